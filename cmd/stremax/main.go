@@ -12,6 +12,7 @@ func main() {
 	// Define command-line flags
 	runCmd := flag.NewFlagSet("run", flag.ExitOnError)
 	runFile := runCmd.String("file", "", "Path to the Stremax-Lang file to run")
+	runStrict := runCmd.Bool("strict", false, "Run in strict mode, as if the file started with pragma strict")
 
 	// Check if a command was provided
 	if len(os.Args) < 2 {
@@ -27,7 +28,7 @@ func main() {
 			fmt.Println("Please provide a file to run with -file flag")
 			os.Exit(1)
 		}
-		runProgram(*runFile)
+		runProgram(*runFile, *runStrict)
 	case "--help", "-h", "help":
 		printHelp()
 	default:
@@ -41,22 +42,21 @@ func printHelp() {
 	fmt.Println("Stremax-Lang Interpreter")
 	fmt.Println("Usage:")
 	fmt.Println("  stremax run -file <filename>  Run a Stremax-Lang program")
+	fmt.Println("        [-strict]               Run it in strict mode")
 	fmt.Println("  stremax help                  Show this help message")
 }
 
-func runProgram(filePath string) {
-	// Read the file
-	source, err := os.ReadFile(filePath)
+func runProgram(filePath string, strict bool) {
+	// Create an interpreter for the file and run the program
+	i, err := interpreter.NewFromFile(filePath)
 	if err != nil {
 		fmt.Printf("Error reading file: %s\n", err)
 		os.Exit(1)
 	}
+	i.SetStrict(strict)
 
-	// Create an interpreter and run the program
-	i := interpreter.New(string(source))
-	err = i.Run()
-	if err != nil {
-		fmt.Printf("Error: %s\n", err)
+	// Run reports its errors itself
+	if err := i.Run(); err != nil {
 		os.Exit(1)
 	}
 }
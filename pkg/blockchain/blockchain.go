@@ -3,18 +3,69 @@ package blockchain
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 )
 
 // Address represents a blockchain address
 type Address string
 
+// ZeroAddress is the address made of zeros, used for "no address"
+const ZeroAddress = Address("0x0000000000000000000000000000000000000000")
+
+// ParseAddress validates an address written as 0x followed by 40 hex
+// digits. The letters may be all lower case or all upper case; an address
+// that mixes cases must carry a valid EIP-55 checksum. The result is in
+// lower case.
+func ParseAddress(s string) (Address, error) {
+	if len(s) != 42 || (s[:2] != "0x" && s[:2] != "0X") {
+		return "", fmt.Errorf("invalid address %q: expected 0x followed by 40 hex digits", s)
+	}
+
+	if _, err := hex.DecodeString(s[2:]); err != nil {
+		return "", fmt.Errorf("invalid address %q: expected 0x followed by 40 hex digits", s)
+	}
+
+	address := Address("0x" + strings.ToLower(s[2:]))
+	digits := s[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) {
+		if checksummed := address.Checksum(); digits != checksummed[2:] {
+			return "", fmt.Errorf("invalid address %q: wrong checksum, expected %s", s, checksummed)
+		}
+	}
+
+	return address, nil
+}
+
+// Checksum returns the address in the mixed-case form of EIP-55, where the
+// case of each letter encodes a bit of the Keccak-256 hash of the address
+func (a Address) Checksum() string {
+	digits := strings.ToLower(strings.TrimPrefix(string(a), "0x"))
+	hash := keccak256([]byte(digits))
+
+	checksummed := []byte(digits)
+	for i, c := range checksummed {
+		// Each hex digit of the address takes its case from the matching
+		// nibble of the hash: upper case when it is 8 or more
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && c <= 'f' && nibble >= 8 {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(checksummed)
+}
+
 // Transaction represents a blockchain transaction
 type Transaction struct {
 	From      Address
 	To        Address
-	Amount    int64
+	Amount    *big.Int
 	Timestamp time.Time
 	Data      []byte
 	Hash      string
@@ -36,6 +87,8 @@ type Blockchain struct {
 	PendingTransactions []Transaction
 	Difficulty          int
 	Contracts           map[Address]*SmartContract
+	Receipts            map[string]*Receipt // receipts of contract calls by transaction hash
+	BlockTime           time.Time           // timestamp of the block being built, read by contracts as the current time
 }
 
 // SmartContract represents a smart contract
@@ -57,6 +110,8 @@ func New() *Blockchain {
 		PendingTransactions: []Transaction{},
 		Difficulty:          4, // Arbitrary difficulty
 		Contracts:           make(map[Address]*SmartContract),
+		Receipts:            make(map[string]*Receipt),
+		BlockTime:           time.Now(),
 	}
 
 	// Create the genesis block
@@ -101,11 +156,11 @@ func (bc *Blockchain) calculateHash(block *Block) string {
 }
 
 // CreateTransaction creates a new transaction
-func (bc *Blockchain) CreateTransaction(from, to Address, amount int64, data []byte) Transaction {
+func (bc *Blockchain) CreateTransaction(from, to Address, amount *big.Int, data []byte) Transaction {
 	tx := Transaction{
 		From:      from,
 		To:        to,
-		Amount:    amount,
+		Amount:    new(big.Int).Set(amount),
 		Timestamp: time.Now(),
 		Data:      data,
 	}
@@ -133,14 +188,14 @@ func (bc *Blockchain) MineBlock(minerAddress Address) *Block {
 	lastBlock := bc.GetLastBlock()
 	newBlock := &Block{
 		Index:        lastBlock.Index + 1,
-		Timestamp:    time.Now(),
+		Timestamp:    bc.BlockTime,
 		Transactions: bc.PendingTransactions,
 		PrevHash:     lastBlock.Hash,
 		Nonce:        0,
 	}
 
 	// Add mining reward
-	bc.CreateTransaction(Address("SYSTEM"), minerAddress, 1, []byte("Mining Reward"))
+	bc.CreateTransaction(Address("SYSTEM"), minerAddress, big.NewInt(1), []byte("Mining Reward"))
 
 	// Mine the block (find a hash with the required difficulty)
 	bc.mineBlockWithProofOfWork(newBlock)
@@ -148,8 +203,9 @@ func (bc *Blockchain) MineBlock(minerAddress Address) *Block {
 	// Add the block to the chain
 	bc.Chain = append(bc.Chain, newBlock)
 
-	// Reset pending transactions
+	// Reset pending transactions and start the next block
 	bc.PendingTransactions = []Transaction{}
+	bc.BlockTime = time.Now()
 
 	return newBlock
 }
@@ -197,16 +253,16 @@ func (bc *Blockchain) IsChainValid() bool {
 }
 
 // GetBalance returns the balance of an address
-func (bc *Blockchain) GetBalance(address Address) int64 {
-	balance := int64(0)
+func (bc *Blockchain) GetBalance(address Address) *big.Int {
+	balance := new(big.Int)
 
 	for _, block := range bc.Chain {
 		for _, tx := range block.Transactions {
 			if tx.From == address {
-				balance -= tx.Amount
+				balance.Sub(balance, tx.Amount)
 			}
 			if tx.To == address {
-				balance += tx.Amount
+				balance.Add(balance, tx.Amount)
 			}
 		}
 	}
@@ -214,13 +270,44 @@ func (bc *Blockchain) GetBalance(address Address) int64 {
 	return balance
 }
 
+// GetPendingBalance returns the balance of an address including the
+// transactions that have not been mined yet
+func (bc *Blockchain) GetPendingBalance(address Address) *big.Int {
+	balance := bc.GetBalance(address)
+
+	for _, tx := range bc.PendingTransactions {
+		if tx.From == address {
+			balance.Sub(balance, tx.Amount)
+		}
+		if tx.To == address {
+			balance.Add(balance, tx.Amount)
+		}
+	}
+
+	return balance
+}
+
+// Transfer creates a transaction moving amount from one address to another.
+// It fails if the sender's pending balance does not cover the amount.
+func (bc *Blockchain) Transfer(from, to Address, amount *big.Int) (Transaction, error) {
+	if amount.Sign() < 0 {
+		return Transaction{}, errors.New("amount must not be negative")
+	}
+
+	if balance := bc.GetPendingBalance(from); balance.Cmp(amount) < 0 {
+		return Transaction{}, fmt.Errorf("insufficient balance: %s has %s, needs %s", from, balance, amount)
+	}
+
+	return bc.CreateTransaction(from, to, amount, nil), nil
+}
+
 // DeployContract deploys a smart contract to the blockchain
 func (bc *Blockchain) DeployContract(owner Address, code []byte) (Address, error) {
 	// Generate a new address for the contract
 	h := sha256.New()
 	h.Write([]byte(fmt.Sprintf("%s%s%d", owner, code, time.Now().UnixNano())))
 	hashed := h.Sum(nil)
-	contractAddress := Address(hex.EncodeToString(hashed)[:40])
+	contractAddress := Address("0x" + hex.EncodeToString(hashed)[:40])
 
 	// Create the contract
 	contract := &SmartContract{
@@ -238,7 +325,7 @@ func (bc *Blockchain) DeployContract(owner Address, code []byte) (Address, error
 	bc.Contracts[contractAddress] = contract
 
 	// Create a deployment transaction
-	bc.CreateTransaction(owner, contractAddress, 0, code)
+	bc.CreateTransaction(owner, contractAddress, new(big.Int), code)
 
 	return contractAddress, nil
 }
@@ -271,7 +358,7 @@ func (bc *Blockchain) CallContract(from Address, to Address, functionName string
 
 	// Create a transaction for the function call
 	data := []byte(fmt.Sprintf("%s(%v)", functionName, args))
-	bc.CreateTransaction(from, to, 0, data)
+	bc.CreateTransaction(from, to, new(big.Int), data)
 
 	return result, nil
 }
@@ -0,0 +1,160 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestBalancesBeyondInt64(t *testing.T) {
+	bc := New()
+	bc.Difficulty = 1
+
+	// One billion tokens with 18 decimals
+	supply, _ := new(big.Int).SetString("1000000000000000000000000000", 10)
+	half := new(big.Int).Rsh(supply, 1)
+
+	bc.CreateTransaction(Address("SYSTEM"), Address("alice"), supply, nil)
+	bc.CreateTransaction(Address("alice"), Address("bob"), half, nil)
+	bc.MineBlock(Address("miner"))
+
+	if got := bc.GetBalance(Address("alice")); got.Cmp(half) != 0 {
+		t.Errorf("wrong balance for alice. got=%s, want=%s", got, half)
+	}
+	if got := bc.GetBalance(Address("bob")); got.Cmp(half) != 0 {
+		t.Errorf("wrong balance for bob. got=%s, want=%s", got, half)
+	}
+
+	// Mutating the amount after creating a transaction must not change history
+	supply.SetInt64(0)
+	if got := bc.GetBalance(Address("alice")); got.Cmp(half) != 0 {
+		t.Errorf("balance changed after mutating amount. got=%s, want=%s", got, half)
+	}
+}
+
+func TestTransferChecksPendingBalance(t *testing.T) {
+	bc := New()
+
+	bc.CreateTransaction(Address("SYSTEM"), Address("alice"), big.NewInt(100), nil)
+
+	if _, err := bc.Transfer(Address("alice"), Address("bob"), big.NewInt(60)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := bc.GetPendingBalance(Address("bob")); got.Cmp(big.NewInt(60)) != 0 {
+		t.Errorf("wrong pending balance for bob. got=%s, want=60", got)
+	}
+	if got := bc.GetBalance(Address("bob")); got.Sign() != 0 {
+		t.Errorf("unmined transfer counted in balance. got=%s, want=0", got)
+	}
+
+	// Only 40 remain after the first transfer
+	if _, err := bc.Transfer(Address("alice"), Address("bob"), big.NewInt(60)); err == nil {
+		t.Errorf("expected insufficient balance error")
+	}
+	if _, err := bc.Transfer(Address("alice"), Address("bob"), big.NewInt(-1)); err == nil {
+		t.Errorf("expected error for negative amount")
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Address
+		valid    bool
+	}{
+		{"0x0000000000000000000000000000000000000000", ZeroAddress, true},
+		{"0xabCDeF0123456789AbcdEf0123456789aBCDEF01", "0xabcdef0123456789abcdef0123456789abcdef01", true},
+		{"0xABCDEF0123456789ABCDEF0123456789ABCDEF01", "0xabcdef0123456789abcdef0123456789abcdef01", true},
+		{"0xAbCdEf0123456789abcdef0123456789ABCDEF01", "", false}, // mixed case with a wrong checksum
+		{"0X00000000000000000000000000000000000000ff", "0x00000000000000000000000000000000000000ff", true},
+		{"0x123", "", false},
+		{"00000000000000000000000000000000000000000000", "", false},
+		{"0xzz00000000000000000000000000000000000000", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		address, err := ParseAddress(tt.input)
+		if (err == nil) != tt.valid {
+			t.Errorf("wrong validity for %q. got err=%v", tt.input, err)
+			continue
+		}
+		if address != tt.expected {
+			t.Errorf("wrong address for %q. got=%s, want=%s", tt.input, address, tt.expected)
+		}
+	}
+}
+
+func TestDeployedContractAddressIsValid(t *testing.T) {
+	bc := New()
+
+	address, err := bc.DeployContract(Address("alice"), []byte("contract C {}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := ParseAddress(string(address)); err != nil {
+		t.Errorf("deployed contract has an invalid address: %v", err)
+	}
+}
+
+func TestCallReceipts(t *testing.T) {
+	bc := New()
+
+	ok := bc.RecordCall(Address("alice"), Address("vault"), "deposit(10)", nil)
+	if ok.Status != StatusSucceeded {
+		t.Errorf("wrong status. got=%s, want=succeeded", ok.Status)
+	}
+	if failure, err := ok.Failure(); failure != nil || err != nil {
+		t.Errorf("succeeded call has a failure: %v, %v", failure, err)
+	}
+
+	// Integers beyond 64 bits keep their precision
+	failure := &Failure{Error: "InsufficientBalance", Args: []FailureArg{
+		{Name: "needed", Type: "Uint256", Value: "1000000000000000000000"},
+		{Name: "available", Type: "Uint256", Value: "5"},
+	}}
+	reverted := bc.RecordCall(Address("alice"), Address("vault"), "withdraw(1000000000000000000000)", failure)
+	if reverted.Status != StatusReverted {
+		t.Errorf("wrong status. got=%s, want=reverted", reverted.Status)
+	}
+
+	receipt, found := bc.GetReceipt(reverted.TxHash)
+	if !found {
+		t.Fatalf("receipt not found by transaction hash")
+	}
+	decoded, err := receipt.Failure()
+	if err != nil {
+		t.Fatalf("cannot decode revert data: %v", err)
+	}
+	if decoded.String() != "InsufficientBalance(needed: 1000000000000000000000, available: 5)" {
+		t.Errorf("wrong failure. got=%s", decoded)
+	}
+	if needed, _ := decoded.Arg("needed"); needed != "1000000000000000000000" {
+		t.Errorf("wrong needed argument. got=%s", needed)
+	}
+	if len(bc.PendingTransactions) != 2 {
+		t.Errorf("expected a transaction per call, got %d", len(bc.PendingTransactions))
+	}
+
+	for _, data := range []string{"", "not json", `{"args": []}`} {
+		if _, err := DecodeFailure([]byte(data)); err == nil {
+			t.Errorf("expected error decoding %q", data)
+		}
+	}
+}
+
+func TestMinedBlockUsesBlockTime(t *testing.T) {
+	bc := New()
+	bc.Difficulty = 1
+
+	pinned := time.Unix(1700000000, 0)
+	bc.BlockTime = pinned
+	block := bc.MineBlock(Address("miner"))
+
+	if !block.Timestamp.Equal(pinned) {
+		t.Errorf("wrong block timestamp. got=%s, want=%s", block.Timestamp, pinned)
+	}
+	if !bc.BlockTime.After(pinned) {
+		t.Errorf("expected the next block to start at the current time, got %s", bc.BlockTime)
+	}
+}
@@ -3,6 +3,7 @@ package blockchain
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
 )
 
@@ -45,7 +46,7 @@ type ParameterDefinition struct {
 type ContractContext struct {
 	Contract   *Contract
 	Sender     Address
-	Value      int64
+	Value      *big.Int
 	Blockchain *Blockchain
 }
 
@@ -76,7 +77,7 @@ func (c *Contract) RegisterEvent(name string, params []ParameterDefinition) {
 }
 
 // Call calls a function on the contract
-func (c *Contract) Call(sender Address, functionName string, value int64, args ...interface{}) (interface{}, error) {
+func (c *Contract) Call(sender Address, functionName string, value *big.Int, args ...interface{}) (interface{}, error) {
 	fn, exists := c.Functions[functionName]
 	if !exists {
 		return nil, fmt.Errorf("function %s does not exist", functionName)
@@ -128,8 +129,8 @@ func (ctx *ContractContext) Require(condition bool, message string) error {
 }
 
 // Transfer transfers tokens from the contract to an address
-func (ctx *ContractContext) Transfer(to Address, amount int64) error {
-	if amount <= 0 {
+func (ctx *ContractContext) Transfer(to Address, amount *big.Int) error {
+	if amount.Sign() <= 0 {
 		return errors.New("amount must be positive")
 	}
 
@@ -150,7 +151,7 @@ func (ctx *ContractContext) GetSender() Address {
 }
 
 // GetValue returns the value sent with the current transaction
-func (ctx *ContractContext) GetValue() int64 {
+func (ctx *ContractContext) GetValue() *big.Int {
 	return ctx.Value
 }
 
@@ -0,0 +1,98 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// keccakRate is the number of bytes of input absorbed per permutation by
+// Keccak-256
+const keccakRate = 136
+
+// keccakRoundConstants are the constants mixed into the state in each round
+// of the Keccak-f[1600] permutation
+var keccakRoundConstants = [24]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808A, 0x8000000080008000,
+	0x000000000000808B, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008A, 0x0000000000000088, 0x0000000080008009, 0x000000008000000A,
+	0x000000008000808B, 0x800000000000008B, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800A, 0x800000008000000A,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// keccakRotations are the rotation offsets of the lanes of the state,
+// indexed by x + 5*y
+var keccakRotations = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// keccak256 returns the Keccak-256 hash of data. This is the hash Ethereum
+// uses, which pads its input differently from the SHA3-256 standard.
+func keccak256(data []byte) [32]byte {
+	return keccak(data, 0x01)
+}
+
+// keccak hashes data with the Keccak sponge, using the given domain
+// padding byte: 0x01 for Keccak-256, 0x06 for SHA3-256
+func keccak(data []byte, domain byte) [32]byte {
+	padded := make([]byte, len(data), len(data)+keccakRate)
+	copy(padded, data)
+	padded = append(padded, domain)
+	for len(padded)%keccakRate != 0 {
+		padded = append(padded, 0)
+	}
+	padded[len(padded)-1] |= 0x80
+
+	var state [25]uint64
+	for offset := 0; offset < len(padded); offset += keccakRate {
+		for lane := 0; lane < keccakRate/8; lane++ {
+			state[lane] ^= binary.LittleEndian.Uint64(padded[offset+8*lane:])
+		}
+		keccakF(&state)
+	}
+
+	var sum [32]byte
+	for lane := 0; lane < 4; lane++ {
+		binary.LittleEndian.PutUint64(sum[8*lane:], state[lane])
+	}
+	return sum
+}
+
+// keccakF applies the Keccak-f[1600] permutation to the state
+func keccakF(a *[25]uint64) {
+	for round := 0; round < 24; round++ {
+		// θ: mix each column's parity into the neighbouring columns
+		var c [5]uint64
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d := c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+			for y := 0; y < 25; y += 5 {
+				a[y+x] ^= d
+			}
+		}
+
+		// ρ and π: rotate each lane and move it to its new position
+		var b [25]uint64
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], keccakRotations[x+5*y])
+			}
+		}
+
+		// χ: combine each lane with the next two in its row
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[y+x] = b[y+x] ^ (^b[y+(x+1)%5] & b[y+(x+2)%5])
+			}
+		}
+
+		// ι: break the symmetry between rounds
+		a[0] ^= keccakRoundConstants[round]
+	}
+}
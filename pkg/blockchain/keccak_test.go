@@ -0,0 +1,48 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestKeccak256(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+	}
+
+	for _, tt := range tests {
+		sum := keccak256([]byte(tt.input))
+		if got := hex.EncodeToString(sum[:]); got != tt.expected {
+			t.Errorf("wrong hash for %q. got=%s, want=%s", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestAddressChecksum(t *testing.T) {
+	// Test vectors from EIP-55
+	tests := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0x27b1fdb04752bbc536007a920d24acb045561c26",
+	}
+
+	for _, expected := range tests {
+		address := Address(strings.ToLower(expected))
+		if got := address.Checksum(); got != expected {
+			t.Errorf("wrong checksum for %s. got=%s, want=%s", address, got, expected)
+		}
+		if _, err := ParseAddress(expected); err != nil {
+			t.Errorf("checksummed address %s rejected: %v", expected, err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ReceiptStatus tells whether the contract call a receipt records succeeded
+type ReceiptStatus int
+
+const (
+	// StatusSucceeded is the status of a call that completed
+	StatusSucceeded ReceiptStatus = iota
+	// StatusReverted is the status of a call that failed and was rolled back
+	StatusReverted
+)
+
+// String returns the name of the status
+func (s ReceiptStatus) String() string {
+	if s == StatusReverted {
+		return "reverted"
+	}
+	return "succeeded"
+}
+
+// Receipt records the outcome of a contract call
+type Receipt struct {
+	TxHash     string // the hash of the transaction recording the call
+	Status     ReceiptStatus
+	RevertData []byte // the encoded Failure of a reverted call
+}
+
+// Failure decodes the error a reverted call failed with. It returns nil for
+// a call that succeeded.
+func (r *Receipt) Failure() (*Failure, error) {
+	if r.Status != StatusReverted {
+		return nil, nil
+	}
+	return DecodeFailure(r.RevertData)
+}
+
+// Failure describes the error a contract call reverted with: a custom error
+// declared by the contract, Error for a failed require or a revert with a
+// message, or Panic for a failed assert or another runtime failure
+type Failure struct {
+	Error string       `json:"error"`
+	Args  []FailureArg `json:"args"`
+}
+
+// FailureArg is one field of the error a call reverted with. Values are
+// written as they print in Stremax-Lang, so integers keep their full
+// precision.
+type FailureArg struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Arg returns the value of the named field of the error
+func (f *Failure) Arg(name string) (string, bool) {
+	for _, arg := range f.Args {
+		if arg.Name == name {
+			return arg.Value, true
+		}
+	}
+	return "", false
+}
+
+// String returns the failure as it is written in Stremax-Lang, e.g.
+// InsufficientBalance(needed: 10, available: 5)
+func (f *Failure) String() string {
+	args := []string{}
+	for _, arg := range f.Args {
+		args = append(args, fmt.Sprintf("%s: %s", arg.Name, arg.Value))
+	}
+	return fmt.Sprintf("%s(%s)", f.Error, strings.Join(args, ", "))
+}
+
+// Encode encodes the failure as the revert data of a receipt
+func (f *Failure) Encode() []byte {
+	data, err := json.Marshal(f)
+	if err != nil {
+		// A Failure holds only strings, which always encode
+		panic(err)
+	}
+	return data
+}
+
+// DecodeFailure decodes the revert data of a receipt
+func DecodeFailure(data []byte) (*Failure, error) {
+	var f Failure
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid revert data: %v", err)
+	}
+	if f.Error == "" {
+		return nil, fmt.Errorf("invalid revert data: no error name")
+	}
+	return &f, nil
+}
+
+// RecordCall records a call of a contract function as a transaction and
+// returns its receipt. A nil failure records a call that succeeded.
+func (bc *Blockchain) RecordCall(from, to Address, call string, failure *Failure) *Receipt {
+	tx := bc.CreateTransaction(from, to, new(big.Int), []byte(call))
+
+	receipt := &Receipt{TxHash: tx.Hash, Status: StatusSucceeded}
+	if failure != nil {
+		receipt.Status = StatusReverted
+		receipt.RevertData = failure.Encode()
+	}
+
+	bc.Receipts[tx.Hash] = receipt
+	return receipt
+}
+
+// GetReceipt retrieves the receipt of a contract call by its transaction hash
+func (bc *Blockchain) GetReceipt(txHash string) (*Receipt, bool) {
+	receipt, ok := bc.Receipts[txHash]
+	return receipt, ok
+}
@@ -0,0 +1,684 @@
+// Package checker implements static type checking for Stremax-Lang.
+// It runs over the syntax tree produced by the parser before the program is
+// evaluated, so that type errors are reported before any contract is
+// deployed instead of partway through a transaction.
+//
+// Types that cannot be determined statically, such as the results of
+// untyped functions, are left unknown and checked by the interpreter when
+// the program runs.
+package checker
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+)
+
+// Checker holds the state of a type check: the bindings in scope and the
+// errors found so far
+type Checker struct {
+	errors []*errors.Error
+	scopes []map[string]*binding
+
+	// returnType is the declared return type of the function being
+	// checked; returns are unchecked when it is nil
+	returnType *Type
+	funcName   string
+
+	// immutables tells whether the code being checked may assign to
+	// immutable state fields
+	immutables immutableAccess
+
+	// strict is set for programs that start with pragma strict, in which
+	// values are not converted to strings by + and conditions must be Bool
+	strict bool
+
+	// importer loads imported modules; without one their bindings are
+	// unknown
+	importer Importer
+}
+
+// binding is a name in scope: its type, which is nil if unknown, and how it
+// may be assigned
+type binding struct {
+	typ  *Type
+	kind bindingKind
+}
+
+// bindingKind describes how a name declared in the program may be assigned
+type bindingKind int
+
+const (
+	variableBinding  bindingKind = iota
+	constantBinding              // declared with const
+	immutableBinding             // an immutable state field
+)
+
+// immutableAccess tells whether the code being checked may assign to
+// immutable state fields
+type immutableAccess int
+
+const (
+	immutableUnknown   immutableAccess = iota // top-level code and function literals, which may run anywhere
+	immutableForbidden                        // functions
+	immutableAllowed                          // the constructor
+)
+
+// Check type-checks a parsed program and returns the type errors it finds.
+//
+// Parameters:
+//   - program: The program produced by the parser
+//
+// Returns:
+//   - The type errors found, each positioned in the source; empty if the
+//     program is well typed
+func Check(program *parser.Program) []*errors.Error {
+	c := New()
+	c.Check(program)
+	return c.Errors()
+}
+
+// New creates a checker whose global scope holds the built-in error types
+func New() *Checker {
+	c := &Checker{}
+	c.push()
+	c.declare("Error", NewErrorType("Error", []string{"message"}, []*Type{String}))
+	c.declare("Panic", NewErrorType("Panic", []string{"message"}, []*Type{String}))
+	return c
+}
+
+// Check type-checks the statements of a program in the checker's global
+// scope
+func (c *Checker) Check(program *parser.Program) {
+	if program.Strict() {
+		c.strict = true
+	}
+	c.statements(program.Statements)
+}
+
+// SetStrict checks programs in strict mode, as if they started with
+// pragma strict
+func (c *Checker) SetStrict(strict bool) {
+	c.strict = strict
+}
+
+// Errors returns the type errors found so far
+func (c *Checker) Errors() []*errors.Error {
+	return c.errors
+}
+
+// errorf records a type error positioned at the given token
+func (c *Checker) errorf(tok parser.Token, format string, args ...interface{}) {
+	c.errors = append(c.errors, errors.NewTypeError(fmt.Sprintf(format, args...), tok.Line, tok.Column, ""))
+}
+
+// mismatch records a value whose type does not match the expected type
+func (c *Checker) mismatch(expr parser.Expression, context string, expected, actual *Type) {
+	if context != "" {
+		context += ": "
+	}
+	c.errorf(position(expr), "Type mismatch: %sexpected %s, got %s", context, expected, actual)
+}
+
+// wideLiteral records an integer literal too large for Int used where its
+// type is not declared unsigned
+func (c *Checker) wideLiteral(expr parser.Expression) {
+	lit := expr.(*parser.IntegerLiteral)
+	c.errorf(lit.Token, "Integer literal %s out of range for Int; declare its type as Uint256", lit.Value)
+}
+
+// value checks an expression whose value is stored in a binding of the
+// target type, which makes a literal too large for Int a Uint256 if the
+// target is unsigned
+func (c *Checker) value(expr parser.Expression, target *Type) *Type {
+	if isWideLiteral(expr) && isUnsigned(target) {
+		return sizedInts["Uint256"]
+	}
+	return c.expression(expr)
+}
+
+// expect checks that an expression of the given type can be stored in a
+// binding of the expected type
+func (c *Checker) expect(expr parser.Expression, context string, expected, actual *Type) {
+	if !assignable(expected, actual) {
+		c.mismatch(expr, context, expected, actual)
+	}
+}
+
+func (c *Checker) push() {
+	c.scopes = append(c.scopes, make(map[string]*binding))
+}
+
+func (c *Checker) pop() {
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+// declare binds a name in the innermost scope. A nil type declares a
+// binding whose type is unknown, which hides any outer binding of the name.
+// The name _ discards a value and is never bound.
+func (c *Checker) declare(name string, typ *Type) {
+	if name == "_" {
+		return
+	}
+	c.scopes[len(c.scopes)-1][name] = &binding{typ: typ}
+}
+
+// bind declares a name written in the program, such as the name of a let
+// or a function. A constant cannot be redeclared in the scope that declared
+// it.
+func (c *Checker) bind(name *parser.Identifier, typ *Type, kind bindingKind) {
+	if name.Value == "_" {
+		return
+	}
+
+	scope := c.scopes[len(c.scopes)-1]
+	if existing, ok := scope[name.Value]; ok && existing.kind == constantBinding {
+		c.errorf(name.Token, "Cannot redeclare constant %s", name.Value)
+	}
+	scope[name.Value] = &binding{typ: typ, kind: kind}
+}
+
+// lookup finds the type of the binding a name resolves to. Names that are
+// not declared in the program, such as built-in functions, are unknown.
+func (c *Checker) lookup(name string) (*Type, bool) {
+	if b := c.binding(name); b != nil {
+		return b.typ, true
+	}
+	return nil, false
+}
+
+// binding finds the binding a name resolves to, or nil if the name is not
+// declared in the program
+func (c *Checker) binding(name string) *binding {
+	for idx := len(c.scopes) - 1; idx >= 0; idx-- {
+		if b, ok := c.scopes[idx][name]; ok {
+			return b
+		}
+	}
+	return nil
+}
+
+// resolve converts a type annotation into a type. Names that are not types
+// known to the checker resolve to nil, as the interpreter does not check them.
+func (c *Checker) resolve(te *parser.TypeExpression) *Type {
+	if te == nil {
+		return nil
+	}
+
+	if te.Optional {
+		plain := *te
+		plain.Optional = false
+		return NewOptional(c.resolve(&plain))
+	}
+
+	switch te.Type {
+	case "Int":
+		return Int
+	case "String":
+		return String
+	case "Bool":
+		return Bool
+	case "Address":
+		return Address
+	case "Map":
+		return NewMap(c.resolve(te.KeyType), c.resolve(te.ValueType))
+	case "Array":
+		if te.ElementType != nil {
+			return NewArray(c.resolve(te.ElementType))
+		}
+	case "fn":
+		params := []*Type{}
+		for _, param := range te.ParamTypes {
+			params = append(params, c.resolve(param))
+		}
+		return NewFunction(params, c.resolve(te.ReturnType))
+	case "Tuple":
+		elems := []*Type{}
+		for _, el := range te.ElementTypes {
+			elems = append(elems, c.resolve(el))
+		}
+		return NewTuple(elems)
+	}
+
+	if sized, ok := sizedInts[te.Type]; ok {
+		return sized
+	}
+
+	if named, ok := c.lookup(te.Type); ok && named != nil {
+		switch named.Kind {
+		case StructTypeKind:
+			return &Type{Kind: StructKind, Name: named.Name, Decl: named.Decl}
+		case EnumTypeKind:
+			return &Type{Kind: EnumKind, Name: named.Name, Decl: named.Decl}
+		case ContractKind:
+			return &Type{Kind: InstanceKind, Name: named.Name, Decl: named.Decl}
+		case ErrorTypeKind:
+			return named.Decl.Init.Result
+		case TypeParamKind:
+			return named
+		}
+	}
+
+	return nil
+}
+
+// signature builds the type of a function from its declaration. The type
+// parameters of a generic function are in scope in its parameter and
+// return types.
+func (c *Checker) signature(token parser.Token, typeParams []*parser.Identifier, params []*parser.ParameterStatement, returnType *parser.TypeExpression) *Type {
+	c.push()
+	defer c.pop()
+
+	generics := []*Type{}
+	for _, tp := range typeParams {
+		typ := &Type{Kind: TypeParamKind, Name: tp.Value}
+		c.declare(tp.Value, typ)
+		generics = append(generics, typ)
+	}
+
+	types := []*Type{}
+	for _, param := range params {
+		types = append(types, c.resolve(param.Type))
+	}
+
+	sig := NewFunction(types, c.resolve(returnType))
+	sig.Sig = newSignature(token, params)
+	if len(generics) != 0 {
+		sig.TypeParams = generics
+	}
+	return sig
+}
+
+// newSignature describes the parameters of a declared function
+func newSignature(token parser.Token, params []*parser.ParameterStatement) *Signature {
+	sig := &Signature{Names: []string{}, Token: token}
+	for _, param := range params {
+		sig.Names = append(sig.Names, param.Name.Value)
+		if param.Default != nil {
+			sig.Defaults++
+		}
+		sig.Variadic = param.Variadic
+	}
+	return sig
+}
+
+func (c *Checker) statements(stmts []parser.Statement) *Type {
+	var last *Type
+	for _, stmt := range stmts {
+		last = c.statement(stmt)
+	}
+	return last
+}
+
+// block checks a block in a new scope and returns the type of its value,
+// the value of its last statement
+func (c *Checker) block(block *parser.BlockStatement) *Type {
+	if block == nil {
+		return nil
+	}
+	c.push()
+	defer c.pop()
+	return c.statements(block.Statements)
+}
+
+// function checks the body of a function with its parameters in scope.
+// access tells whether the body may assign to immutable state fields.
+func (c *Checker) function(name string, params []*parser.ParameterStatement, sig *Type, body *parser.BlockStatement, access immutableAccess) {
+	previousType, previousName, previousAccess := c.returnType, c.funcName, c.immutables
+	c.returnType, c.funcName, c.immutables = sig.Result, name, access
+	defer func() { c.returnType, c.funcName, c.immutables = previousType, previousName, previousAccess }()
+
+	c.push()
+	defer c.pop()
+	for _, tp := range sig.TypeParams {
+		c.declare(tp.Name, tp)
+	}
+	c.parameters(name, params, sig.Params)
+	c.block(body)
+}
+
+// parameters declares the parameters of a function or error in order,
+// checking their default values, which may refer to the parameters before
+// them. A variadic parameter holds an array of the arguments it collects.
+func (c *Checker) parameters(name string, params []*parser.ParameterStatement, types []*Type) {
+	for idx, param := range params {
+		if param.Default != nil {
+			value := c.expression(param.Default)
+			c.expect(param.Default, fmt.Sprintf("default value of %s in %s", param.Name.Value, name), types[idx], value)
+		}
+		if param.Variadic {
+			c.declare(param.Name.Value, NewArray(types[idx]))
+		} else {
+			c.declare(param.Name.Value, types[idx])
+		}
+	}
+}
+
+// statement checks a statement and returns the type of its value
+func (c *Checker) statement(stmt parser.Statement) *Type {
+	switch s := stmt.(type) {
+	case *parser.LetStatement:
+		c.letStatement(s)
+	case *parser.ExpressionStatement:
+		return c.expression(s.Expression)
+	case *parser.ReturnStatement:
+		c.returnStatement(s)
+	case *parser.RequireStatement:
+		c.condition(s.Condition, "require")
+		c.expression(s.Message)
+	case *parser.EmitStatement:
+		for _, arg := range s.Arguments {
+			c.expression(arg)
+		}
+	case *parser.RevertStatement:
+		value := c.expression(s.Error)
+		if value != nil && value != String && value.Kind != ErrorKind {
+			c.errorf(position(s.Error), "Cannot revert with %s; use an error such as Unauthorized() or a message", value)
+		}
+	case *parser.AssertStatement:
+		c.condition(s.Condition, "assert")
+		c.expression(s.Message)
+	case *parser.TryStatement:
+		c.tryStatement(s)
+	case *parser.WhileStatement:
+		c.condition(s.Condition, "while")
+		c.block(s.Body)
+	case *parser.ForStatement:
+		c.push()
+		if s.Init != nil {
+			c.statement(s.Init)
+		}
+		c.condition(s.Condition, "for")
+		c.block(s.Body)
+		c.expression(s.Update)
+		c.pop()
+	case *parser.ForInStatement:
+		c.forInStatement(s)
+	case *parser.BlockStatement:
+		return c.block(s)
+	case *parser.FunctionStatement:
+		sig := c.signature(s.Token, s.TypeParameters, s.Parameters, s.ReturnType)
+		// The function is bound before its body runs, so it may call itself
+		c.bind(s.Name, sig, variableBinding)
+		c.function(s.Name.Value, s.Parameters, sig, s.Body, immutableForbidden)
+	case *parser.ContractStatement:
+		c.contractStatement(s)
+	case *parser.StructStatement:
+		c.structStatement(s)
+	case *parser.ErrorStatement:
+		c.errorStatement(s)
+	case *parser.EnumStatement:
+		decl := &Declaration{Name: s.Name.Value}
+		for _, v := range s.Variants {
+			decl.Variants = append(decl.Variants, v.Value)
+		}
+		c.bind(s.Name, &Type{Kind: EnumTypeKind, Name: s.Name.Value, Decl: decl}, variableBinding)
+	case *parser.ImportStatement:
+		c.importStatement(s)
+	}
+	return nil
+}
+
+// letStatement checks a declaration. Without an annotation the binding
+// takes the type of its initial value.
+func (c *Checker) letStatement(stmt *parser.LetStatement) {
+	var value *Type
+	if stmt.Type != nil {
+		declared := c.resolve(stmt.Type)
+		c.expect(stmt.Value, "", declared, c.value(stmt.Value, declared))
+		value = declared
+	} else if value = c.expression(stmt.Value); value == Null {
+		// A binding initialized to null may later hold a value of any type
+		value = nil
+	}
+
+	kind := variableBinding
+	if stmt.Constant {
+		kind = constantBinding
+	}
+
+	if stmt.Pattern != nil {
+		c.destructure(stmt.Pattern, value, kind)
+		return
+	}
+	c.bind(stmt.Name, value, kind)
+}
+
+// destructure declares the names of a destructuring pattern, typing them
+// from the elements of a tuple or an array or the fields of a struct
+func (c *Checker) destructure(pattern *parser.DestructuringPattern, value *Type, kind bindingKind) {
+	types := make([]*Type, len(pattern.Names))
+
+	switch {
+	case isOpaque(value):
+	case pattern.Token.Literal == "(" && value.Kind == TupleKind:
+		if len(value.Elems) != len(pattern.Names) {
+			c.errorf(pattern.Token, "Cannot destructure %s as %s", value, pattern)
+			break
+		}
+		copy(types, value.Elems)
+	case pattern.Token.Literal == "[" && value.Kind == ArrayKind:
+		for idx := range types {
+			types[idx] = value.Elem
+		}
+	case pattern.Token.Literal == "{" && value.Kind == StructKind:
+		for idx, field := range pattern.Fields {
+			typ, ok := value.Decl.Fields[field.Value]
+			if !ok {
+				c.errorf(field.Token, "Struct %s has no field %s", value.Name, field.Value)
+			}
+			types[idx] = typ
+		}
+	default:
+		c.errorf(pattern.Token, "Cannot destructure %s as %s", value, pattern)
+	}
+
+	for idx, name := range pattern.Names {
+		c.bind(name, types[idx], kind)
+	}
+}
+
+// returnStatement checks a returned value against the declared return type
+// of the enclosing function
+func (c *Checker) returnStatement(stmt *parser.ReturnStatement) {
+	if stmt.ReturnValue == nil {
+		return
+	}
+
+	value := c.value(stmt.ReturnValue, c.returnType)
+	c.expect(stmt.ReturnValue, fmt.Sprintf("return value of %s", c.funcName), c.returnType, value)
+}
+
+// forInStatement checks a for-in loop, typing its variables from the
+// collection: arrays yield elements or (index, element), maps yield keys
+// or (key, value)
+func (c *Checker) forInStatement(stmt *parser.ForInStatement) {
+	iterable := c.expression(stmt.Iterable)
+
+	var first, second *Type
+	if !isOpaque(iterable) {
+		switch iterable.Kind {
+		case MapKind:
+			first, second = iterable.Key, iterable.Value
+		case ArrayKind:
+			first, second = iterable.Elem, iterable.Elem
+			if len(stmt.Variables) == 2 {
+				first = Int
+			}
+		default:
+			c.errorf(stmt.Token, "Cannot iterate over %s", iterable)
+		}
+	}
+
+	c.push()
+	defer c.pop()
+	c.declare(stmt.Variables[0].Value, first)
+	if len(stmt.Variables) == 2 {
+		c.declare(stmt.Variables[1].Value, second)
+	}
+	c.block(stmt.Body)
+}
+
+// structStatement declares a struct type
+func (c *Checker) structStatement(stmt *parser.StructStatement) {
+	decl := &Declaration{Name: stmt.Name.Value, Fields: make(map[string]*Type)}
+	typ := &Type{Kind: StructTypeKind, Name: stmt.Name.Value, Decl: decl}
+	c.bind(stmt.Name, typ, variableBinding)
+
+	for _, field := range stmt.Fields {
+		decl.Fields[field.Name.Value] = c.resolve(field.Type)
+		decl.Order = append(decl.Order, field.Name.Value)
+	}
+}
+
+// errorStatement declares an error type
+func (c *Checker) errorStatement(stmt *parser.ErrorStatement) {
+	fields := []string{}
+	types := []*Type{}
+	for _, field := range stmt.Fields {
+		fields = append(fields, field.Name.Value)
+		types = append(types, c.resolve(field.Type))
+	}
+
+	// Default values are checked with the fields before them in scope
+	c.push()
+	c.parameters(stmt.Name.Value, stmt.Fields, types)
+	c.pop()
+
+	errorType := NewErrorType(stmt.Name.Value, fields, types)
+	errorType.Decl.Init.Sig = newSignature(stmt.Token, stmt.Fields)
+	c.bind(stmt.Name, errorType, variableBinding)
+}
+
+// tryStatement checks a try statement. The result of the call is in scope
+// in the block run when it succeeds, and the fields of the caught error in
+// each catch clause.
+func (c *Checker) tryStatement(stmt *parser.TryStatement) {
+	var callee *Type
+	if dot, ok := stmt.Call.Function.(*parser.DotExpression); ok {
+		left := c.expression(dot.Left)
+		if left != nil && left.Kind != InstanceKind {
+			c.errorf(stmt.Call.Token, "try requires a contract call or deployment, got a call of %s.%s", left, dot.Right)
+		}
+		callee = c.access(dot, left)
+	} else {
+		callee = c.expression(stmt.Call.Function)
+	}
+	result := c.call(stmt.Call, callee)
+
+	c.push()
+	if stmt.Result != nil {
+		c.declare(stmt.Result.Value, result)
+	}
+	c.block(stmt.Body)
+	c.pop()
+
+	for _, clause := range stmt.Catches {
+		c.catchClause(clause)
+	}
+}
+
+// catchClause checks a catch clause with its parameters in scope. The
+// parameters of a clause naming an error take the types of its fields.
+func (c *Checker) catchClause(clause *parser.CatchClause) {
+	c.push()
+	defer c.pop()
+
+	var fields []*Type
+	if clause.ErrorName != nil {
+		named, _ := c.lookup(clause.ErrorName.Value)
+		switch {
+		case named == nil:
+			// Unknown names are left to the interpreter
+		case named.Kind != ErrorTypeKind:
+			c.errorf(clause.ErrorName.Token, "%s is not an error type", clause.ErrorName.Value)
+		case len(clause.Parameters) != len(named.Decl.Order):
+			c.errorf(clause.Token, "Wrong number of parameters to catch %s: expected %d, got %d",
+				named.Name, len(named.Decl.Order), len(clause.Parameters))
+		default:
+			fields = named.Decl.Init.Params
+		}
+	}
+
+	for idx, param := range clause.Parameters {
+		var typ *Type
+		if fields != nil {
+			typ = fields[idx]
+		}
+		c.declare(param.Value, typ)
+	}
+	c.block(clause.Body)
+}
+
+// contractStatement declares a contract and checks its body. State fields
+// and functions share one scope, which every function sees; functions are
+// bound before any of them runs, so they may call each other in any order.
+func (c *Checker) contractStatement(stmt *parser.ContractStatement) {
+	decl := &Declaration{Name: stmt.Name.Value, Fields: make(map[string]*Type)}
+	c.bind(stmt.Name, &Type{Kind: ContractKind, Name: stmt.Name.Value, Decl: decl}, variableBinding)
+
+	c.push()
+	defer c.pop()
+
+	for _, s := range stmt.Body.Statements {
+		switch s := s.(type) {
+		case *parser.StructStatement, *parser.EnumStatement, *parser.ErrorStatement:
+			c.statement(s)
+		}
+	}
+
+	if stmt.StateBlock != nil {
+		for _, field := range stmt.StateBlock.Fields {
+			typ := c.resolve(field.Type)
+			if field.Value != nil {
+				c.expect(field.Value, "", typ, c.value(field.Value, typ))
+			}
+			switch {
+			case field.Constant:
+				c.bind(field.Name, typ, constantBinding)
+			case field.Immutable:
+				c.bind(field.Name, typ, immutableBinding)
+			default:
+				c.bind(field.Name, typ, variableBinding)
+			}
+			decl.Fields[field.Name.Value] = typ
+			decl.Order = append(decl.Order, field.Name.Value)
+		}
+	}
+
+	type method struct {
+		name   string
+		params []*parser.ParameterStatement
+		sig    *Type
+		body   *parser.BlockStatement
+		access immutableAccess
+	}
+	methods := []method{}
+	decl.Init = NewFunction([]*Type{}, nil)
+	decl.Init.Sig = newSignature(stmt.Token, nil)
+
+	for _, s := range stmt.Body.Statements {
+		switch s := s.(type) {
+		case *parser.FunctionStatement:
+			sig := c.signature(s.Token, s.TypeParameters, s.Parameters, s.ReturnType)
+			if _, ok := decl.Fields[s.Name.Value]; ok {
+				c.errorf(s.Name.Token, "Duplicate member %s in contract %s", s.Name.Value, stmt.Name.Value)
+			}
+			c.bind(s.Name, sig, variableBinding)
+			decl.Fields[s.Name.Value] = sig
+			methods = append(methods, method{s.Name.Value, s.Parameters, sig, s.Body, immutableForbidden})
+		case *parser.ConstructorStatement:
+			// Only the constructor may assign to immutable state fields
+			decl.Init = c.signature(s.Token, nil, s.Parameters, nil)
+			methods = append(methods, method{"constructor", s.Parameters, decl.Init, s.Body, immutableAllowed})
+		}
+	}
+
+	for _, m := range methods {
+		// msg is bound by the interpreter for every contract call
+		c.push()
+		c.declare("msg", nil)
+		c.function(m.name, m.params, m.sig, m.body, m.access)
+		c.pop()
+	}
+}
@@ -0,0 +1,693 @@
+package checker
+
+import (
+	"github.com/Stremax-Team/stremax-lang/pkg/lexer"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"testing"
+)
+
+// checkProgram parses and type-checks a program, failing the test if it
+// does not parse
+func checkProgram(t *testing.T, input string) []string {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+
+	messages := []string{}
+	for _, err := range Check(program) {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+func TestWellTypedPrograms(t *testing.T) {
+	tests := []string{
+		"let x = 5;\nlet y: Int = x + 1;",
+		"let small: Uint8 = 200;\nlet next: Uint8 = small + 1;\nlet big: Uint256 = Uint256(small);",
+		"let decimals = 18;\nlet mask = 0xff ^ decimals;\nlet flipped = decimals ^ 1;\nlet supply = 10 ** decimals;",
+		"let s = \"n = \" + 5;",
+		"function add(a: Int, b: Int): Int { return a + b; }\nlet r: Int = add(1, 2);",
+		"function fact(n: Int): Int { if (n < 2) { return 1; } return n * fact(n - 1); }",
+		"let m: Map<String, Int> = {};\nm[\"a\"] = 1;\nlet v: Int = m[\"a\"];",
+		"let items = [1, 2, 3];\nlet total = 0;\nfor (item in items) { total += item; }",
+		"let m = {\"a\": 1};\nfor (k, v in m) { let s: String = k; let n: Int = v; }",
+		"struct Point { x: Int, y: Int }\nlet p = Point { x: 1, y: 2 };\nlet sum: Int = p.x + p.y;",
+		"enum Color { Red, Green }\nlet c = Color.Red;\nlet d: Color = Color.Green;",
+		"let n = parseInt(\"5\") + len([1]);\nlet s: String = toString(n) + typeof(n);",
+		// Values of unknown type are left to the interpreter
+		"let f = function(x) { return x; };\nlet y: String = f(1);",
+		`contract Counter {
+			state { count: Int }
+			constructor(start: Int) { count = start; }
+			function increment(): Int { count += 1; return count; }
+		}
+		let c = Counter(1);
+		let n: Int = c.increment();`,
+	}
+
+	for _, input := range tests {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+}
+
+func TestTypeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x: Int = \"five\";", "TypeError: Type mismatch: expected Int, got String at :1:14"},
+		{"let x = 5;\nx = true;", "TypeError: Type mismatch: expected Int, got Bool at :2:5"},
+		{"let a: Uint8 = 1;\nlet b: Uint16 = 2;\na + b;", "TypeError: Type mismatch: Uint8 + Uint16 (use an explicit cast) at :3:3"},
+		{"true - 1;", "TypeError: Type mismatch: Bool - Int at :1:6"},
+		{"-\"a\";", "TypeError: Type mismatch: -String at :1:1"},
+		{"1 && true;", "TypeError: Left operand of && must be a boolean, got Int at :1:3"},
+		{"function f(a: Int): Int { return a; }\nf(1, 2);", "TypeError: Wrong number of arguments to f: expected 1, got 2 (f is declared at line 1, column 1) at :2:1"},
+		{"function f(a: Int): Int { return a; }\nf(\"x\");", "TypeError: Type mismatch: argument 1 of f: expected Int, got String at :2:3"},
+		{"function f(): Int { return \"x\"; }", "TypeError: Type mismatch: return value of f: expected Int, got String at :1:28"},
+		{"let m: Map<String, Int> = {};\nm[1] = 2;", "TypeError: Type mismatch: Map<String, Int> key: expected String, got Int at :2:3"},
+		{"let m: Map<String, Int> = {};\nm[\"a\"] = \"b\";", "TypeError: Type mismatch: Map<String, Int> value: expected Int, got String at :2:10"},
+		{"let a = [1];\na[\"x\"];", "TypeError: Array index must be an integer, got String at :2:3"},
+		{"5[0];", "TypeError: Index operator not supported: Int at :1:2"},
+		{"let x = 5;\nx();", "TypeError: Not a function: Int at :2:1"},
+		{"for (x in 5) { }", "TypeError: Cannot iterate over Int at :1:1"},
+		{"let s = \"a\";\ns++;", "TypeError: Type mismatch: String++ at :2:2"},
+		{"[1].size;", "TypeError: Array<Int> has no member size at :1:5"},
+		{"struct P { x: Int }\nlet p = P { x: \"a\" };", "TypeError: Type mismatch: P.x: expected Int, got String at :2:16"},
+		{"struct P { x: Int }\nlet p = P { x: 1 };\np.y;", "TypeError: Struct P has no field y at :3:3"},
+		{"enum Color { Red }\nColor.Blue;", "TypeError: Enum Color has no variant Blue at :2:7"},
+		{"let decimals = 18;\nlet supply = 1000 * 10^decimals;", "TypeError: Operator ^ is bitwise XOR, not exponentiation: use 10 ** for a power, or a hexadecimal literal for XOR at :2:23"},
+		{
+			"contract C {\n state { n: Int }\n function get(): Int { return n; }\n}\nlet c = C();\nc.missing;",
+			"TypeError: Contract C has no member missing at :6:3",
+		},
+		{
+			"contract C {\n constructor(n: Int) { }\n}\nC(\"x\");",
+			"TypeError: Type mismatch: argument 1 of C: expected Int, got String at :4:3",
+		},
+		{
+			"contract C {\n state { total: Int }\n function total(): Int { return total; }\n}",
+			"TypeError: Duplicate member total in contract C at :3:11",
+		},
+		{
+			"contract C {\n function f() { }\n function f() { }\n}",
+			"TypeError: Duplicate member f in contract C at :3:11",
+		},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestAllTypeErrorsReported(t *testing.T) {
+	input := "let a: Int = \"x\";\nlet b: String = 1;\nlet c: Bool = true;"
+
+	errs := checkProgram(t, input)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 type errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestGenericTypeChecking(t *testing.T) {
+	valid := []string{
+		"let xs: Array<Int> = [1, 2];\nxs.push(3);\nlet n: Int = xs[0];",
+		"let grid: Array<Array<Int>> = [[1], [2]];\nlet row: Array<Int> = grid[0];",
+		"let m: Map<String, Array<Int>> = {};\nlet xs: Array<Int> = m[\"a\"];",
+		"let f: fn(Int) -> Bool = function(n: Int): Bool { return n > 0; };\nlet b: Bool = f(1);",
+		"let f: fn(Int) -> Bool = function(n) { return n > 0; };",
+		"function max<T>(a: T, b: T): T { if (a > b) { return a; } return b; }\nlet n: Int = max(1, 2);\nlet s: Uint8 = max(Uint8(1), Uint8(2));",
+		"function first<T>(xs: Array<T>): T { return xs[0]; }\nlet s: String = first([\"a\"]);",
+		"function map<A, B>(xs: Array<A>, f: fn(A) -> B): Array<B> { let out: Array<B> = []; for (x in xs) { out.push(f(x)); } return out; }\nlet strs: Array<String> = map([1], function(x: Int): String { return toString(x); });",
+		"contract C {\n state { winner: Address? }\n function set(a: Address) { winner = a; }\n}",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let xs: Array<Int> = [\"a\"];", "TypeError: Type mismatch: expected Array<Int>, got Array<String> at :1:22"},
+		{"let xs: Array<Int> = [];\nxs.push(\"a\");", "TypeError: Type mismatch: argument 1 of push: expected Int, got String at :2:9"},
+		{"let xs: Array<Int> = [];\nlet s: String = xs[0];", "TypeError: Type mismatch: expected String, got Int at :2:17"},
+		{"let f: fn(Int) -> Bool = function(a: Int, b: Int): Bool { return true; };", "TypeError: Type mismatch: expected fn(Int) -> Bool, got fn(Int, Int) -> Bool at :1:26"},
+		{"let f: fn(Int) -> Bool = function(s: String): Bool { return true; };", "TypeError: Type mismatch: expected fn(Int) -> Bool, got fn(String) -> Bool at :1:26"},
+		{"let f: fn(Int) -> Bool = function(n: Int): Bool { return true; };\nf(\"a\");", "TypeError: Type mismatch: argument 1 of f: expected Int, got String at :2:3"},
+		{"function max<T>(a: T, b: T): T { return a; }\nmax(1, \"two\");", "TypeError: Type mismatch: argument 2 of max: expected Int, got String at :2:8"},
+		{"function max<T>(a: T, b: T): T { return a; }\nlet s: String = max(1, 2);", "TypeError: Type mismatch: expected String, got Int at :2:17"},
+		{"function same<T>(a: T): T { return 1; }", "TypeError: Type mismatch: return value of same: expected T, got Int at :1:36"},
+		{"let owner: Address? = 5;", "TypeError: Type mismatch: expected Address?, got Int at :1:23"},
+		{"contract C {\n state { winner: Address? }\n function get(): Address { return winner; }\n}", "TypeError: Type mismatch: return value of get: expected Address, got Address? at :3:35"},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestOptionalTypeChecking(t *testing.T) {
+	valid := []string{
+		"let x: Int? = null;\nx = 5;\nlet y: Int = x ?? 0;",
+		"let x = null;\nx = 5;",
+		"let s: String? = null;\nlet n: Int? = s?.length;\nlet u: String = s?.toUpper() ?? \"\";",
+		"let s: String? = null;\nlet t: String? = s ?? null;",
+		"struct P { next: Q? }\nstruct Q { name: String }\nlet p = P {};\nlet name: String = p.next?.name ?? \"none\";",
+		"function find(id: Int): String? { if (id == 1) { return \"one\"; } return null; }\nlet s: String = find(1) ?? \"\";",
+		"let x: Int? = null;\nlet b: Bool = x == null;",
+		"let m: Map<String, Int> = {};\nlet n: Int = m[\"a\"] + 1;",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x: Int = null;", "TypeError: Type mismatch: expected Int, got Null at :1:14"},
+		{"let x: Int? = null;\nlet y: Int = x;", "TypeError: Type mismatch: expected Int, got Int? at :2:14"},
+		{"let x: Int? = 1;\nx + 1;", "TypeError: Type mismatch: Int? + Int at :2:3"},
+		{"let s: String? = null;\ns.length;", "TypeError: String? may be null; use ?. to access length at :2:3"},
+		{"let s: String? = null;\nlet n: Int = s?.length;", "TypeError: Type mismatch: expected Int, got Int? at :2:14"},
+		{"let s: String? = null;\nlet u: String = s?.toUpper();", "TypeError: Type mismatch: expected String, got String? at :2:17"},
+		{"let x: Int? = null;\nlet s: String = x ?? \"none\";", "TypeError: Type mismatch: Int? ?? String at :2:19"},
+		{"function f(): Int { return null; }", "TypeError: Type mismatch: return value of f: expected Int, got Null at :1:28"},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestErrorHandlingTypeChecking(t *testing.T) {
+	vault := `error InsufficientBalance(needed: Int, available: Int)
+contract Vault {
+	function withdraw(amount: Int): Int { revert InsufficientBalance(amount, 0); }
+}
+let v = Vault();
+`
+
+	valid := []string{
+		"error Paused()\nrevert Paused();",
+		"revert \"closed\";",
+		"revert Error(\"closed\");",
+		"assert(1 < 2);\nassert(true, \"always\");",
+		"error Low(needed: Int)\nlet e = Low(1);\nlet n: Int = e.needed;",
+		"error Low(needed: Int)\nfunction report(e: Low): Int { return e.needed; }\nreport(Low(1));",
+		vault + "try v.withdraw(1) as left { let n: Int = left; } catch InsufficientBalance(needed, available) { let s: Int = needed - available; }",
+		vault + "try v.withdraw(1) { } catch Error(message) { let s: String = message; } catch (e) { }",
+		vault + "try Vault() as other { let n: Int = other.withdraw(1); } catch { }",
+		"contract C {\n error Closed(at: Int)\n function f() { revert Closed(1); }\n}",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"revert 5;", "TypeError: Cannot revert with Int; use an error such as Unauthorized() or a message at :1:8"},
+		{"error Low(needed: Int)\nrevert Low(\"a\");", "TypeError: Type mismatch: argument 1 of Low: expected Int, got String at :2:12"},
+		{"error Low(needed: Int)\nrevert Low();", "TypeError: Wrong number of arguments to Low: expected 1, got 0 (Low is declared at line 1, column 1) at :2:8"},
+		{"error Low(needed: Int)\nLow(1).available;", "TypeError: Error Low has no field available at :2:8"},
+		{vault + "try v.withdraw(1) as left { let s: String = left; } catch { }", "TypeError: Type mismatch: expected String, got Int at :6:45"},
+		{vault + "try v.withdraw(1) { } catch InsufficientBalance(needed, available) { let s: String = needed; }", "TypeError: Type mismatch: expected String, got Int at :6:86"},
+		{vault + "try v.withdraw(1) { } catch InsufficientBalance(needed) { }", "TypeError: Wrong number of parameters to catch InsufficientBalance: expected 2, got 1 at :6:23"},
+		{vault + "try v.withdraw(1) { } catch Vault(x) { }", "TypeError: Vault is not an error type at :6:29"},
+		{"let m = {\"a\": 1};\ntry m.has(\"a\") { } catch { }", "TypeError: try requires a contract call or deployment, got a call of Map<String, Int>.has at :2:10"},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestTupleTypeChecking(t *testing.T) {
+	divmod := "function divmod(a: Int, b: Int): (Int, Int) { return (a / b, a % b); }\n"
+
+	valid := []string{
+		divmod + "let (q, r) = divmod(7, 2);\nlet s: Int = q + r;",
+		divmod + "let pair = divmod(7, 2);\nlet q: Int = pair[0];",
+		divmod + "let (q, _) = divmod(7, 2);",
+		"let [a, b] = [1, 2];\nlet s: Int = a + b;",
+		"struct P { x: Int, y: String }\nlet { x, y: label } = P { x: 1, y: \"a\" };\nlet n: Int = x;\nlet s: String = label;",
+		"let t: (Int, String?) = (1, null);",
+		"function first<T>(pair: (T, T)): T { return pair[0]; }\nlet n: Int = first((1, 2));",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"function f(): (Int, Int) { return (1, \"a\"); }", "TypeError: Type mismatch: return value of f: expected (Int, Int), got (Int, String) at :1:35"},
+		{divmod + "let (q, r, s) = divmod(7, 2);", "TypeError: Cannot destructure (Int, Int) as (q, r, s) at :2:5"},
+		{divmod + "let (q, r) = divmod(7, 2);\nlet s: String = q;", "TypeError: Type mismatch: expected String, got Int at :3:17"},
+		{"let [a, b] = (1, 2);", "TypeError: Cannot destructure (Int, Int) as [a, b] at :1:5"},
+		{"struct P { x: Int }\nlet { z } = P { x: 1 };", "TypeError: Struct P has no field z at :2:7"},
+		{"let t = (1, \"a\");\nt[2];", "TypeError: Tuple index out of range: 2 for (Int, String) at :2:3"},
+		{"let t = (1, \"a\");\nt[0] = 2;", "TypeError: Cannot assign to an element of a tuple; tuples are immutable at :2:2"},
+		{"let t: (Int, Int) = (1, 2, 3);", "TypeError: Type mismatch: expected (Int, Int), got (Int, Int, Int) at :1:21"},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestParameterTypeChecking(t *testing.T) {
+	mint := "function mint(to: String, amount: Int = 1): Int { return amount; }\n"
+	sum := "function sum(...values: Int): Int { return len(values); }\n"
+
+	valid := []string{
+		mint + "let n: Int = mint(\"a\");",
+		mint + "let n: Int = mint(amount: 5, to: \"a\");",
+		mint + "let n: Int = mint(\"a\", amount: 5);",
+		sum + "let n: Int = sum();\nlet m: Int = sum(1, 2, 3);",
+		"function f(a: Int, b: Int = a * 2): Int { return b; }\nlet n: Int = f(1);",
+		"error Low(needed: Int, available: Int = 0)\nrevert Low(needed: 3);",
+		"contract C { constructor(owner: String, fee: Int = 1) { } }\nlet c = C(fee: 2, owner: \"a\");",
+		"let f: fn(Int) -> Int = function(a: Int, b: Int = 1): Int { return a + b; };\nlet n: Int = f(1);",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{mint + "mint();", "TypeError: Wrong number of arguments to mint: expected 1 or 2, got 0 (mint is declared at line 1, column 1) at :2:1"},
+		{mint + "mint(\"a\", 2, 3);", "TypeError: Wrong number of arguments to mint: expected 1 or 2, got 3 (mint is declared at line 1, column 1) at :2:1"},
+		{mint + "mint(amount: 2);", "TypeError: Missing argument for parameter to of mint (mint is declared at line 1, column 1) at :2:1"},
+		{mint + "mint(\"a\", value: 2);", "TypeError: Unknown parameter value in call to mint (mint is declared at line 1, column 1) at :2:11"},
+		{mint + "mint(\"a\", to: \"b\");", "TypeError: Parameter to of mint is passed more than once (mint is declared at line 1, column 1) at :2:11"},
+		{mint + "mint(\"a\", amount: \"a\");", "TypeError: Type mismatch: argument 2 of mint: expected Int, got String at :2:19"},
+		{sum + "sum(1, \"a\");", "TypeError: Type mismatch: argument 2 of sum: expected Int, got String at :2:8"},
+		{sum + "sum(values: 1);", "TypeError: Variadic parameter values of sum cannot be passed by name (sum is declared at line 1, column 1) at :2:5"},
+		{"function f(a: Int = \"a\") { }", "TypeError: Type mismatch: default value of a in f: expected Int, got String at :1:21"},
+		{"len(value: [1]);", "TypeError: len does not take named arguments at :1:1"},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestInterpolatedStringTypeChecking(t *testing.T) {
+	valid := []string{
+		"let amount = 5;\nlet s: String = \"Sent ${amount} to ${[1, 2]}\";",
+		"let x: Uint8 = 255;\nlet s: String = \"${x:08b}\";",
+		"function f<T>(v: T): String { return \"${v:x}\"; }",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let n: Int = \"${1}\";", "TypeError: Type mismatch: expected Int, got String at :1:14"},
+		{"let s = \"${true:x}\";", "TypeError: Format x applies to integers, got Bool at :1:16"},
+		{"let s = \"${\"a\":05}\";", "TypeError: Format 05 applies to integers, got String at :1:15"},
+		{"let s = \"a\n${1 + true}\";", "TypeError: Type mismatch: Int + Bool at :2:5"},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestStrictModeTypeChecking(t *testing.T) {
+	valid := []string{
+		"pragma strict;\nlet n = 5;\nlet s: String = \"n = \" + toString(n) + \" ${n}\";",
+		"pragma strict;\nlet n = parseInt(\"12\") + 1;",
+		"pragma strict;\nif (1 < 2) { 1; }\nwhile (false) { }\nrequire(true, \"m\");\nassert(!false);",
+		"pragma strict;\nlet f = function(v) { if (v) { 1; } };",
+		// Outside strict mode values are still converted
+		"let s: String = \"n = \" + 5;\nif (1) { 2; }",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"pragma strict;\n\"a\" + 1;", "TypeError: Type mismatch: String + Int (strict mode does not convert values to strings; use toString or interpolation) at :2:5"},
+		{"pragma strict;\ntrue + \"a\";", "TypeError: Type mismatch: Bool + String (strict mode does not convert values to strings; use toString or interpolation) at :2:6"},
+		{"pragma strict;\nlet s = \"a\";\ns += 1;", "TypeError: Type mismatch: String + Int (strict mode does not convert values to strings; use toString or interpolation) at :3:3"},
+		{"pragma strict;\nif (1) { 2; }", "TypeError: Condition of if must be a Bool in strict mode, got Int at :2:5"},
+		{"pragma strict;\nlet s = \"a\";\nwhile (s) { }", "TypeError: Condition of while must be a Bool in strict mode, got String at :3:8"},
+		{"pragma strict;\nfor (let i = 0; i; i++) { }", "TypeError: Condition of for must be a Bool in strict mode, got Int at :2:17"},
+		{"pragma strict;\nrequire(1, \"m\");", "TypeError: Condition of require must be a Bool in strict mode, got Int at :2:9"},
+		{"pragma strict;\nlet b: Bool? = null;\nassert(b);", "TypeError: Condition of assert must be a Bool in strict mode, got Bool? at :3:8"},
+		{"pragma strict;\n!1;", "TypeError: Operand of ! must be a Bool in strict mode, got Int at :2:1"},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestMapMembershipTypeChecking(t *testing.T) {
+	valid := []string{
+		"let m: Map<String, Int> = {};\nlet a: Bool = \"a\" in m;\nlet b: Bool = m.delete(\"a\");",
+		"let m: Map<String, Map<String, Int>> = {};\nm[\"a\"][\"b\"] = 1;\nlet c: Bool = \"b\" in m[\"a\"];",
+		"let f = function(k, m) { k in m; };",
+		// Methods take precedence over entries of the same name
+		"let m = {\"delete\": 1};\nlet b: Bool = m.delete(\"delete\");\nm[\"keys\"] = 2;",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let m: Map<String, Int> = {};\n1 in m;", "TypeError: Type mismatch: Map<String, Int> key: expected String, got Int at :2:1"},
+		{"let a = [1];\n1 in a;", "TypeError: Right operand of in must be a Map, got Array<Int> at :2:3"},
+		{"let m: Map<String, Int> = {};\nm.delete(1);", "TypeError: Type mismatch: argument 1 of delete: expected String, got Int at :2:10"},
+		{"let m: Map<String, Int> = {};\nlet n: Int = m.delete(\"a\");", "TypeError: Type mismatch: expected Int, got Bool at :2:14"},
+		{"let m: Map<String, Int> = {};\nm.length = 1;", "TypeError: Cannot assign to map method length; use [\"length\"] to set the entry at :2:3"},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestMatchExhaustiveness(t *testing.T) {
+	valid := []string{
+		"enum Phase { Open, Ended }\nlet p = Phase.Open;\nmatch (p) { Phase.Open => 1, Phase.Ended => 2 };",
+		"enum Phase { Open, Ended }\nlet p = Phase.Open;\nmatch (p) { Phase.Open => 1, _ => 2 };",
+		// Matches on values other than enum variants are left to the interpreter
+		"let n = 1;\nmatch (n) { 1 => \"one\" };",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"enum Phase { Open, Ended }\nmatch (p) { Phase.Open => 1 }",
+			"TypeError: Match on Phase is not exhaustive: missing Phase.Ended at :2:1",
+		},
+		{
+			"enum Phase { Open, Ended, Closed }\nlet x = match (p) { Phase.Ended => 1 };",
+			"TypeError: Match on Phase is not exhaustive: missing Phase.Open, Phase.Closed at :2:9",
+		},
+		{
+			"enum Phase { Open, Ended }\nmatch (p) { Phase.Open => 1, Phase.Open => 2, Phase.Ended => 3 }",
+			"TypeError: Duplicate match arm Phase.Open at :2:30",
+		},
+		{
+			"enum Phase { Open, Ended }\nmatch (p) { Phase.Open => 1, Phase.Closed => 2 }",
+			"TypeError: Enum Phase has no variant Closed at :2:36",
+		},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestImportedModules(t *testing.T) {
+	modules := map[string]string{
+		"phases": "enum Phase { Open, Ended }\nfunction _hidden(): Int { return 1; }",
+		"limits": "import { Phase } from \"phases\"\nconst MAX: Int = 10;",
+	}
+
+	var importer Importer
+	importer = func(path string) *Module {
+		source, ok := modules[path]
+		if !ok {
+			return nil
+		}
+		p := parser.New(lexer.New(source))
+		return &Module{Name: path, Program: p.ParseProgram(), Importer: importer}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"import { Phase } from \"phases\"\nmatch (Phase.Open) { Phase.Open => 1 }",
+			"TypeError: Match on Phase is not exhaustive: missing Phase.Ended at :2:1",
+		},
+		{
+			"import \"phases\"\nmatch (phases.Phase.Open) { phases.Phase.Ended => 1 }",
+			"TypeError: Match on Phase is not exhaustive: missing Phase.Open at :2:1",
+		},
+		{
+			"import \"limits\" as l\nlet s: String = l.MAX;",
+			"TypeError: Type mismatch: expected String, got Int at :2:17",
+		},
+		{
+			"import { Phase } from \"limits\"\nlet p: Int = Phase.Open;",
+			"TypeError: Type mismatch: expected Int, got Phase at :2:14",
+		},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("parser errors for %q: %v", tt.input, p.Errors())
+		}
+
+		c := New()
+		c.SetImporter(importer)
+		c.Check(program)
+		if len(c.Errors()) == 0 || c.Errors()[0].Error() != tt.expected {
+			t.Errorf("wrong errors for %q. expected=%q, got=%v", tt.input, tt.expected, c.Errors())
+		}
+	}
+
+	// Names a module does not export and modules that cannot be loaded are
+	// left to the interpreter
+	valid := []string{
+		"import { _hidden } from \"phases\"\nlet s: String = _hidden();",
+		"import { x } from \"missing\"\nlet s: String = x;",
+	}
+	for _, input := range valid {
+		c := New()
+		c.SetImporter(importer)
+		c.Check(parser.New(lexer.New(input)).ParseProgram())
+		if len(c.Errors()) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, c.Errors())
+		}
+	}
+}
+
+func TestConstAssignments(t *testing.T) {
+	valid := []string{
+		// Shadowing a constant in an inner scope is allowed
+		"const x = 1;\nfunction f(x: Int) { x = 2; }\nif (true) { let x = 3; x = 4; }",
+		"contract T {\n state { immutable owner: Address }\n constructor() { owner = msg.sender; }\n}",
+		// Function literals may run in the constructor, so they are left to the interpreter
+		"contract T {\n state { immutable owner: Address }\n constructor() { let set = function() { owner = msg.sender; }; set(); }\n}",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const x = 1;\nx = 2;", "TypeError: Cannot assign to constant x at :2:1"},
+		{"const x = 1;\nif (true) { x -= 1; }", "TypeError: Cannot assign to constant x at :2:13"},
+		{"const x = 1;\nlet f = function() { x++; };", "TypeError: Cannot assign to constant x at :2:22"},
+		{"let x = 0;\nfor (const i = 0; i < 3; i++) { x = i; }", "TypeError: Cannot assign to constant i at :2:26"},
+		{"const x = 1;\nconst x = 2;", "TypeError: Cannot redeclare constant x at :2:7"},
+		{"const x = 1;\nfunction x() { }", "TypeError: Cannot redeclare constant x at :2:10"},
+		{"const (a, b) = (1, 2);\na = 2;", "TypeError: Cannot assign to constant a at :2:1"},
+		{
+			"contract T {\n state { const max: Int = 1 }\n function f() { max += 1; }\n}",
+			"TypeError: Cannot assign to constant max at :3:17",
+		},
+		{
+			"contract T {\n state { immutable owner: Address }\n function f() { owner = msg.sender; }\n}",
+			"TypeError: Cannot assign to immutable state field owner outside the constructor at :3:17",
+		},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
+
+func TestWideIntegerLiterals(t *testing.T) {
+	const max = "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+
+	// A literal too large for Int is allowed where its type is declared
+	// unsigned
+	valid := []string{
+		"let x: Uint256 = " + max + ";",
+		"let x: Uint256 = 0; x = " + max + ";",
+		"let x = Uint256(" + max + ");",
+		"function f(n: Uint256): Uint256 { return " + max + "; }\nf(" + max + ");",
+		"struct S { n: Uint256 }\nlet s = S { n: " + max + " };",
+		"contract C {\n state { cap: Uint256 = " + max + " }\n}",
+		// The largest Int is still an Int
+		"let x = 0x7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff;",
+	}
+
+	for _, input := range valid {
+		if errs := checkProgram(t, input); len(errs) != 0 {
+			t.Errorf("unexpected type errors for %q: %v", input, errs)
+		}
+	}
+
+	message := "TypeError: Integer literal 115792089237316195423570985008687907853269984665640564039457584007913129639935 out of range for Int; declare its type as Uint256"
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = " + max + ";", message + " at :1:9"},
+		{"let x = 0x8000000000000000000000000000000000000000000000000000000000000000;", "TypeError: Integer literal 57896044618658097711785492504343953926634992332820282019728792003956564819968 out of range for Int; declare its type as Uint256 at :1:9"},
+		{"let x: Uint256 = 0;\nx == " + max + ";", message + " at :2:6"},
+		{"let x: Int = 0;\nx = " + max + ";", message + " at :2:5"},
+		{"function f(n: Int) { }\nf(" + max + ");", message + " at :2:3"},
+		{"function f<T>(n: T) { }\nf(" + max + ");", message + " at :2:3"},
+		{"toString(" + max + ");", message + " at :1:10"},
+		{"let x = Int256(" + max + ");", message + " at :1:16"},
+		{"function f(): Int { return " + max + "; }", message + " at :1:28"},
+	}
+
+	for _, tt := range tests {
+		errs := checkProgram(t, tt.input)
+		if len(errs) == 0 {
+			t.Errorf("expected type error for %q", tt.input)
+			continue
+		}
+		if errs[0] != tt.expected {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected, errs[0])
+		}
+	}
+}
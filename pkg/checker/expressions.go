@@ -0,0 +1,835 @@
+package checker
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"strings"
+)
+
+// builtinResults holds the result types of the built-in functions, which
+// apply to calls of names the program does not declare itself
+var builtinResults = map[string]*Type{
+	"Address":  Address,
+	"len":      Int,
+	"toString": String,
+	"parseInt": Int,
+	"typeof":   String,
+	"now":      sizedInts["Uint256"],
+}
+
+// expression checks an expression and returns its type
+func (c *Checker) expression(expr parser.Expression) *Type {
+	switch e := expr.(type) {
+	case *parser.IntegerLiteral:
+		// Literals too large for Int are checked by value where their type
+		// is declared
+		if isWideLiteral(e) {
+			c.wideLiteral(e)
+			return nil
+		}
+		return Int
+	case *parser.StringLiteral:
+		return String
+	case *parser.InterpolatedString:
+		for _, interpolation := range e.Interpolations {
+			t := c.expression(interpolation.Expression)
+			format := interpolation.Format
+			if format != nil && (format.Verb != "" || format.Zero) && !isOpaque(t) && !isInteger(t) {
+				c.errorf(format.Token, "Format %s applies to integers, got %s", format, t)
+			}
+		}
+		return String
+	case *parser.BooleanLiteral:
+		return Bool
+	case *parser.AddressLiteral:
+		return Address
+	case *parser.NullLiteral:
+		return Null
+	case *parser.Identifier:
+		typ, _ := c.lookup(e.Value)
+		return typ
+	case *parser.PrefixExpression:
+		return c.prefixExpression(e)
+	case *parser.InfixExpression:
+		return c.infixExpression(e)
+	case *parser.AssignExpression:
+		return c.assignExpression(e)
+	case *parser.PostfixExpression:
+		c.checkTarget(e.Left)
+		operand := c.expression(e.Left)
+		if !isOpaque(operand) && !isInteger(operand) {
+			c.errorf(e.Token, "Type mismatch: %s%s", operand, e.Operator)
+		}
+		return operand
+	case *parser.CallExpression:
+		return c.callExpression(e)
+	case *parser.DotExpression:
+		return c.dotExpression(e)
+	case *parser.IndexExpression:
+		return c.indexExpression(e)
+	case *parser.IfExpression:
+		c.condition(e.Condition, "if")
+		consequence := c.block(e.Consequence)
+		if e.Alternative == nil {
+			return nil
+		}
+		return unify(consequence, c.block(e.Alternative))
+	case *parser.MatchExpression:
+		return c.matchExpression(e)
+	case *parser.FunctionLiteral:
+		sig := c.signature(e.Token, nil, e.Parameters, e.ReturnType)
+		c.function("function literal", e.Parameters, sig, e.Body, immutableUnknown)
+		return sig
+	case *parser.ArrayLiteral:
+		elements := []*Type{}
+		for _, el := range e.Elements {
+			elements = append(elements, c.expression(el))
+		}
+		return NewArray(unify(elements...))
+	case *parser.TupleLiteral:
+		elements := []*Type{}
+		for _, el := range e.Elements {
+			elements = append(elements, c.expression(el))
+		}
+		return NewTuple(elements)
+	case *parser.HashLiteral:
+		keys, values := []*Type{}, []*Type{}
+		for _, key := range e.Keys {
+			keys = append(keys, c.expression(key))
+			values = append(values, c.expression(e.Pairs[key]))
+		}
+		return NewMap(unify(keys...), unify(values...))
+	case *parser.StructLiteral:
+		return c.structLiteral(e)
+	}
+	return nil
+}
+
+// matchExpression checks a match. A match whose patterns are all variants
+// of one enum must handle each variant once or end with a wildcard arm;
+// other matches are left to the interpreter, which fails when no arm
+// applies.
+func (c *Checker) matchExpression(expr *parser.MatchExpression) *Type {
+	c.expression(expr.Subject)
+
+	var enum *Type
+	checkable, wildcard := true, false
+	covered := make(map[string]bool)
+	arms := []*Type{}
+	for _, arm := range expr.Arms {
+		if arm.Pattern == nil {
+			wildcard = true
+		} else if typ, variant := c.variantPattern(arm.Pattern); typ == nil || (enum != nil && typ.Decl != enum.Decl) {
+			checkable = false
+		} else {
+			enum = typ
+			if covered[variant] {
+				c.errorf(arm.Token, "Duplicate match arm %s.%s", enum.Name, variant)
+			}
+			covered[variant] = true
+		}
+		arms = append(arms, c.block(arm.Body))
+	}
+
+	if checkable && !wildcard && enum != nil {
+		missing := []string{}
+		for _, v := range enum.Decl.Variants {
+			if !covered[v] {
+				missing = append(missing, enum.Name+"."+v)
+			}
+		}
+		if len(missing) > 0 {
+			c.errorf(expr.Token, "Match on %s is not exhaustive: missing %s", enum.Name, strings.Join(missing, ", "))
+		}
+	}
+
+	return unify(arms...)
+}
+
+// variantPattern checks a match pattern and, if it names a variant of an
+// enum such as Phase.Open, returns the enum and the variant
+func (c *Checker) variantPattern(pattern parser.Expression) (*Type, string) {
+	dot, ok := pattern.(*parser.DotExpression)
+	if !ok {
+		c.expression(pattern)
+		return nil, ""
+	}
+
+	left := c.expression(dot.Left)
+	if c.access(dot, left) == nil || left.Kind != EnumTypeKind {
+		return nil, ""
+	}
+	return left, dot.Right.(*parser.Identifier).Value
+}
+
+// prefixExpression checks a unary operator
+func (c *Checker) prefixExpression(expr *parser.PrefixExpression) *Type {
+	operand := c.expression(expr.Right)
+	if expr.Operator == "!" {
+		if c.strict && !isOpaque(operand) && operand != Bool {
+			c.errorf(expr.Token, "Operand of ! must be a Bool in strict mode, got %s", operand)
+		}
+		return Bool
+	}
+
+	if !isOpaque(operand) && !isInteger(operand) {
+		c.errorf(expr.Token, "Type mismatch: %s%s", expr.Operator, operand)
+		return nil
+	}
+	return operand
+}
+
+// condition checks the condition of an if, while, for, require or assert,
+// which must be a Bool in strict mode
+func (c *Checker) condition(expr parser.Expression, construct string) {
+	t := c.expression(expr)
+	if c.strict && !isOpaque(t) && t != Bool {
+		c.errorf(position(expr), "Condition of %s must be a Bool in strict mode, got %s", construct, t)
+	}
+}
+
+// infixExpression checks a binary operator, following the operand rules of
+// the interpreter
+func (c *Checker) infixExpression(expr *parser.InfixExpression) *Type {
+	left := c.expression(expr.Left)
+	right := c.expression(expr.Right)
+
+	switch expr.Operator {
+	case "&&", "||":
+		if !isOpaque(left) && left != Bool {
+			c.errorf(expr.Token, "Left operand of %s must be a boolean, got %s", expr.Operator, left)
+		}
+		if !isOpaque(right) && right != Bool {
+			c.errorf(expr.Token, "Right operand of %s must be a boolean, got %s", expr.Operator, right)
+		}
+		return Bool
+	case "==", "!=":
+		return Bool
+	case "in":
+		switch {
+		case isOpaque(right):
+		case right.Kind == MapKind:
+			c.expect(expr.Left, right.String()+" key", right.Key, left)
+		default:
+			c.errorf(expr.Token, "Right operand of in must be a Map, got %s", right)
+		}
+		return Bool
+	case "??":
+		return c.coalesce(expr.Token, left, right)
+	case "^":
+		// 10^decimals reads as a power but is an exclusive or, and since ^
+		// binds looser than *, a * 10^n is (a * 10) ^ n. Masks are written
+		// in hexadecimal, so a decimal literal before ^ is taken as the mistake
+		if lit, ok := operandBefore(expr.Left).(*parser.IntegerLiteral); ok && !strings.HasPrefix(lit.Token.Literal, "0x") {
+			c.errorf(expr.Token, "Operator ^ is bitwise XOR, not exponentiation: use %s ** for a power, or a hexadecimal literal for XOR", lit.Token.Literal)
+			return nil
+		}
+	}
+
+	return c.binaryOperator(expr.Token, expr.Operator, left, right)
+}
+
+// operandBefore finds the operand written just before an operator whose
+// left side is expr, which is the rightmost operand of expr
+func operandBefore(expr parser.Expression) parser.Expression {
+	for {
+		switch e := expr.(type) {
+		case *parser.InfixExpression:
+			expr = e.Right
+		case *parser.PrefixExpression:
+			expr = e.Right
+		default:
+			return expr
+		}
+	}
+}
+
+// coalesce determines the type of value ?? fallback. An optional value is
+// unwrapped, and the result is optional only if the fallback may be null.
+func (c *Checker) coalesce(tok parser.Token, value, fallback *Type) *Type {
+	switch {
+	case value == Null:
+		return fallback
+	case value == nil || value.Kind != OptionalKind:
+		// The value is never null, or its type is unknown
+		if fallback == nil {
+			return nil
+		}
+		return value
+	case fallback == nil:
+		return nil
+	case fallback == Null || fallback.Kind == OptionalKind:
+		if !assignable(value, fallback) {
+			c.errorf(tok, "Type mismatch: %s ?? %s", value, fallback)
+			return nil
+		}
+		return value
+	case !assignable(value.Elem, fallback):
+		c.errorf(tok, "Type mismatch: %s ?? %s", value, fallback)
+		return nil
+	default:
+		return value.Elem
+	}
+}
+
+// binaryOperator determines the result of an arithmetic, bitwise,
+// comparison or concatenation operator, reporting operands it cannot apply to
+func (c *Checker) binaryOperator(tok parser.Token, operator string, left, right *Type) *Type {
+	// Any value can be concatenated to a string, but strict mode asks
+	// for the conversion to be written out
+	if operator == "+" && (left == String || right == String) {
+		if c.strict && !isOpaque(left) && !isOpaque(right) && left != right {
+			c.errorf(tok, "Type mismatch: %s + %s (strict mode does not convert values to strings; use toString or interpolation)", left, right)
+		}
+		return String
+	}
+
+	if isOpaque(left) || isOpaque(right) {
+		if operator == "<" || operator == ">" || operator == "<=" || operator == ">=" {
+			return Bool
+		}
+		return nil
+	}
+
+	if !isInteger(left) || !isInteger(right) {
+		c.errorf(tok, "Type mismatch: %s %s %s", left, operator, right)
+		return nil
+	}
+
+	switch operator {
+	case "<", ">", "<=", ">=":
+		return Bool
+	case "**", "<<", ">>":
+		// The right operand is only a count
+		return left
+	}
+
+	result, ok := arithmeticResult(left, right)
+	if !ok {
+		c.errorf(tok, "Type mismatch: %s %s %s (use an explicit cast)", left, operator, right)
+		return nil
+	}
+	return result
+}
+
+// checkTarget reports an assignment to a constant, or to an immutable
+// state field outside the constructor
+func (c *Checker) checkTarget(target parser.Expression) {
+	ident, ok := target.(*parser.Identifier)
+	if !ok {
+		return
+	}
+
+	b := c.binding(ident.Value)
+	switch {
+	case b == nil:
+	case b.kind == constantBinding:
+		c.errorf(ident.Token, "Cannot assign to constant %s", ident.Value)
+	case b.kind == immutableBinding && c.immutables == immutableForbidden:
+		c.errorf(ident.Token, "Cannot assign to immutable state field %s outside the constructor", ident.Value)
+	}
+}
+
+// assignExpression checks an assignment or compound assignment
+func (c *Checker) assignExpression(expr *parser.AssignExpression) *Type {
+	c.checkTarget(expr.Left)
+
+	context := ""
+	var target *Type
+	if index, ok := expr.Left.(*parser.IndexExpression); ok {
+		var container *Type
+		container, target = c.index(index)
+		if container != nil && container.Kind == MapKind {
+			context = container.String() + " value"
+		}
+		if container != nil && container.Kind == TupleKind {
+			c.errorf(index.Token, "Cannot assign to an element of a tuple; tuples are immutable")
+		}
+	} else if dot, ok := expr.Left.(*parser.DotExpression); ok {
+		left := c.expression(dot.Left)
+		name := dot.Right.(*parser.Identifier)
+		if left != nil && left.Kind == MapKind {
+			// A dot reads the method of that name, not the entry
+			if _, ok := methodType(left, name.Value); ok {
+				c.errorf(name.Token, "Cannot assign to map method %s; use [%q] to set the entry", name.Value, name.Value)
+			}
+		}
+		target = c.access(dot, left)
+	} else {
+		target = c.expression(expr.Left)
+	}
+	value := c.value(expr.Right, target)
+
+	if expr.Operator != "=" {
+		// x += y assigns x + y
+		operator := expr.Operator[:len(expr.Operator)-1]
+		value = c.binaryOperator(expr.Token, operator, target, value)
+	}
+
+	c.expect(expr.Right, context, target, value)
+	return target
+}
+
+// callExpression checks the arguments of a call against the parameters of
+// the function called and returns the type of its result
+func (c *Checker) callExpression(expr *parser.CallExpression) *Type {
+	return c.call(expr, c.expression(expr.Function))
+}
+
+// call checks a call of a function whose type has already been found
+func (c *Checker) call(expr *parser.CallExpression, callee *Type) *Type {
+	args := []*Type{}
+	for _, arg := range expr.Arguments {
+		// A literal too large for Int is checked against the parameter it
+		// is passed to
+		if isWideLiteral(arg) {
+			args = append(args, sizedInts["Uint256"])
+			continue
+		}
+		args = append(args, c.expression(arg))
+	}
+
+	if callee == nil {
+		builtin := ""
+		if ident, ok := expr.Function.(*parser.Identifier); ok {
+			if _, declared := c.lookup(ident.Value); !declared {
+				builtin = ident.Value
+			}
+		}
+
+		// Of the built-in functions only the unsigned casts, such as
+		// Uint256(x), take a literal too large for Int
+		for _, arg := range expr.Arguments {
+			if isWideLiteral(arg) && !isUnsigned(sizedInts[builtin]) {
+				c.wideLiteral(arg)
+			}
+		}
+
+		if builtin == "" {
+			return nil
+		}
+		if expr.Names != nil {
+			c.errorf(position(expr.Function), "%s does not take named arguments", builtin)
+		}
+		return builtinResult(builtin)
+	}
+
+	// A method called through ?. returns null if its object is null
+	if dot, ok := expr.Function.(*parser.DotExpression); ok && dot.Optional && callee.Kind == OptionalKind {
+		return NewOptional(c.apply(expr, callee.Elem, args))
+	}
+	return c.apply(expr, callee, args)
+}
+
+// apply checks the arguments of a call to a function of a known type
+func (c *Checker) apply(expr *parser.CallExpression, callee *Type, args []*Type) *Type {
+	name := calleeName(expr.Function)
+	sig := callee
+	var result *Type
+
+	switch callee.Kind {
+	case FunctionKind:
+		result = callee.Result
+	case ContractKind:
+		sig = callee.Decl.Init
+		result = &Type{Kind: InstanceKind, Name: callee.Name, Decl: callee.Decl}
+	case ErrorTypeKind:
+		sig = callee.Decl.Init
+		result = sig.Result
+	default:
+		c.errorf(position(expr.Function), "Not a function: %s", callee)
+		return nil
+	}
+
+	params, ok := c.match(expr, name, sig, args)
+	if !ok {
+		if len(sig.TypeParams) != 0 {
+			return nil
+		}
+		return result
+	}
+
+	// The type parameters of a generic function take the types of the
+	// arguments they are first used by
+	var bindings map[*Type]*Type
+	if len(sig.TypeParams) != 0 {
+		bindings = make(map[*Type]*Type)
+		for idx, param := range params {
+			infer(param, args[idx], bindings)
+		}
+		result = substitute(result, bindings)
+	}
+
+	for idx, param := range params {
+		if isWideLiteral(expr.Arguments[idx]) && !isUnsigned(param) {
+			c.wideLiteral(expr.Arguments[idx])
+			continue
+		}
+		if bindings != nil {
+			param = substitute(param, bindings)
+		}
+		if !assignable(param, args[idx]) {
+			c.errorf(position(expr.Arguments[idx]), "Type mismatch: argument %d of %s: expected %s, got %s",
+				idx+1, name, param, args[idx])
+		}
+	}
+
+	return result
+}
+
+// match matches the arguments of a call to the parameters of a function and
+// returns the type of the parameter each argument is passed to. Named
+// arguments are passed to the parameter of that name and the others in
+// order; parameters with default values may be left out.
+func (c *Checker) match(expr *parser.CallExpression, name string, fn *Type, args []*Type) ([]*Type, bool) {
+	if fn.Sig == nil {
+		// Functions known only by their type take positional arguments
+		if expr.Names != nil {
+			return nil, false
+		}
+		if len(args) != len(fn.Params) {
+			c.errorf(position(expr.Function), "Wrong number of arguments to %s: expected %d, got %d",
+				name, len(fn.Params), len(args))
+			return nil, false
+		}
+		return fn.Params, true
+	}
+
+	sig := fn.Sig
+	declared := fmt.Sprintf(" (%s is declared at line %d, column %d)", name, sig.Token.Line, sig.Token.Column)
+	fixed := len(fn.Params)
+	if sig.Variadic {
+		fixed--
+	}
+
+	params := make([]*Type, len(args))
+	passed := make([]bool, len(fn.Params))
+	positional := 0
+	for idx := range args {
+		argName := expr.ArgumentName(idx)
+		if argName == nil {
+			positional++
+			if idx < fixed {
+				passed[idx] = true
+			}
+			continue
+		}
+
+		param := -1
+		for p, paramName := range sig.Names {
+			if paramName == argName.Value {
+				param = p
+			}
+		}
+		switch {
+		case param < 0:
+			c.errorf(argName.Token, "Unknown parameter %s in call to %s%s", argName.Value, name, declared)
+			return nil, false
+		case param == fixed:
+			c.errorf(argName.Token, "Variadic parameter %s of %s cannot be passed by name%s", argName.Value, name, declared)
+			return nil, false
+		case passed[param]:
+			c.errorf(argName.Token, "Parameter %s of %s is passed more than once%s", argName.Value, name, declared)
+			return nil, false
+		}
+		passed[param] = true
+		params[idx] = fn.Params[param]
+	}
+
+	if !fn.accepts(positional) && (expr.Names == nil || positional > fixed) {
+		c.errorf(position(expr.Function), "Wrong number of arguments to %s: expected %s, got %d%s",
+			name, expectedArguments(fn), positional, declared)
+		return nil, false
+	}
+	for idx := 0; idx < fixed-sig.Defaults; idx++ {
+		if !passed[idx] {
+			c.errorf(position(expr.Function), "Missing argument for parameter %s of %s%s", sig.Names[idx], name, declared)
+			return nil, false
+		}
+	}
+
+	for idx := 0; idx < positional; idx++ {
+		params[idx] = fn.param(idx)
+	}
+	return params, true
+}
+
+// expectedArguments describes how many arguments a function takes
+func expectedArguments(fn *Type) string {
+	total := len(fn.Params)
+	if fn.Sig.Variadic {
+		total--
+	}
+	required := total - fn.Sig.Defaults
+
+	switch {
+	case fn.Sig.Variadic:
+		return fmt.Sprintf("at least %d", required)
+	case total == required+1:
+		return fmt.Sprintf("%d or %d", required, total)
+	case total > required:
+		return fmt.Sprintf("%d to %d", required, total)
+	default:
+		return fmt.Sprintf("%d", required)
+	}
+}
+
+// calleeName returns the name a called function is reported by
+func calleeName(expr parser.Expression) string {
+	switch e := expr.(type) {
+	case *parser.Identifier:
+		return e.Value
+	case *parser.DotExpression:
+		return e.Right.String()
+	default:
+		return expr.String()
+	}
+}
+
+// builtinResult returns the result type of a call to a built-in function
+func builtinResult(name string) *Type {
+	if sized, ok := sizedInts[name]; ok {
+		return sized
+	}
+	return builtinResults[name]
+}
+
+// dotExpression checks a member access
+func (c *Checker) dotExpression(expr *parser.DotExpression) *Type {
+	return c.access(expr, c.expression(expr.Left))
+}
+
+// access checks a member access whose object's type has already been found
+func (c *Checker) access(expr *parser.DotExpression, left *Type) *Type {
+	name := expr.Right.(*parser.Identifier).Value
+	if left == nil {
+		return nil
+	}
+
+	// Members of an optional value are read with ?., which is null if the
+	// value is
+	if left.Kind == OptionalKind {
+		if !expr.Optional {
+			c.errorf(expr.Right.(*parser.Identifier).Token, "%s may be null; use ?. to access %s", left, name)
+			return nil
+		}
+		return NewOptional(c.member(expr, left.Elem, name))
+	}
+	if left == Null && expr.Optional {
+		return Null
+	}
+	return c.member(expr, left, name)
+}
+
+// member checks the access of a named member of a value of a known type
+func (c *Checker) member(expr *parser.DotExpression, left *Type, name string) *Type {
+	switch left.Kind {
+	case EnumTypeKind:
+		for _, v := range left.Decl.Variants {
+			if v == name {
+				return &Type{Kind: EnumKind, Name: left.Name, Decl: left.Decl}
+			}
+		}
+		c.errorf(expr.Right.(*parser.Identifier).Token, "Enum %s has no variant %s", left.Name, name)
+		return nil
+	case StructKind:
+		field, ok := left.Decl.Fields[name]
+		if !ok {
+			c.errorf(expr.Right.(*parser.Identifier).Token, "Struct %s has no field %s", left.Name, name)
+		}
+		return field
+	case ErrorKind:
+		field, ok := left.Decl.Fields[name]
+		if !ok {
+			c.errorf(expr.Right.(*parser.Identifier).Token, "Error %s has no field %s", left.Name, name)
+		}
+		return field
+	case ModuleKind:
+		// Exports whose types are unknown are left to the interpreter
+		return left.Decl.Fields[name]
+	case InstanceKind:
+		if member, ok := left.Decl.Fields[name]; ok {
+			return member
+		}
+		if name == "address" {
+			return Address
+		}
+		c.errorf(expr.Right.(*parser.Identifier).Token, "Contract %s has no member %s", left.Name, name)
+		return nil
+	}
+
+	if method, ok := methodType(left, name); ok {
+		return method
+	}
+
+	switch left.Kind {
+	case MapKind:
+		// Other names read the entry with that key
+		return left.Value
+	case ArrayKind, Basic:
+		c.errorf(expr.Right.(*parser.Identifier).Token, "%s has no member %s", left, name)
+	}
+	return nil
+}
+
+// methodType returns the type of a member of a built-in type: the value of
+// a property such as length, or the signature of a method
+func methodType(recv *Type, name string) (*Type, bool) {
+	switch {
+	case recv.Kind == ArrayKind:
+		switch name {
+		case "length":
+			return Int, true
+		case "push":
+			return NewFunction([]*Type{recv.Elem}, Int), true
+		case "pop":
+			return NewFunction([]*Type{}, recv.Elem), true
+		}
+	case recv.Kind == MapKind:
+		switch name {
+		case "length":
+			return Int, true
+		case "keys":
+			return NewFunction([]*Type{}, NewArray(recv.Key)), true
+		case "values":
+			return NewFunction([]*Type{}, NewArray(recv.Value)), true
+		case "has", "delete":
+			return NewFunction([]*Type{recv.Key}, Bool), true
+		}
+	case recv == String:
+		switch name {
+		case "length":
+			return Int, true
+		case "toUpper", "toLower":
+			return NewFunction([]*Type{}, String), true
+		case "contains":
+			return NewFunction([]*Type{String}, Bool), true
+		}
+	case recv == Address:
+		switch name {
+		case "balance":
+			return sizedInts["Uint256"], true
+		case "transfer":
+			return NewFunction([]*Type{nil}, nil), true
+		case "send":
+			return NewFunction([]*Type{nil}, Bool), true
+		}
+	}
+	return nil, false
+}
+
+// indexExpression checks an index into a map or array
+func (c *Checker) indexExpression(expr *parser.IndexExpression) *Type {
+	_, elem := c.index(expr)
+	return elem
+}
+
+// index checks an index expression and returns the type of the collection
+// indexed and of its elements
+func (c *Checker) index(expr *parser.IndexExpression) (*Type, *Type) {
+	left := c.expression(expr.Left)
+	index := c.expression(expr.Index)
+	if isOpaque(left) {
+		return nil, nil
+	}
+
+	switch left.Kind {
+	case MapKind:
+		c.expect(expr.Index, left.String()+" key", left.Key, index)
+		return left, left.Value
+	case ArrayKind:
+		if index != nil && !isInteger(index) {
+			c.errorf(position(expr.Index), "Array index must be an integer, got %s", index)
+		}
+		return left, left.Elem
+	case TupleKind:
+		if index != nil && !isInteger(index) {
+			c.errorf(position(expr.Index), "Tuple index must be an integer, got %s", index)
+		}
+		// Only a literal index tells which element is read
+		lit, ok := expr.Index.(*parser.IntegerLiteral)
+		if !ok {
+			return left, nil
+		}
+		if !lit.Value.IsInt64() || lit.Value.Int64() >= int64(len(left.Elems)) {
+			c.errorf(lit.Token, "Tuple index out of range: %s for %s", lit.Value, left)
+			return left, nil
+		}
+		return left, left.Elems[lit.Value.Int64()]
+	default:
+		c.errorf(expr.Token, "Index operator not supported: %s", left)
+		return left, nil
+	}
+}
+
+// structLiteral checks the field values of a struct literal
+func (c *Checker) structLiteral(lit *parser.StructLiteral) *Type {
+	named, _ := c.lookup(lit.Name.Value)
+	if named == nil || named.Kind != StructTypeKind {
+		for _, val := range lit.Values {
+			c.expression(val)
+		}
+		return nil
+	}
+
+	for idx, name := range lit.Fields {
+		field, ok := named.Decl.Fields[name.Value]
+		value := c.value(lit.Values[idx], field)
+		if !ok {
+			c.errorf(name.Token, "Struct %s has no field %s", named.Name, name.Value)
+			continue
+		}
+		c.expect(lit.Values[idx], named.Name+"."+name.Value, field, value)
+	}
+
+	return &Type{Kind: StructKind, Name: named.Name, Decl: named.Decl}
+}
+
+// position returns the token at which an expression starts
+func position(expr parser.Expression) parser.Token {
+	switch e := expr.(type) {
+	case *parser.InfixExpression:
+		return position(e.Left)
+	case *parser.AssignExpression:
+		return position(e.Left)
+	case *parser.PostfixExpression:
+		return position(e.Left)
+	case *parser.CallExpression:
+		return position(e.Function)
+	case *parser.DotExpression:
+		return position(e.Left)
+	case *parser.IndexExpression:
+		return position(e.Left)
+	case *parser.Identifier:
+		return e.Token
+	case *parser.IntegerLiteral:
+		return e.Token
+	case *parser.StringLiteral:
+		return e.Token
+	case *parser.InterpolatedString:
+		return e.Token
+	case *parser.BooleanLiteral:
+		return e.Token
+	case *parser.NullLiteral:
+		return e.Token
+	case *parser.AddressLiteral:
+		return e.Token
+	case *parser.PrefixExpression:
+		return e.Token
+	case *parser.IfExpression:
+		return e.Token
+	case *parser.MatchExpression:
+		return e.Token
+	case *parser.FunctionLiteral:
+		return e.Token
+	case *parser.ArrayLiteral:
+		return e.Token
+	case *parser.TupleLiteral:
+		return e.Token
+	case *parser.HashLiteral:
+		return e.Token
+	case *parser.StructLiteral:
+		return e.Token
+	}
+	return parser.Token{}
+}
@@ -0,0 +1,78 @@
+package checker
+
+import (
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"strings"
+)
+
+// Importer loads a module that a program imports, given the path written in
+// the import statement. It returns nil if the module cannot be loaded, in
+// which case the bindings the import declares are unknown and the
+// interpreter reports the failure when the import runs.
+type Importer func(path string) *Module
+
+// Module is an imported source file as the checker sees it
+type Module struct {
+	Name     string // the name a namespace import binds by default, or "" if it has none
+	Program  *parser.Program
+	Importer Importer // loads the imports of the module itself
+}
+
+// SetImporter sets how the checker loads imported modules, so that the
+// bindings they export have known types
+func (c *Checker) SetImporter(importer Importer) {
+	c.importer = importer
+}
+
+// importStatement declares the bindings of an import: the namespace of the
+// module or the exports it selects
+func (c *Checker) importStatement(stmt *parser.ImportStatement) {
+	var module *Type
+	name := ""
+	if c.importer != nil {
+		if m := c.importer(stmt.Path.Value); m != nil {
+			module = c.module(m)
+			name = m.Name
+		}
+	}
+
+	if len(stmt.Names) == 0 {
+		if stmt.Alias != nil {
+			name = stmt.Alias.Value
+		}
+		if name != "" {
+			c.declare(name, module)
+		}
+		return
+	}
+
+	for _, n := range stmt.Names {
+		var typ *Type
+		if module != nil {
+			typ = module.Decl.Fields[n.Value]
+		}
+		c.bind(n, typ, variableBinding)
+	}
+}
+
+// module checks an imported module and returns its namespace. Errors in the
+// module are left to the interpreter, which reports them against the
+// module's file when it is imported.
+func (c *Checker) module(m *Module) *Type {
+	mc := New()
+	mc.strict = c.strict
+	mc.importer = m.Importer
+
+	// The module's top-level bindings get a scope of their own, apart from
+	// the built-in error types
+	mc.push()
+	mc.Check(m.Program)
+
+	decl := &Declaration{Name: m.Name, Fields: make(map[string]*Type)}
+	for name, b := range mc.scopes[1] {
+		if !strings.HasPrefix(name, "_") {
+			decl.Fields[name] = b.typ
+		}
+	}
+	return &Type{Kind: ModuleKind, Name: m.Name, Decl: decl}
+}
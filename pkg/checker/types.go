@@ -0,0 +1,423 @@
+package checker
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"strings"
+)
+
+// Kind distinguishes the families of static types
+type Kind int
+
+const (
+	// Basic is a built-in value type such as Int, Uint8, String or Bool
+	Basic Kind = iota
+	// MapKind is a Map<K, V>
+	MapKind
+	// ArrayKind is an array of elements of one type
+	ArrayKind
+	// FunctionKind is a function, built-in method or contract function
+	FunctionKind
+	// StructKind is a value of a declared struct
+	StructKind
+	// EnumKind is a variant of a declared enum
+	EnumKind
+	// StructTypeKind is the name of a struct
+	StructTypeKind
+	// EnumTypeKind is the name of an enum, whose fields are its variants
+	EnumTypeKind
+	// ContractKind is the name of a contract, which deploys it when called
+	ContractKind
+	// InstanceKind is a deployed contract
+	InstanceKind
+	// OptionalKind is a T?, which holds a value of type T or null
+	OptionalKind
+	// TypeParamKind is a type parameter of a generic function, such as T
+	TypeParamKind
+	// ErrorKind is a value of a declared error
+	ErrorKind
+	// ErrorTypeKind is the name of an error, which creates an error when called
+	ErrorTypeKind
+	// TupleKind is a fixed-size group of values, such as (Int, String)
+	TupleKind
+	// ModuleKind is the namespace of an imported module, whose fields are
+	// its exports
+	ModuleKind
+)
+
+// Type is the static type of an expression. A nil *Type is unknown: the
+// checker accepts it anywhere and leaves it to the interpreter.
+type Type struct {
+	Kind Kind
+	Name string // the type name, e.g. Int, Map or the name of a struct
+
+	Key   *Type // the key type of a Map
+	Value *Type // the value type of a Map
+	Elem  *Type // the element type of an Array, or the type of an optional
+
+	Elems []*Type // the element types of a tuple
+
+	Params     []*Type    // the parameter types of a function; a variadic parameter has the type of each argument it collects
+	Result     *Type      // the result type of a function
+	TypeParams []*Type    // the type parameters of a generic function
+	Sig        *Signature // the parameters of a function declared in the program
+
+	Decl *Declaration // the declaration of a struct, enum, contract or module
+}
+
+// Declaration describes a type declared in the program
+type Declaration struct {
+	Name     string
+	Fields   map[string]*Type // struct or error fields, or contract state fields and functions
+	Order    []string         // field names in declaration order
+	Variants []string         // enum variants
+	Init     *Type            // the constructor of a contract or error
+}
+
+// Signature describes the parameters of a function declared in the program,
+// which calls may pass by name or leave out. Function types written in
+// annotations and built-in functions have none.
+type Signature struct {
+	Names    []string     // the parameter names
+	Defaults int          // the number of parameters with default values
+	Variadic bool         // the last parameter collects the remaining arguments
+	Token    parser.Token // the declaration
+}
+
+// Built-in types
+var (
+	Int     = &Type{Kind: Basic, Name: "Int"}
+	String  = &Type{Kind: Basic, Name: "String"}
+	Bool    = &Type{Kind: Basic, Name: "Bool"}
+	Address = &Type{Kind: Basic, Name: "Address"}
+	Null    = &Type{Kind: Basic, Name: "Null"}
+)
+
+// sizedInts holds the sized integer types by name: Uint8 through Uint256
+// and Int8 through Int256, in steps of 8 bits
+var sizedInts = newSizedInts()
+
+func newSizedInts() map[string]*Type {
+	types := make(map[string]*Type)
+	for bits := 8; bits <= 256; bits += 8 {
+		for _, prefix := range []string{"Uint", "Int"} {
+			name := fmt.Sprintf("%s%d", prefix, bits)
+			types[name] = &Type{Kind: Basic, Name: name}
+		}
+	}
+	return types
+}
+
+// NewMap creates a Map type
+func NewMap(key, value *Type) *Type {
+	return &Type{Kind: MapKind, Name: "Map", Key: key, Value: value}
+}
+
+// NewArray creates an Array type
+func NewArray(elem *Type) *Type {
+	return &Type{Kind: ArrayKind, Name: "Array", Elem: elem}
+}
+
+// NewOptional creates the optional type T? of a type T
+func NewOptional(elem *Type) *Type {
+	if elem == nil || elem.Kind == OptionalKind {
+		return elem
+	}
+	return &Type{Kind: OptionalKind, Name: "Optional", Elem: elem}
+}
+
+// NewTuple creates a tuple type
+func NewTuple(elems []*Type) *Type {
+	return &Type{Kind: TupleKind, Name: "Tuple", Elems: elems}
+}
+
+// NewFunction creates a function type
+func NewFunction(params []*Type, result *Type) *Type {
+	return &Type{Kind: FunctionKind, Name: "Function", Params: params, Result: result}
+}
+
+// NewErrorType creates the type of the name of an error with the given
+// fields, which creates an error when called
+func NewErrorType(name string, fields []string, types []*Type) *Type {
+	decl := &Declaration{Name: name, Fields: make(map[string]*Type), Order: fields}
+	for idx, field := range fields {
+		decl.Fields[field] = types[idx]
+	}
+	decl.Init = NewFunction(types, &Type{Kind: ErrorKind, Name: name, Decl: decl})
+	return &Type{Kind: ErrorTypeKind, Name: name, Decl: decl}
+}
+
+// String returns the type as it is written in Stremax-Lang code
+func (t *Type) String() string {
+	if t == nil {
+		return "?"
+	}
+
+	switch t.Kind {
+	case MapKind:
+		if t.Key == nil && t.Value == nil {
+			return "Map"
+		}
+		return fmt.Sprintf("Map<%s, %s>", t.Key, t.Value)
+	case ArrayKind:
+		if t.Elem == nil {
+			return "Array"
+		}
+		return fmt.Sprintf("Array<%s>", t.Elem)
+	case OptionalKind:
+		return t.Elem.String() + "?"
+	case TupleKind:
+		elems := []string{}
+		for _, e := range t.Elems {
+			elems = append(elems, e.String())
+		}
+		return fmt.Sprintf("(%s)", strings.Join(elems, ", "))
+	case FunctionKind:
+		params := []string{}
+		for _, p := range t.Params {
+			params = append(params, p.String())
+		}
+		if t.Sig != nil && t.Sig.Variadic {
+			params[len(params)-1] = "..." + params[len(params)-1]
+		}
+		if t.Result == nil {
+			return fmt.Sprintf("fn(%s)", strings.Join(params, ", "))
+		}
+		return fmt.Sprintf("fn(%s) -> %s", strings.Join(params, ", "), t.Result)
+	default:
+		return t.Name
+	}
+}
+
+// accepts reports whether a function can be called with n positional
+// arguments
+func (t *Type) accepts(n int) bool {
+	required := len(t.Params)
+	if t.Sig != nil {
+		required -= t.Sig.Defaults
+		if t.Sig.Variadic {
+			return n >= required-1
+		}
+	}
+	return n >= required && n <= len(t.Params)
+}
+
+// param returns the type of the parameter of a function that the
+// positional argument at idx is passed to
+func (t *Type) param(idx int) *Type {
+	if t.Sig != nil && t.Sig.Variadic && idx >= len(t.Params)-1 {
+		return t.Params[len(t.Params)-1]
+	}
+	return t.Params[idx]
+}
+
+// isInteger reports whether t is Int or a sized integer type
+func isInteger(t *Type) bool {
+	return t == Int || isSized(t)
+}
+
+// isUnsigned reports whether t is an unsigned sized integer type such as
+// Uint256
+func isUnsigned(t *Type) bool {
+	return isSized(t) && strings.HasPrefix(t.Name, "Uint")
+}
+
+// isWideLiteral reports whether expr is an integer literal too large for
+// Int. Such a literal may only be stored where its type is declared
+// unsigned, and is then a Uint256.
+func isWideLiteral(expr parser.Expression) bool {
+	lit, ok := expr.(*parser.IntegerLiteral)
+	if !ok || lit.Value == nil {
+		return false
+	}
+	_, max, _ := parser.IntegerRange("Int")
+	return lit.Value.Cmp(max) > 0
+}
+
+// isOpaque reports whether the checker cannot tell which operations values
+// of type t support: t is unknown or a type parameter, whose values are
+// checked by the interpreter when they are used
+func isOpaque(t *Type) bool {
+	return t == nil || t.Kind == TypeParamKind
+}
+
+// isSized reports whether t is a sized integer type such as Uint8
+func isSized(t *Type) bool {
+	return t != nil && sizedInts[t.Name] == t
+}
+
+// assignable reports whether a value of type value can be stored in a
+// binding of type target. Unknown types are assignable both ways. Plain Int
+// values may be stored in sized integer bindings, where the interpreter
+// checks their range, and sized values in Int bindings; values of two
+// different sized types need an explicit cast.
+func assignable(target, value *Type) bool {
+	if target == nil || value == nil {
+		return true
+	}
+
+	if isInteger(target) && isInteger(value) {
+		return target == value || target == Int || value == Int
+	}
+
+	// An optional holds null or a value of its type; an optional value must
+	// be unwrapped before it can be stored where null is not allowed
+	if target.Kind == OptionalKind {
+		if value == Null {
+			return true
+		}
+		if value.Kind == OptionalKind {
+			return assignable(target.Elem, value.Elem)
+		}
+		return assignable(target.Elem, value)
+	}
+
+	if target.Kind != value.Kind {
+		return false
+	}
+
+	switch target.Kind {
+	case MapKind:
+		return assignable(target.Key, value.Key) && assignable(target.Value, value.Value)
+	case ArrayKind:
+		return assignable(target.Elem, value.Elem)
+	case TupleKind:
+		if len(target.Elems) != len(value.Elems) {
+			return false
+		}
+		for idx := range target.Elems {
+			if !assignable(target.Elems[idx], value.Elems[idx]) {
+				return false
+			}
+		}
+		return true
+	case FunctionKind:
+		if !value.accepts(len(target.Params)) {
+			return false
+		}
+		// A function may be passed where it is called with arguments its
+		// parameters accept
+		for idx := range target.Params {
+			if !assignable(value.param(idx), target.Params[idx]) {
+				return false
+			}
+		}
+		return target.Result == nil || assignable(target.Result, value.Result)
+	case TypeParamKind:
+		return target == value
+	case StructKind, EnumKind, InstanceKind, ErrorKind, StructTypeKind, EnumTypeKind, ContractKind, ErrorTypeKind, ModuleKind:
+		return target.Decl == value.Decl
+	default:
+		return target.Name == value.Name
+	}
+}
+
+// infer binds the type parameters that occur in a parameter type to the
+// corresponding parts of an argument type. Parameters bound by an earlier
+// argument keep their binding.
+func infer(param, arg *Type, bindings map[*Type]*Type) {
+	if param == nil || arg == nil {
+		return
+	}
+
+	switch param.Kind {
+	case TypeParamKind:
+		if _, bound := bindings[param]; !bound {
+			bindings[param] = arg
+		}
+	case OptionalKind:
+		if arg.Kind == OptionalKind {
+			arg = arg.Elem
+		}
+		infer(param.Elem, arg, bindings)
+	case ArrayKind:
+		if arg.Kind == ArrayKind {
+			infer(param.Elem, arg.Elem, bindings)
+		}
+	case MapKind:
+		if arg.Kind == MapKind {
+			infer(param.Key, arg.Key, bindings)
+			infer(param.Value, arg.Value, bindings)
+		}
+	case TupleKind:
+		if arg.Kind == TupleKind && len(arg.Elems) == len(param.Elems) {
+			for idx := range param.Elems {
+				infer(param.Elems[idx], arg.Elems[idx], bindings)
+			}
+		}
+	case FunctionKind:
+		if arg.Kind == FunctionKind && len(arg.Params) == len(param.Params) {
+			for idx := range param.Params {
+				infer(param.Params[idx], arg.Params[idx], bindings)
+			}
+			infer(param.Result, arg.Result, bindings)
+		}
+	}
+}
+
+// substitute replaces the type parameters in a type with the types bound
+// to them. Parameters that are not bound become unknown.
+func substitute(t *Type, bindings map[*Type]*Type) *Type {
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind {
+	case TypeParamKind:
+		return bindings[t]
+	case OptionalKind:
+		return NewOptional(substitute(t.Elem, bindings))
+	case ArrayKind:
+		return NewArray(substitute(t.Elem, bindings))
+	case MapKind:
+		return NewMap(substitute(t.Key, bindings), substitute(t.Value, bindings))
+	case TupleKind:
+		elems := []*Type{}
+		for _, e := range t.Elems {
+			elems = append(elems, substitute(e, bindings))
+		}
+		return NewTuple(elems)
+	case FunctionKind:
+		params := []*Type{}
+		for _, p := range t.Params {
+			params = append(params, substitute(p, bindings))
+		}
+		fn := NewFunction(params, substitute(t.Result, bindings))
+		fn.Sig = t.Sig
+		return fn
+	default:
+		return t
+	}
+}
+
+// unify returns the type shared by all of the given types, or nil if they
+// differ or any of them is unknown
+func unify(types ...*Type) *Type {
+	if len(types) == 0 {
+		return nil
+	}
+
+	first := types[0]
+	for _, t := range types {
+		if t == nil || first == nil || t.String() != first.String() || t.Decl != first.Decl {
+			return nil
+		}
+	}
+	return first
+}
+
+// arithmeticResult determines the type of an integer operation, following
+// the interpreter: a plain Int operand adopts the sized type of the other
+// operand, and two different sized types are an error
+func arithmeticResult(left, right *Type) (*Type, bool) {
+	switch {
+	case left == right:
+		return left, true
+	case left == Int:
+		return right, true
+	case right == Int:
+		return left, true
+	default:
+		return nil, false
+	}
+}
@@ -20,6 +20,11 @@ const (
 	BlockchainError ErrorType = "BlockchainError"
 	// ContractError represents an error in a smart contract
 	ContractError ErrorType = "ContractError"
+	// RevertError represents a contract call abandoned with revert
+	RevertError ErrorType = "RevertError"
+	// AssertionError represents a failed assert: a broken invariant rather
+	// than rejected input
+	AssertionError ErrorType = "AssertionError"
 )
 
 // Error represents a Stremax-Lang error
@@ -29,6 +34,10 @@ type Error struct {
 	Line    int
 	Column  int
 	File    string
+
+	// Data holds structured details of the failure, such as the custom
+	// error a contract reverted with; nil when there are none
+	Data interface{}
 }
 
 // Error returns a string representation of the error
@@ -83,6 +92,30 @@ func NewRuntimeError(message string, line, column int, file string) *Error {
 	}
 }
 
+// NewRevertError creates a new revert error carrying the error value the
+// call reverted with
+func NewRevertError(message string, data interface{}, line, column int, file string) *Error {
+	return &Error{
+		Type:    RevertError,
+		Message: message,
+		Line:    line,
+		Column:  column,
+		File:    file,
+		Data:    data,
+	}
+}
+
+// NewAssertionError creates a new assertion error
+func NewAssertionError(message string, line, column int, file string) *Error {
+	return &Error{
+		Type:    AssertionError,
+		Message: message,
+		Line:    line,
+		Column:  column,
+		File:    file,
+	}
+}
+
 // NewBlockchainError creates a new blockchain error
 func NewBlockchainError(message string) *Error {
 	return &Error{
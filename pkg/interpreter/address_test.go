@@ -0,0 +1,92 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddressLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0x00000000000000000000000000000000000000AB;", "0x00000000000000000000000000000000000000ab"},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed;", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{"Address(0);", "0x0000000000000000000000000000000000000000"},
+		{"Address(255);", "0x00000000000000000000000000000000000000ff"},
+		{`Address("0x00000000000000000000000000000000000000FF");`, "0x00000000000000000000000000000000000000ff"},
+		{"Address(0) == 0x0000000000000000000000000000000000000000;", "true"},
+		{"Address(1) != Address(0);", "true"},
+		{"0x00000000000000000000000000000000000000AB == 0x00000000000000000000000000000000000000ab;", "true"},
+		{"let a: Address = Address(7); a == Address(7);", "true"},
+		{"contract C {} let c = C(); Address(c) == c.address;", "true"},
+		{
+			// A hex literal of another length is still an integer
+			"0xff + 0x00000000000000000000000000000000000001;",
+			"256",
+		},
+		{
+			// Addresses are hashable, so they can key a map
+			`let balances: Map<Address, Int> = {};
+			balances[0x00000000000000000000000000000000000000a1] = 10;
+			balances[Address(0xa1)] += 5;
+			balances[0x00000000000000000000000000000000000000A1];`,
+			"15",
+		},
+		{
+			`contract Token {
+				state {
+					owner: Address
+					balances: Map<Address, Int>
+				}
+
+				constructor() {
+					owner = msg.sender;
+					balances[msg.sender] = 100;
+				}
+
+				function balanceOf(who: Address): Int {
+					return balances[who];
+				}
+			}
+
+			let t = Token();
+			[t.owner == Address(0), t.balanceOf(t.owner)];`,
+			"[false, 100]",
+		},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestAddressErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`Address("alice");`, `Cannot convert "alice" to Address: expected 0x followed by 40 hex digits`},
+		{`Address("0x00000000000000000000000000000000000000zz");`, "Cannot convert"},
+		{`Address("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD");`, "wrong checksum, expected 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{"Address(-1);", "Cannot convert -1 to Address: value out of range"},
+		{"Address(2 ** 160);", "value out of range"},
+		{"Address(true);", "Cannot convert BOOLEAN to Address"},
+		{"Address();", "Wrong number of arguments to Address: expected 1, got 0"},
+		{"let a: Address = 5;", "Type mismatch: expected Address, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+)
+
+// signature describes the parameters the arguments of a call are matched
+// against
+type signature struct {
+	name   string
+	params []*parser.ParameterStatement
+	token  parser.Token // the declaration of the callee
+}
+
+// signatureOf returns the parameters of a function, contract function,
+// contract constructor or error type declared in the program. Built-in
+// functions have no declared parameters.
+func signatureOf(function Object) (*signature, bool) {
+	switch fn := function.(type) {
+	case *Function:
+		name := fn.Name
+		if name == "" {
+			name = "function"
+		}
+		return &signature{name: name, params: fn.Parameters, token: fn.Token}, true
+	case *BoundMethod:
+		return signatureOf(fn.Method)
+	case *Contract:
+		for _, stmt := range fn.Statement.Body.Statements {
+			if constructor, ok := stmt.(*parser.ConstructorStatement); ok {
+				return &signature{name: fn.Name, params: constructor.Parameters, token: constructor.Token}, true
+			}
+		}
+		return &signature{name: fn.Name, token: fn.Statement.Token}, true
+	case *ErrorType:
+		return &signature{name: fn.Name, params: fn.Fields, token: fn.Token}, true
+	default:
+		return nil, false
+	}
+}
+
+// arrangeArguments matches the arguments of a call to the parameters of the
+// callee. Named arguments are passed to the parameter of that name and the
+// others in order. The result holds one argument per parameter, nil for an
+// omitted parameter that takes its default value, followed by the
+// arguments a variadic parameter collects. Arrangement is idempotent, so
+// callees can arrange the positional arguments they are given again.
+func (i *Interpreter) arrangeArguments(function Object, args []Object, names []*parser.Identifier, token parser.Token) ([]Object, error) {
+	sig, ok := signatureOf(function)
+	if !ok {
+		if names != nil {
+			name := typeName(function)
+			if builtin, ok := function.(*Builtin); ok {
+				name = builtin.Name
+			}
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("%s does not take named arguments", name),
+				token.Line, token.Column, "")
+		}
+		return args, nil
+	}
+
+	fixed := sig.params
+	variadic := len(fixed) != 0 && fixed[len(fixed)-1].Variadic
+	if variadic {
+		fixed = fixed[:len(fixed)-1]
+	}
+
+	arranged := make([]Object, len(fixed))
+	passed := make([]bool, len(fixed))
+	rest := []Object{}
+	positional := 0
+
+	for idx, arg := range args {
+		if names == nil || names[idx] == nil {
+			positional++
+			switch {
+			case idx < len(fixed):
+				arranged[idx], passed[idx] = arg, true
+			case variadic:
+				rest = append(rest, arg)
+			default:
+				return nil, sig.arityError(len(args), token)
+			}
+			continue
+		}
+
+		name := names[idx]
+		param := sig.param(name.Value)
+		switch {
+		case param < 0:
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Unknown parameter %s in call to %s%s", name.Value, sig.name, declaredAt(sig.name, sig.token)),
+				name.Token.Line, name.Token.Column, "")
+		case param == len(fixed):
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Variadic parameter %s of %s cannot be passed by name%s", name.Value, sig.name, declaredAt(sig.name, sig.token)),
+				name.Token.Line, name.Token.Column, "")
+		case passed[param]:
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Parameter %s of %s is passed more than once%s", name.Value, sig.name, declaredAt(sig.name, sig.token)),
+				name.Token.Line, name.Token.Column, "")
+		}
+		arranged[param], passed[param] = arg, true
+	}
+
+	for idx, param := range fixed {
+		if passed[idx] || param.Default != nil {
+			continue
+		}
+		if names == nil {
+			return nil, sig.arityError(positional, token)
+		}
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Missing argument for parameter %s of %s%s", param.Name.Value, sig.name, declaredAt(sig.name, sig.token)),
+			token.Line, token.Column, "")
+	}
+
+	return append(arranged, rest...), nil
+}
+
+// param returns the index of the named parameter, or -1 if there is none
+func (s *signature) param(name string) int {
+	for idx, param := range s.params {
+		if param.Name.Value == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+// arityError creates the error raised when a call passes too many or too
+// few arguments
+func (s *signature) arityError(got int, token parser.Token) error {
+	required, total := 0, 0
+	variadic := false
+	for _, param := range s.params {
+		switch {
+		case param.Variadic:
+			variadic = true
+		case param.Default == nil:
+			required++
+			total++
+		default:
+			total++
+		}
+	}
+
+	expected := fmt.Sprintf("%d", required)
+	switch {
+	case variadic:
+		expected = fmt.Sprintf("at least %d", required)
+	case total == required+1:
+		expected = fmt.Sprintf("%d or %d", required, total)
+	case total > required:
+		expected = fmt.Sprintf("%d to %d", required, total)
+	}
+
+	return errors.NewTypeError(
+		fmt.Sprintf("Wrong number of arguments to %s: expected %s, got %d%s", s.name, expected, got, declaredAt(s.name, s.token)),
+		token.Line, token.Column, "")
+}
+
+// declaredAt describes where a callee is declared, for errors in calls of
+// it. Built-in callees have no declaration to point to.
+func declaredAt(name string, token parser.Token) string {
+	if token.Line == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s is declared at line %d, column %d)", name, token.Line, token.Column)
+}
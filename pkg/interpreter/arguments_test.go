@@ -0,0 +1,111 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"function mint(to: String, amount: Int = 1): Int { return amount; }\nmint(\"a\");", "1"},
+		{"function mint(to: String, amount: Int = 1): Int { return amount; }\nmint(\"a\", 5);", "5"},
+		// Defaults are evaluated at each call and can use earlier parameters
+		{"function f(a: Int, b: Int = a * 2): Int { return b; }\nf(3);", "6"},
+		{"function f(xs: Array<Int> = []): Int { xs.push(1); return len(xs); }\nf();\nf();", "1"},
+		{"let f = function(x, y = 10) { x + y; };\nf(1);", "11"},
+		// Defaults are coerced to the declared type
+		{"function f(n: Uint8 = 1): String { return typeof(n); }\nf();", "Uint8"},
+		{"contract C {\n function who(account: Address = msg.sender): Address { return account; }\n function caller(): Address { return msg.sender; }\n}\nlet c = C();\nc.who() == c.caller();", "true"},
+		{"contract C {\n state { fee: Int }\n constructor(fee_: Int = 3) { fee = fee_; }\n function get(): Int { return fee; }\n}\nC().get();", "3"},
+		{"error Low(needed: Int, available: Int = 0)\nLow(3);", "Low(needed: 3, available: 0)"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNamedArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"function sub(a: Int, b: Int): Int { return a - b; }\nsub(b: 1, a: 5);", "4"},
+		{"function sub(a: Int, b: Int): Int { return a - b; }\nsub(5, b: 1);", "4"},
+		{"function f(a: Int = 1, b: Int = 2, c: Int = 3): String { return toString([a, b, c]); }\nf(c: 9);", "[1, 2, 9]"},
+		{"contract C {\n function sub(a: Int, b: Int): Int { return a - b; }\n}\nlet c = C();\nc.sub(b: 2, a: 10);", "8"},
+		{"contract C {\n state { owner: String }\n constructor(owner_: String, fee: Int = 1) { owner = owner_; }\n function get(): String { return owner; }\n}\nC(fee: 2, owner_: \"alice\").get();", "alice"},
+		{"error Low(needed: Int, available: Int)\nLow(available: 1, needed: 3);", "Low(needed: 3, available: 1)"},
+		{"contract C {\n function f(a: Int, b: Int): Int { return a - b; }\n}\nlet c = C();\ntry c.f(b: 1, a: 3) as r { r; } catch (e) { -1; }", "2"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestVariadicParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"function sum(...values: Int): Int { let s = 0; for (v in values) { s += v; } return s; }\nsum(1, 2, 3);", "6"},
+		{"function sum(...values: Int): Int { return len(values); }\nsum();", "0"},
+		{"function f(...values: Uint8): String { return typeof(values[0]); }\nf(1);", "Uint8"},
+		{"function log(prefix: String, ...parts: String): String { return prefix + toString(parts); }\nlog(\"> \", \"a\", \"b\");", "> [a, b]"},
+		{"function f(level: Int = 0, ...rest: Int): Int { return level + len(rest); }\nf(level: 5);", "5"},
+		{"let f = function(...xs) { len(xs); };\nf(1, \"a\", true);", "3"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestArgumentErrors(t *testing.T) {
+	mint := "function mint(to: String, amount: Int = 1): Int { return amount; }\n"
+
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{mint + "mint();", "Wrong number of arguments to mint: expected 1 or 2, got 0 (mint is declared at line 1, column 1)"},
+		{mint + "mint(\"a\", 1, 2);", "Wrong number of arguments to mint: expected 1 or 2, got 3 (mint is declared at line 1, column 1)"},
+		{mint + "mint(amount: 2);", "Missing argument for parameter to of mint (mint is declared at line 1, column 1)"},
+		{mint + "mint(\"a\", value: 2);", "Unknown parameter value in call to mint (mint is declared at line 1, column 1) at :2:11"},
+		{mint + "mint(\"a\", to: \"b\");", "Parameter to of mint is passed more than once (mint is declared at line 1, column 1) at :2:11"},
+		{"function sum(...values: Int) { }\nsum(values: 1);", "Variadic parameter values of sum cannot be passed by name (sum is declared at line 1, column 1)"},
+		{"function f(a: Int, b: Int, c: Int = 1) { }\nf(1);", "Wrong number of arguments to f: expected 2 or 3, got 1"},
+		{"function f(a: Int, b: Int = 1, c: Int = 2) { }\nf();", "Wrong number of arguments to f: expected 1 to 3, got 0"},
+		{"function f(a: Int, ...b: Int) { }\nf();", "Wrong number of arguments to f: expected at least 1, got 0"},
+		{"function f(...values: Int) { }\nf(1, \"a\");", "Type mismatch: expected Int, got STRING"},
+		{"function f(a: Int = \"a\") { }\nf();", "Type mismatch: expected Int, got STRING"},
+		{"contract C {\n constructor(owner: String) { }\n}\nC();", "Wrong number of arguments to C: expected 1, got 0 (C is declared at line 2, column 2)"},
+		{"contract C { }\nC(1);", "Wrong number of arguments: C has no constructor, got 1 arguments (C is declared at line 1, column 1)"},
+		{"error Low(needed: Int)\nLow(have: 1);", "Unknown parameter have in call to Low (Low is declared at line 1, column 1)"},
+		{"len(value: [1]);", "len does not take named arguments"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
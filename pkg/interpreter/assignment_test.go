@@ -0,0 +1,161 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 1; x = 5; x;", 5},
+		{"let x = 1; x = x + 1; x;", 2},
+		{"let x = 1; let y = x = 7; y;", 7},
+		{"let x = 1; let y = 2; x = y = 3; x + y;", 6},
+		{"let x = 10; x += 5; x;", 15},
+		{"let x = 10; x -= 4; x;", 6},
+		{"let x = 10; x *= 3; x;", 30},
+		{"let x = 10; x /= 2; x;", 5},
+		{"let x = 10; x += 2 * 3; x;", 16},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestAssignmentToOuterScope(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{
+			`
+			let x = 1;
+			if (true) {
+				x = x + 1;
+			}
+			x;
+			`,
+			2,
+		},
+		{
+			`
+			let x = 1;
+			if (true) {
+				if (true) {
+					x += 10;
+				}
+			}
+			x;
+			`,
+			11,
+		},
+		{
+			// Assigning to a shadowing variable leaves the outer binding alone
+			`
+			let x = 1;
+			if (true) {
+				let x = 5;
+				x = 50;
+			}
+			x;
+			`,
+			1,
+		},
+		{
+			`
+			let counter = 10;
+			let increment = function() { counter += 1; };
+			increment();
+			increment();
+			counter;
+			`,
+			12,
+		},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestIncrementDecrement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let i = 0; i++; i;", 1},
+		{"let i = 0; i--; i;", -1},
+		{"let i = 5; i++; i++; i--; i;", 6},
+		{"let i = 5; let j = i++; j;", 5},
+		{"let a = [1, 2]; a[1]++; a[1];", 3},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestIndexAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let a = [1, 2, 3]; a[0] = 10; a[0];", 10},
+		{"let a = [1, 2, 3]; a[2] += 5; a[2];", 8},
+		{"let m = {\"a\": 1}; m[\"a\"] = 2; m[\"a\"];", 2},
+		{"let m = {\"a\": 1}; m[\"b\"] = 3; m[\"b\"];", 3},
+		{"let balances = {\"alice\": 100}; let to = \"alice\"; balances[to] += 50; balances[to];", 150},
+		{"let m = {1: [1, 2]}; m[1][0] = 9; m[1][0];", 9},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestDotAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let m = {\"votes\": 1}; m.votes = 5; m.votes;", 5},
+		{"let m = {\"votes\": 1}; m.votes += 2; m[\"votes\"];", 3},
+		{"let m = {\"inner\": {\"n\": 1}}; m.inner.n++; m.inner.n;", 2},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestAssignmentErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"x = 5;", "Cannot assign to undeclared variable: x"},
+		{"let a = [1]; a[3] = 1;", "array index out of range: 3"},
+		{"let s = \"hi\"; s++;", "Operator ++ requires an integer"},
+		{"let x = 1; x += true;", "Type mismatch: INTEGER + BOOLEAN"},
+		{"let x = 1; x.y = 2;", "field assignment not supported"},
+	}
+
+	for _, tt := range tests {
+		interpreter := New(tt.input)
+		err := runUnchecked(interpreter)
+
+		if err == nil {
+			t.Errorf("expected error but got none for input: %s", tt.input)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("wrong error message. expected=%q to contain %q for input: %s",
+				err.Error(), tt.expectedError, tt.input)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/blockchain"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// BuiltinFunction is the Go implementation of a built-in function
+type BuiltinFunction func(args ...Object) (Object, error)
+
+// Builtin represents a function provided by the interpreter
+type Builtin struct {
+	Name string
+	Fn   BuiltinFunction
+}
+
+// Type returns the type of the Builtin object
+func (b *Builtin) Type() string { return "BUILTIN" }
+
+// Inspect returns a string representation of the Builtin object
+func (b *Builtin) Inspect() string { return fmt.Sprintf("builtin function %s", b.Name) }
+
+// builtins holds the default built-in functions. Every interpreter loads
+// them into its outermost environment.
+var builtins = make(map[string]*Builtin)
+
+func init() {
+	// Explicit integer conversions: Uint8(x), Int64(x), Int(x), ...
+	for name, kind := range intTypes {
+		registerBuiltin(name, castBuiltin(name, kind))
+	}
+	registerBuiltin("Int", castBuiltin("Int", nil))
+
+	registerBuiltin("Address", addressBuiltin)
+
+	registerBuiltin("len", lenBuiltin)
+	registerBuiltin("range", rangeBuiltin)
+	registerBuiltin("split", splitBuiltin)
+	registerBuiltin("join", joinBuiltin)
+	registerBuiltin("toString", toStringBuiltin)
+	registerBuiltin("parseInt", parseIntBuiltin)
+	registerBuiltin("typeof", typeofBuiltin)
+
+	registerBuiltin("wrapping_add", wrappingBuiltin("wrapping_add", "+", (*big.Int).Add))
+	registerBuiltin("wrapping_sub", wrappingBuiltin("wrapping_sub", "-", (*big.Int).Sub))
+	registerBuiltin("wrapping_mul", wrappingBuiltin("wrapping_mul", "*", (*big.Int).Mul))
+	registerBuiltin("saturating_add", saturatingBuiltin("saturating_add", "+", (*big.Int).Add))
+	registerBuiltin("saturating_sub", saturatingBuiltin("saturating_sub", "-", (*big.Int).Sub))
+	registerBuiltin("saturating_mul", saturatingBuiltin("saturating_mul", "*", (*big.Int).Mul))
+}
+
+// registerBuiltin adds a built-in function to the registry
+func registerBuiltin(name string, fn BuiltinFunction) {
+	builtins[name] = &Builtin{Name: name, Fn: fn}
+}
+
+// castBuiltin converts an integer to the given sized type, failing if the
+// value does not fit. A nil kind converts to plain Int.
+func castBuiltin(name string, kind *IntType) BuiltinFunction {
+	return func(args ...Object) (Object, error) {
+		if len(args) != 1 {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Wrong number of arguments to %s: expected 1, got %d", name, len(args)), 0, 0, "")
+		}
+
+		integer, ok := args[0].(*Integer)
+		if !ok {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Cannot convert %s to %s", args[0].Type(), name), 0, 0, "")
+		}
+
+		if !rangeOf(kind).Contains(integer.Value) {
+			return nil, errors.NewRuntimeError(
+				fmt.Sprintf("Cannot convert %s to %s: value out of range", integer.Value, name), 0, 0, "")
+		}
+
+		return &Integer{Value: integer.Value, Kind: kind}, nil
+	}
+}
+
+// maxAddress is the largest integer that converts to an address
+var maxAddress = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+
+// addressBuiltin converts an integer, a string or a deployed contract to an
+// Address. Address(0) is the zero address.
+func addressBuiltin(args ...Object) (Object, error) {
+	if len(args) != 1 {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Wrong number of arguments to Address: expected 1, got %d", len(args)), 0, 0, "")
+	}
+
+	switch arg := args[0].(type) {
+	case *Address:
+		return arg, nil
+	case *ContractInstance:
+		return &Address{Value: arg.Address}, nil
+	case *Integer:
+		if arg.Value.Sign() < 0 || arg.Value.Cmp(maxAddress) > 0 {
+			return nil, errors.NewRuntimeError(
+				fmt.Sprintf("Cannot convert %s to Address: value out of range", arg.Value), 0, 0, "")
+		}
+		return &Address{Value: blockchain.Address(fmt.Sprintf("0x%040x", arg.Value))}, nil
+	case *String:
+		address, err := blockchain.ParseAddress(arg.Value)
+		if err != nil {
+			reason := strings.TrimPrefix(err.Error(), fmt.Sprintf("invalid address %q: ", arg.Value))
+			return nil, errors.NewRuntimeError(
+				fmt.Sprintf("Cannot convert %q to Address: %s", arg.Value, reason), 0, 0, "")
+		}
+		return &Address{Value: address}, nil
+	default:
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Cannot convert %s to Address", args[0].Type()), 0, 0, "")
+	}
+}
+
+// wrappingBuiltin performs an arithmetic operation that wraps around at the
+// bounds of the operands' type instead of failing
+func wrappingBuiltin(name, operator string, op func(z, x, y *big.Int) *big.Int) BuiltinFunction {
+	return func(args ...Object) (Object, error) {
+		left, right, kind, err := integerOperands(name, operator, args)
+		if err != nil {
+			return nil, err
+		}
+
+		result := op(new(big.Int), left.Value, right.Value)
+		return &Integer{Value: rangeOf(kind).Wrap(result), Kind: kind}, nil
+	}
+}
+
+// saturatingBuiltin performs an arithmetic operation that clamps to the
+// bounds of the operands' type instead of failing
+func saturatingBuiltin(name, operator string, op func(z, x, y *big.Int) *big.Int) BuiltinFunction {
+	return func(args ...Object) (Object, error) {
+		left, right, kind, err := integerOperands(name, operator, args)
+		if err != nil {
+			return nil, err
+		}
+
+		result := op(new(big.Int), left.Value, right.Value)
+		return &Integer{Value: rangeOf(kind).Saturate(result), Kind: kind}, nil
+	}
+}
+
+// integerOperands validates the two integer arguments of an arithmetic
+// builtin and determines the type of its result
+func integerOperands(name, operator string, args []Object) (*Integer, *Integer, *IntType, error) {
+	if len(args) != 2 {
+		return nil, nil, nil, errors.NewTypeError(
+			fmt.Sprintf("Wrong number of arguments to %s: expected 2, got %d", name, len(args)), 0, 0, "")
+	}
+
+	left, ok := args[0].(*Integer)
+	if !ok {
+		return nil, nil, nil, errors.NewTypeError(
+			fmt.Sprintf("%s requires integer arguments, got %s", name, args[0].Type()), 0, 0, "")
+	}
+	right, ok := args[1].(*Integer)
+	if !ok {
+		return nil, nil, nil, errors.NewTypeError(
+			fmt.Sprintf("%s requires integer arguments, got %s", name, args[1].Type()), 0, 0, "")
+	}
+
+	kind, err := resultKind(operator, left, right)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return left, right, kind, nil
+}
+
+// lenBuiltin returns the number of characters in a string, elements in an
+// array or entries in a map
+func lenBuiltin(args ...Object) (Object, error) {
+	if err := checkArgumentCount("len", args, 1); err != nil {
+		return nil, err
+	}
+
+	switch arg := args[0].(type) {
+	case *String:
+		return newInteger(int64(utf8.RuneCountInString(arg.Value))), nil
+	case *Array:
+		return newInteger(int64(len(arg.Elements))), nil
+	case *Tuple:
+		return newInteger(int64(len(arg.Elements))), nil
+	case *Hash:
+		return newInteger(int64(arg.Len())), nil
+	default:
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("len is not supported for %s", typeName(arg)), 0, 0, "")
+	}
+}
+
+// maxRangeLength is the largest number of integers range builds, so that a
+// huge span fails instead of exhausting memory
+const maxRangeLength = 1000000
+
+// rangeBuiltin returns the integers from start up to, but not including,
+// end: range(end) starts at 0, range(start, end) at start
+func rangeBuiltin(args ...Object) (Object, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Wrong number of arguments to range: expected 1 or 2, got %d", len(args)), 0, 0, "")
+	}
+
+	bounds := []*big.Int{new(big.Int)}
+	if len(args) == 2 {
+		bounds = nil
+	}
+	for _, arg := range args {
+		integer, ok := arg.(*Integer)
+		if !ok {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("range requires integer arguments, got %s", typeName(arg)), 0, 0, "")
+		}
+		bounds = append(bounds, integer.Value)
+	}
+
+	span := new(big.Int).Sub(bounds[1], bounds[0])
+	if span.Cmp(big.NewInt(maxRangeLength)) > 0 {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("range of %s integers exceeds the maximum of %d", span, maxRangeLength), 0, 0, "")
+	}
+
+	elements := []Object{}
+	for n := new(big.Int).Set(bounds[0]); n.Cmp(bounds[1]) < 0; n.Add(n, big.NewInt(1)) {
+		elements = append(elements, &Integer{Value: new(big.Int).Set(n)})
+	}
+	return &Array{Elements: elements}, nil
+}
+
+// splitBuiltin splits a string around every occurrence of a separator
+func splitBuiltin(args ...Object) (Object, error) {
+	if err := checkArgumentCount("split", args, 2); err != nil {
+		return nil, err
+	}
+
+	str, ok := args[0].(*String)
+	sep, ok2 := args[1].(*String)
+	if !ok || !ok2 {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("split requires String arguments, got %s and %s", typeName(args[0]), typeName(args[1])), 0, 0, "")
+	}
+
+	elements := []Object{}
+	for _, part := range strings.Split(str.Value, sep.Value) {
+		elements = append(elements, &String{Value: part})
+	}
+	return &Array{Elements: elements}, nil
+}
+
+// joinBuiltin joins the elements of an array into a string
+func joinBuiltin(args ...Object) (Object, error) {
+	if err := checkArgumentCount("join", args, 2); err != nil {
+		return nil, err
+	}
+
+	arr, ok := args[0].(*Array)
+	sep, ok2 := args[1].(*String)
+	if !ok || !ok2 {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("join requires an Array and a String, got %s and %s", typeName(args[0]), typeName(args[1])), 0, 0, "")
+	}
+
+	parts := []string{}
+	for _, e := range arr.Elements {
+		parts = append(parts, e.Inspect())
+	}
+	return &String{Value: strings.Join(parts, sep.Value)}, nil
+}
+
+// toStringBuiltin converts any value to its string representation
+func toStringBuiltin(args ...Object) (Object, error) {
+	if err := checkArgumentCount("toString", args, 1); err != nil {
+		return nil, err
+	}
+	return &String{Value: args[0].Inspect()}, nil
+}
+
+// parseIntBuiltin converts a decimal string to an Int
+func parseIntBuiltin(args ...Object) (Object, error) {
+	if err := checkArgumentCount("parseInt", args, 1); err != nil {
+		return nil, err
+	}
+
+	str, ok := args[0].(*String)
+	if !ok {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("parseInt requires a String, got %s", typeName(args[0])), 0, 0, "")
+	}
+
+	value, ok := new(big.Int).SetString(strings.TrimSpace(str.Value), 10)
+	if !ok {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("Cannot parse %q as Int", str.Value), 0, 0, "")
+	}
+	if !inIntRange(value) {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("Cannot parse %q as Int: value out of range", str.Value), 0, 0, "")
+	}
+	return &Integer{Value: value}, nil
+}
+
+// typeofBuiltin returns the name of the type of a value
+func typeofBuiltin(args ...Object) (Object, error) {
+	if err := checkArgumentCount("typeof", args, 1); err != nil {
+		return nil, err
+	}
+	return &String{Value: typeName(args[0])}, nil
+}
+
+// nowBuiltin returns the timestamp of the current block in seconds since the
+// Unix epoch, so every call while the block is built sees the same time
+func (i *Interpreter) nowBuiltin(args ...Object) (Object, error) {
+	if err := checkArgumentCount("now", args, 0); err != nil {
+		return nil, err
+	}
+	return &Integer{Value: big.NewInt(i.bc.BlockTime.Unix()), Kind: intTypes["Uint256"]}, nil
+}
+
+// printBuiltin writes its arguments, separated by spaces, to the
+// interpreter's output
+func (i *Interpreter) printBuiltin(args ...Object) (Object, error) {
+	parts := []string{}
+	for _, arg := range args {
+		parts = append(parts, arg.Inspect())
+	}
+
+	fmt.Fprintln(i.out, strings.Join(parts, " "))
+	return NULL, nil
+}
+
+// typeName returns the name of the type of a value as it is written in
+// Stremax-Lang code, such as Uint8, Map or the name of a struct
+func typeName(obj Object) string {
+	switch obj := obj.(type) {
+	case *Integer:
+		if obj.Kind != nil {
+			return obj.Kind.Name
+		}
+		return "Int"
+	case *String:
+		return "String"
+	case *Boolean:
+		return "Bool"
+	case *Address:
+		return "Address"
+	case *Array:
+		return "Array"
+	case *Tuple:
+		return "Tuple"
+	case *Hash:
+		return "Map"
+	case *Null:
+		return "Null"
+	case *Struct:
+		return obj.Definition.Name
+	case *EnumValue:
+		return obj.Enum.Name
+	case *ErrorValue:
+		return obj.Definition.Name
+	case *ContractInstance:
+		return obj.Contract.Name
+	case *Function, *Builtin, *BoundMethod:
+		return "Function"
+	default:
+		return obj.Type()
+	}
+}
@@ -0,0 +1,138 @@
+package interpreter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCoreBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`len("héllo");`, "5"},
+		{`len([1, 2, 3]);`, "3"},
+		{`len({"a": 1});`, "1"},
+		{`range(3);`, "[0, 1, 2]"},
+		{`range(2, 5);`, "[2, 3, 4]"},
+		{`range(5, 2);`, "[]"},
+		{`len(range(1000000));`, "1000000"},
+		{`split("a,b,c", ",");`, "[a, b, c]"},
+		{`join([1, "two", true], "-");`, "1-two-true"},
+		{`toString(42) + "!";`, "42!"},
+		{`parseInt("-17") + 1;`, "-16"},
+		{`typeof(1);`, "Int"},
+		{`typeof(Uint8(1));`, "Uint8"},
+		{`typeof("s");`, "String"},
+		{`typeof(true);`, "Bool"},
+		{`typeof(Address(0));`, "Address"},
+		{`typeof([]);`, "Array"},
+		{`typeof({});`, "Map"},
+		{`typeof(len);`, "Function"},
+		{`struct P { x: Int } typeof(P {});`, "P"},
+		{`now() > 1600000000;`, "true"},
+		{`typeof(now());`, "Uint256"},
+		{
+			// Built-ins can be shadowed by program bindings
+			`let len = 7; len;`,
+			"7",
+		},
+		{`function f() { let range = 1; return range; } [f(), len(range(2))];`, "[1, 2]"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`len(1);`, "len is not supported for Int"},
+		{`len();`, "Wrong number of arguments to len: expected 1, got 0"},
+		{`range("a");`, "range requires integer arguments, got String"},
+		{`range(1, 2, 3);`, "Wrong number of arguments to range: expected 1 or 2, got 3"},
+		{`range(10 ** 12);`, "range of 1000000000000 integers exceeds the maximum of 1000000"},
+		{`range(-1, 1000000);`, "range of 1000001 integers exceeds the maximum of 1000000"},
+		{`split("a", 1);`, "split requires String arguments, got String and Int"},
+		{`join("a", ",");`, "join requires an Array and a String"},
+		{`parseInt("12abc");`, `Cannot parse "12abc" as Int`},
+		{`parseInt(12);`, "parseInt requires a String, got Int"},
+		{`now(1);`, "Wrong number of arguments to now: expected 0, got 1"},
+		{"let x = 1;\n  len(x);", "at :2:6"},
+	}
+
+	for _, tt := range tests {
+		err := New(tt.input).Run()
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
+
+func TestPrint(t *testing.T) {
+	var out bytes.Buffer
+
+	interp := New(`print("total:", 1 + 2, [true]); print();`)
+	interp.SetOutput(&out)
+	if err := interp.Run(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "total: 3 [true]\n\nResult: null\n"
+	if out.String() != expected {
+		t.Errorf("wrong output. got=%q, want=%q", out.String(), expected)
+	}
+}
+
+func TestNowReadsBlockTime(t *testing.T) {
+	var out bytes.Buffer
+
+	interp := New(`let start = now(); [start, now() - start];`)
+	interp.SetOutput(&out)
+	interp.Blockchain().BlockTime = time.Unix(1700000000, 0)
+
+	if err := interp.Run(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out.String() != "Result: [1700000000, 0]\n" {
+		t.Errorf("wrong output. got=%q, want=%q", out.String(), "Result: [1700000000, 0]\n")
+	}
+}
+
+func TestRegisterBuiltin(t *testing.T) {
+	var out bytes.Buffer
+
+	interp := New(`double(21);`)
+	interp.SetOutput(&out)
+	interp.RegisterBuiltin("double", func(args ...Object) (Object, error) {
+		return &Integer{Value: args[0].(*Integer).Value}, nil
+	})
+	interp.RegisterBuiltin("double", func(args ...Object) (Object, error) {
+		n := args[0].(*Integer)
+		return newInteger(n.Value.Int64() * 2), nil
+	})
+
+	if err := interp.Run(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out.String() != "Result: 42\n" {
+		t.Errorf("wrong output. got=%q, want=%q", out.String(), "Result: 42\n")
+	}
+
+	// Registering on one interpreter does not affect others
+	if err := New(`double(1);`).Run(); err == nil || !strings.Contains(err.Error(), "Identifier not found: double") {
+		t.Errorf("expected double to be undefined in a new interpreter, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package interpreter
+
+// checkpoint records the state a failed contract call is rolled back to:
+// the state fields of every deployed contract, the contents of the arrays,
+// maps and structs they hold, and the blockchain's pending transactions and
+// deployed contracts. Values are restored in place, so references to them
+// held elsewhere see the rolled back contents.
+type checkpoint struct {
+	i            *Interpreter
+	instances    int                                // the number of deployed contracts
+	transactions int                                // the number of pending transactions
+	stores       map[*Environment]map[string]Object // the bindings of each contract's state
+	values       map[Object]Object                  // mutable values and copies of their contents
+}
+
+// checkpoint records the current state of every deployed contract
+func (i *Interpreter) checkpoint() *checkpoint {
+	cp := &checkpoint{
+		i:            i,
+		instances:    len(i.instances),
+		transactions: len(i.bc.PendingTransactions),
+		stores:       make(map[*Environment]map[string]Object),
+		values:       make(map[Object]Object),
+	}
+
+	for _, instance := range i.instances {
+		store := make(map[string]Object, len(instance.State.store))
+		for name, val := range instance.State.store {
+			store[name] = val
+			cp.save(val)
+		}
+		cp.stores[instance.State] = store
+	}
+
+	return cp
+}
+
+// save records the contents of a mutable value and of the values it holds
+func (cp *checkpoint) save(obj Object) {
+	if _, ok := cp.values[obj]; ok {
+		return
+	}
+
+	switch obj := obj.(type) {
+	case *Array:
+		elements := make([]Object, len(obj.Elements))
+		copy(elements, obj.Elements)
+		cp.values[obj] = &Array{Elements: elements}
+		for _, el := range elements {
+			cp.save(el)
+		}
+	case *Hash:
+		saved := obj.clone()
+		cp.values[obj] = saved
+		for _, pair := range saved.Entries() {
+			cp.save(pair.Value)
+		}
+	case *Struct:
+		saved := &Struct{Fields: make(map[string]Object, len(obj.Fields))}
+		for name, val := range obj.Fields {
+			saved.Fields[name] = val
+		}
+		cp.values[obj] = saved
+		for _, val := range saved.Fields {
+			cp.save(val)
+		}
+	case *Tuple:
+		// Tuples cannot change, but the values they hold can
+		for _, el := range obj.Elements {
+			cp.save(el)
+		}
+	}
+}
+
+// rollback restores the state recorded by the checkpoint, forgetting the
+// contracts deployed and the transactions made since
+func (cp *checkpoint) rollback() {
+	i := cp.i
+
+	for env, store := range cp.stores {
+		env.store = store
+	}
+
+	for obj, saved := range cp.values {
+		switch obj := obj.(type) {
+		case *Array:
+			obj.Elements = saved.(*Array).Elements
+		case *Hash:
+			obj.entries, obj.buckets = saved.(*Hash).entries, saved.(*Hash).buckets
+		case *Struct:
+			obj.Fields = saved.(*Struct).Fields
+		}
+	}
+
+	for _, instance := range i.instances[cp.instances:] {
+		delete(i.bc.Contracts, instance.Address)
+	}
+	i.instances = i.instances[:cp.instances]
+	i.bc.PendingTransactions = i.bc.PendingTransactions[:cp.transactions]
+}
@@ -0,0 +1,114 @@
+package interpreter
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConstants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const MAX = 10;\nMAX * 2;", "20"},
+		{"const DECIMALS: Uint8 = 18;\ntypeof(DECIMALS);", "Uint8"},
+		// Only the binding is constant; the value it holds may change
+		{"const items = [1];\nitems.push(2);\nitems.length;", "2"},
+		// Inner scopes may shadow a constant
+		{"const x = 1;\nlet y = if (true) { let x = 5; x = x + 1; x };\nx + y;", "7"},
+		{"const x = 1;\nfunction f(x: Int) { x = x + 1; return x; }\nf(x);", "2"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestImmutableStateFields(t *testing.T) {
+	input := `
+	contract Token {
+		state {
+			const decimals: Uint8 = 18
+			immutable name: String
+			immutable owner: Address
+			supply: Int
+		}
+
+		constructor(tokenName: String) {
+			name = tokenName;
+			owner = msg.sender;
+			supply = 1000;
+		}
+
+		function describe(): String {
+			return name + "/" + toString(decimals);
+		}
+	}
+
+	let t = Token("Stremax");
+	t.describe();
+	`
+
+	result := testEval(t, input)
+	if result == nil || result.Inspect() != "Stremax/18" {
+		t.Errorf("wrong result. got=%v, want=Stremax/18", result)
+	}
+}
+
+func TestConstantReassignmentErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		// Targets known from the program text are rejected before it runs
+		{"const x = 1;\nx = 2;", "TypeError: Cannot assign to constant x at :2:1"},
+		{"const x = 1;\nx += 2;", "TypeError: Cannot assign to constant x at :2:1"},
+		{"const x = 1;\nx++;", "TypeError: Cannot assign to constant x at :2:1"},
+		{"const x = 1;\nfunction f() { x = 2; }", "TypeError: Cannot assign to constant x at :2:16"},
+		{"const x = 1;\nconst x = 2;", "TypeError: Cannot redeclare constant x at :2:7"},
+		{
+			"contract C {\n state { const max: Int = 1 }\n constructor() { max = 2; }\n}",
+			"TypeError: Cannot assign to constant max at :3:18",
+		},
+		{
+			"contract C {\n state { immutable owner: Address }\n function take() { owner = msg.sender; }\n}",
+			"TypeError: Cannot assign to immutable state field owner outside the constructor at :3:20",
+		},
+		// Other targets are rejected when the assignment runs
+		{"function f() { x = 2; }\nconst x = 1;\nf();", "TypeError: Cannot assign to constant x at :1:16"},
+		{
+			"contract C {\n state { immutable owner: Address }\n function take() { let set = function() { owner = msg.sender; }; set(); }\n}\nC().take();",
+			"TypeError: Cannot assign to immutable state field owner outside the constructor at :3:43",
+		},
+	}
+
+	for _, tt := range tests {
+		i := New(tt.input)
+		i.SetOutput(&bytes.Buffer{})
+		err := i.Run()
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if err.Error() != tt.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expectedError, err.Error())
+		}
+	}
+}
+
+func TestImportedConstantsStayConstant(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"config.sx": `const LIMIT = 5;`,
+		"main.sx":   "import { LIMIT } from \"config.sx\"\nLIMIT = 6;",
+	})
+
+	_, err := evalFile(t, filepath.Join(dir, "main.sx"))
+	if err == nil || !strings.Contains(err.Error(), "Cannot assign to constant LIMIT") {
+		t.Errorf("expected constant assignment error, got %v", err)
+	}
+}
@@ -0,0 +1,272 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/blockchain"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"math/big"
+)
+
+// defaultSender is the account that deploys and calls contracts when the
+// embedder has not chosen one
+const defaultSender = blockchain.Address("0x0000000000000000000000000000000000000001")
+
+// Contract represents a contract declaration. Calling it deploys a new
+// instance, passing the arguments to the constructor.
+type Contract struct {
+	Name      string
+	Statement *parser.ContractStatement
+	Env       *Environment // the environment the contract was declared in
+	Strict    bool         // whether the contract was declared in strict mode
+}
+
+// Type returns the type of the Contract object
+func (c *Contract) Type() string { return "CONTRACT" }
+
+// Inspect returns a string representation of the Contract object
+func (c *Contract) Inspect() string { return fmt.Sprintf("contract %s", c.Name) }
+
+// ContractInstance represents a deployed contract. Its state fields and
+// functions live in the State environment.
+type ContractInstance struct {
+	Contract *Contract
+	Address  blockchain.Address
+	State    *Environment
+}
+
+// Type returns the type of the ContractInstance object
+func (ci *ContractInstance) Type() string { return "CONTRACT_INSTANCE" }
+
+// Inspect returns a string representation of the ContractInstance object
+func (ci *ContractInstance) Inspect() string {
+	return fmt.Sprintf("%s@%s", ci.Contract.Name, ci.Address)
+}
+
+// BoundMethod represents a contract function bound to a deployed instance
+type BoundMethod struct {
+	Instance *ContractInstance
+	Method   *Function
+}
+
+// Type returns the type of the BoundMethod object
+func (bm *BoundMethod) Type() string { return "BOUND_METHOD" }
+
+// Inspect returns a string representation of the BoundMethod object
+func (bm *BoundMethod) Inspect() string {
+	return fmt.Sprintf("function %s.%s", bm.Instance.Contract.Name, bm.Method.Name)
+}
+
+// evalContractStatement evaluates a contract statement, binding the
+// contract name so that it can be deployed
+func (i *Interpreter) evalContractStatement(stmt *parser.ContractStatement) (Object, error) {
+	// State fields and functions share the state environment, so a function
+	// would silently replace a field of the same name
+	seen := make(map[string]bool)
+	if stmt.StateBlock != nil {
+		for _, field := range stmt.StateBlock.Fields {
+			seen[field.Name.Value] = true
+		}
+	}
+	for _, s := range stmt.Body.Statements {
+		if fn, ok := s.(*parser.FunctionStatement); ok {
+			if seen[fn.Name.Value] {
+				return nil, errors.NewTypeError(
+					fmt.Sprintf("Duplicate member %s in contract %s", fn.Name.Value, stmt.Name.Value),
+					fn.Name.Token.Line, fn.Name.Token.Column, "")
+			}
+			seen[fn.Name.Value] = true
+		}
+	}
+
+	contract := &Contract{
+		Name:      stmt.Name.Value,
+		Statement: stmt,
+		Env:       i.env,
+		Strict:    i.strict,
+	}
+
+	i.env.Set(contract.Name, contract)
+	return contract, nil
+}
+
+// deployContract creates a new instance of a contract: its state fields are
+// initialized to their declared values or zero values, its functions are
+// bound to the new state and the constructor is run with the arguments.
+func (i *Interpreter) deployContract(contract *Contract, args []Object, token parser.Token) (Object, error) {
+	// State fields are initialized in the strictness the contract was
+	// declared in, as its functions run
+	previousStrict := i.strict
+	i.strict = contract.Strict
+	defer func() { i.strict = previousStrict }()
+
+	state := NewEnclosedEnvironment(contract.Env)
+
+	// Struct and enum types declared in the contract are visible to its state fields
+	for _, stmt := range contract.Statement.Body.Statements {
+		switch stmt.(type) {
+		case *parser.StructStatement, *parser.EnumStatement, *parser.ErrorStatement:
+			if _, err := i.evalInEnv(state, func() (Object, error) { return i.evalStatement(stmt) }); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if block := contract.Statement.StateBlock; block != nil {
+		for _, field := range block.Fields {
+			val, err := i.evalStateField(field, state)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case field.Constant:
+				state.SetConstant(field.Name.Value, val, field.Type)
+			case field.Immutable:
+				state.SetTyped(field.Name.Value, val, field.Type)
+				state.kinds[field.Name.Value] = immutableBinding
+			default:
+				state.SetTyped(field.Name.Value, val, field.Type)
+			}
+		}
+	}
+
+	var constructor *parser.ConstructorStatement
+	for _, stmt := range contract.Statement.Body.Statements {
+		switch stmt := stmt.(type) {
+		case *parser.FunctionStatement:
+			state.Set(stmt.Name.Value, &Function{
+				TypeParameters: stmt.TypeParameters,
+				Parameters:     stmt.Parameters,
+				Body:           stmt.Body,
+				ReturnType:     stmt.ReturnType,
+				Env:            state,
+				Name:           stmt.Name.Value,
+				Token:          stmt.Token,
+				Strict:         contract.Strict,
+			})
+		case *parser.ConstructorStatement:
+			constructor = stmt
+		}
+	}
+
+	address, err := i.bc.DeployContract(i.sender, []byte(contract.Statement.String()))
+	if err != nil {
+		return nil, errors.NewRuntimeError(err.Error(), token.Line, token.Column, "")
+	}
+
+	instance := &ContractInstance{
+		Contract: contract,
+		Address:  address,
+		State:    state,
+	}
+	i.instances = append(i.instances, instance)
+
+	if constructor == nil {
+		if len(args) != 0 {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Wrong number of arguments: %s has no constructor, got %d arguments%s",
+					contract.Name, len(args), declaredAt(contract.Name, contract.Statement.Token)),
+				token.Line, token.Column, "")
+		}
+		return instance, nil
+	}
+
+	init := &Function{
+		Parameters: constructor.Parameters,
+		Body:       constructor.Body,
+		Env:        state,
+		Name:       contract.Name,
+		Token:      constructor.Token,
+		Strict:     contract.Strict,
+	}
+
+	// Immutable state fields can be assigned while the constructor runs
+	previous := i.constructing
+	i.constructing = instance
+	defer func() { i.constructing = previous }()
+
+	if _, err := i.callMethod(&BoundMethod{Instance: instance, Method: init}, args, token); err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// evalStateField computes the initial value of a state field
+func (i *Interpreter) evalStateField(field *parser.StateFieldStatement, state *Environment) (Object, error) {
+	return i.evalInEnv(state, func() (Object, error) {
+		if field.Value == nil {
+			return i.zeroValue(field.Type), nil
+		}
+
+		val, err := i.evalExpression(field.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return i.coerceToType(val, field.Type, field.Token)
+	})
+}
+
+// evalInEnv runs an evaluation with env as the current environment
+func (i *Interpreter) evalInEnv(env *Environment, eval func() (Object, error)) (Object, error) {
+	previousEnv := i.env
+	i.env = env
+	defer func() { i.env = previousEnv }()
+
+	return eval()
+}
+
+// callMethod calls a contract function on its instance. The function sees
+// the instance state and a msg object describing the call.
+func (i *Interpreter) callMethod(method *BoundMethod, args []Object, token parser.Token) (Object, error) {
+	callEnv := NewEnclosedEnvironment(method.Instance.State)
+	callEnv.Set("msg", i.newMessage())
+
+	previous := i.contract
+	i.contract = method.Instance
+	defer func() { i.contract = previous }()
+
+	fn := *method.Method
+	fn.Env = callEnv
+	return i.callFunction(&fn, args, token)
+}
+
+// payer returns the account that funds transfers: the running contract, or
+// the sender outside of contracts
+func (i *Interpreter) payer() blockchain.Address {
+	if i.contract != nil {
+		return i.contract.Address
+	}
+	return i.sender
+}
+
+// newMessage creates the msg object available inside contract functions
+func (i *Interpreter) newMessage() *Hash {
+	msg := NewHash()
+	for _, field := range []HashPair{
+		{Key: &String{Value: "sender"}, Value: &Address{Value: i.sender}},
+		{Key: &String{Value: "value"}, Value: &Integer{Value: new(big.Int), Kind: intTypes["Uint256"]}},
+	} {
+		msg.Set(field.Key.(Hashable).HashKey(), field)
+	}
+	return msg
+}
+
+// evalContractMember reads a state field or function of a deployed contract
+func (i *Interpreter) evalContractMember(instance *ContractInstance, name string, token parser.Token) (Object, error) {
+	if val, ok := instance.State.store[name]; ok {
+		if fn, ok := val.(*Function); ok {
+			return &BoundMethod{Instance: instance, Method: fn}, nil
+		}
+		return val, nil
+	}
+
+	if name == "address" {
+		return &Address{Value: instance.Address}, nil
+	}
+
+	return nil, errors.NewReferenceError(
+		fmt.Sprintf("Contract %s has no member %s", instance.Contract.Name, name),
+		token.Line, token.Column, "")
+}
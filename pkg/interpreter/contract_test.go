@@ -0,0 +1,106 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+const tokenContract = `
+contract Token {
+	state {
+		decimals: Uint8 = 18
+		totalSupply: Uint256
+		balances: Map<String, Uint256>
+	}
+
+	constructor(supply: Uint256) {
+		totalSupply = supply * 10 ** decimals;
+		balances["owner"] = totalSupply;
+	}
+
+	function setDecimals(d: Uint8) {
+		decimals = d;
+	}
+
+	function credit(who: String, amount: Uint256) {
+		balances[who] = amount;
+	}
+
+	function balanceOf(who: String): Uint256 {
+		return balances[who];
+	}
+}
+`
+
+func TestContractDeployment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let t = Token(1000000000); t.totalSupply;", "1000000000000000000000000000"},
+		{"let t = Token(1); t.decimals;", "18"},
+		{"let t = Token(5); t.balanceOf(\"owner\");", "5000000000000000000"},
+		{"let t = Token(1); t.setDecimals(6); t.decimals;", "6"},
+		{"let t = Token(1); t.credit(\"bob\", 7); t.balanceOf(\"bob\");", "7"},
+		{"let t = Token(1); let u = Token(2); u.totalSupply / t.totalSupply;", "2"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tokenContract+tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestContractStateZeroValues(t *testing.T) {
+	input := `
+	contract Empty {
+		state {
+			count: Uint64
+			name: String
+			active: Bool
+			owner: Address
+		}
+	}
+	let e = Empty();
+	[e.count, e.name, e.active, e.owner];
+	`
+
+	result := testEval(t, input)
+	expected := `[0, , false, 0x0000000000000000000000000000000000000000]`
+	if result.Inspect() != expected {
+		t.Errorf("wrong zero values. got=%s, want=%s", result.Inspect(), expected)
+	}
+}
+
+func TestContractStateTypeErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let t = Token(1); t.setDecimals(256);", "Value 256 out of range for Uint8"},
+		{"let t = Token(1); t.credit(\"bob\", -1);", "Value -1 out of range for Uint256"},
+		{"let t = Token(2 ** 200);", "(Uint256)"},
+		{"let t = Token(1); t.decimals = 3;", "Cannot assign to contract state from outside the contract"},
+		{"let t = Token(1); t.missing;", "Contract Token has no member missing"},
+		{"let t = Token();", "Wrong number of arguments"},
+		{`contract C { state { d: Uint8 } function set(x: Int) { d = x; } } let c = C(); c.set(300);`, "Value 300 out of range for Uint8"},
+		{`contract C { state { d: Uint8 } function set(x: Uint16) { d = x; } } let c = C(); c.set(3);`, "Cannot implicitly convert Uint16 to Uint8"},
+		{`contract C { state { d: Uint8 = 1000 } } C();`, "Integer literal 1000 out of range for Uint8"},
+		{`contract C { state { name: String } function set() { name = 5; } } let c = C(); c.set();`, "Type mismatch: expected String, got INTEGER"},
+		{`contract C { state { total: Int } function total(): Int { return total; } } C();`, "Duplicate member total in contract C"},
+		{`contract C { function f() { } function f() { } }`, "Duplicate member f in contract C"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tokenContract + tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"hash/fnv"
+)
+
+// EnumType represents an enum declaration. Its variants are read with a
+// dot, as in Phase.Open.
+type EnumType struct {
+	Name     string
+	Variants []*EnumValue // in declaration order
+}
+
+// Type returns the type of the EnumType object
+func (et *EnumType) Type() string { return "ENUM_TYPE" }
+
+// Inspect returns a string representation of the EnumType object
+func (et *EnumType) Inspect() string { return fmt.Sprintf("enum %s", et.Name) }
+
+// variant returns the variant with the given name
+func (et *EnumType) variant(name string) (*EnumValue, bool) {
+	for _, v := range et.Variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// EnumValue represents one variant of an enum. Each variant is a single
+// shared object, so variants compare equal exactly when they are identical.
+type EnumValue struct {
+	Enum  *EnumType
+	Name  string
+	Index int
+}
+
+// Type returns the type of the EnumValue object
+func (ev *EnumValue) Type() string { return "ENUM" }
+
+// Inspect returns a string representation of the EnumValue object
+func (ev *EnumValue) Inspect() string { return ev.Enum.Name + "." + ev.Name }
+
+// HashKey makes EnumValue hashable
+func (ev *EnumValue) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(ev.Inspect()))
+
+	return HashKey{Type: ev.Type(), Value: h.Sum64()}
+}
+
+// evalEnumStatement evaluates an enum declaration, binding its name to the
+// enum type
+func (i *Interpreter) evalEnumStatement(stmt *parser.EnumStatement) (Object, error) {
+	enum := &EnumType{Name: stmt.Name.Value}
+	for idx, v := range stmt.Variants {
+		enum.Variants = append(enum.Variants, &EnumValue{Enum: enum, Name: v.Value, Index: idx})
+	}
+
+	i.env.Set(enum.Name, enum)
+	return enum, nil
+}
+
+// evalEnumVariant reads a variant of an enum type
+func (i *Interpreter) evalEnumVariant(enum *EnumType, name string, token parser.Token) (Object, error) {
+	variant, ok := enum.variant(name)
+	if !ok {
+		return nil, errors.NewReferenceError(
+			fmt.Sprintf("Enum %s has no variant %s", enum.Name, name),
+			token.Line, token.Column, "")
+	}
+	return variant, nil
+}
+
+// evalMatchExpression evaluates the body of the first arm whose pattern
+// equals the subject. A match in which no arm applies is an error.
+func (i *Interpreter) evalMatchExpression(expr *parser.MatchExpression) (Object, error) {
+	subject, err := i.evalExpression(expr.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arm := range expr.Arms {
+		if arm.Pattern != nil {
+			pattern, err := i.evalExpression(arm.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			if !objectsEqual(subject, pattern) {
+				continue
+			}
+		}
+
+		return i.evalBlockStatement(arm.Body)
+	}
+
+	return nil, errors.NewRuntimeError(
+		fmt.Sprintf("No match arm for %s", subject.Inspect()),
+		expr.Token.Line, expr.Token.Column, "")
+}
@@ -0,0 +1,111 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnumsAndMatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"enum Phase { Open, Ended }\nPhase.Open;", "Phase.Open"},
+		{"enum Phase { Open, Ended }\nPhase.Open == Phase.Open;", "true"},
+		{"enum Phase { Open, Ended }\nPhase.Open == Phase.Ended;", "false"},
+		{"enum Phase { Open, Ended }\nPhase.Open != Phase.Ended;", "true"},
+		{"true == true;", "true"},
+		{"false != true;", "true"},
+		{
+			"enum Phase { Open, Ended }\nlet p = Phase.Ended;\nmatch (p) { Phase.Open => \"open\", Phase.Ended => \"ended\" };",
+			"ended",
+		},
+		{
+			"enum Phase { Open, Ended }\nlet p = Phase.Open;\nmatch (p) { Phase.Open => { let x = 40; x + 2 }, Phase.Ended => 0 };",
+			"42",
+		},
+		{"match (7) { 1 => \"one\", 7 => \"seven\", _ => \"other\" };", "seven"},
+		{"match (9) { 1 => \"one\", _ => \"other\" };", "other"},
+		{
+			// Enum variants are hashable and can be used as map keys
+			"enum Phase { Open, Ended }\nlet m = {Phase.Open: 1, Phase.Ended: 2};\nm[Phase.Ended];",
+			"2",
+		},
+		{
+			// A typed binding starts at the first variant
+			"enum Phase { Open, Ended }\nstruct Auction { phase: Phase }\nAuction {}.phase;",
+			"Phase.Open",
+		},
+		{
+			"enum Phase { Open, Ended }\nfunction next(p: Phase): Phase { return match (p) { Phase.Open => Phase.Ended, _ => p }; }\nnext(Phase.Open);",
+			"Phase.Ended",
+		},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestEnumInContractState(t *testing.T) {
+	input := `
+	contract Auction {
+		enum Phase { Open, Ended }
+
+		state {
+			phase: Phase
+		}
+
+		function end() {
+			require(phase == Phase.Open, "Auction already ended");
+			phase = Phase.Ended;
+		}
+
+		function status(): String {
+			return match (phase) {
+				Phase.Open => "open",
+				Phase.Ended => "ended",
+			};
+		}
+	}
+
+	let a = Auction();
+	let before = a.status();
+	a.end();
+	before + "/" + a.status();
+	`
+
+	result := testEval(t, input)
+	if result.Inspect() != "open/ended" {
+		t.Errorf("wrong result. got=%s, want=%s", result.Inspect(), "open/ended")
+	}
+}
+
+func TestEnumErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"enum Phase { Open, Ended }\nPhase.Closed;", "Enum Phase has no variant Closed"},
+		{"enum Phase { Open, Ended }\nlet p: Phase = 1;", "Type mismatch: expected Phase, got INTEGER"},
+		{
+			"enum Phase { Open, Ended }\nenum Color { Red }\nlet p: Phase = Color.Red;",
+			"Type mismatch: expected Phase, got ENUM",
+		},
+		{"match (3) { 1 => 1, 2 => 2 };", "No match arm for 3"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package interpreter
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"strings"
+)
+
+// objectsEqual reports whether two values are equal, as == and match
+// compare them:
+//   - integers, strings and booleans compare by value, and integers of
+//     different sized types are equal when their values are
+//   - addresses compare by value, ignoring the case of their hex digits
+//   - arrays and tuples are equal when they have the same length and their
+//     elements are equal in order; an array never equals a tuple
+//   - maps are equal when they hold equal values under the same keys, in
+//     any order
+//   - structs are equal when they are instances of the same struct type
+//     and their fields are equal
+//   - every other value, such as null, enum variants, functions and
+//     contracts, is equal only to itself
+//
+// The declared element, key and value types of arrays and maps are not
+// compared. Values that contain themselves compare without recursing
+// forever: a pair of values already being compared is taken to be equal.
+func objectsEqual(left, right Object) bool {
+	return equalObjects(left, right, make(map[[2]Object]bool))
+}
+
+// equalObjects compares two values, recording the pairs of composite
+// values being compared in comparing
+func equalObjects(left, right Object, comparing map[[2]Object]bool) bool {
+	switch left := left.(type) {
+	case *Integer:
+		r, ok := right.(*Integer)
+		return ok && left.Value.Cmp(r.Value) == 0
+	case *String:
+		r, ok := right.(*String)
+		return ok && left.Value == r.Value
+	case *Boolean:
+		r, ok := right.(*Boolean)
+		return ok && left.Value == r.Value
+	case *Address:
+		r, ok := right.(*Address)
+		return ok && strings.EqualFold(string(left.Value), string(r.Value))
+	}
+
+	if left == right {
+		return true
+	}
+	pair := [2]Object{left, right}
+	if comparing[pair] {
+		return true
+	}
+	comparing[pair] = true
+	defer delete(comparing, pair)
+
+	switch left := left.(type) {
+	case *Array:
+		r, ok := right.(*Array)
+		return ok && equalElements(left.Elements, r.Elements, comparing)
+	case *Tuple:
+		r, ok := right.(*Tuple)
+		return ok && equalElements(left.Elements, r.Elements, comparing)
+	case *Hash:
+		r, ok := right.(*Hash)
+		if !ok || left.Len() != r.Len() {
+			return false
+		}
+		for _, entry := range left.entries {
+			other, ok := r.Get(entry.hash, entry.pair.Key)
+			if !ok || !equalObjects(entry.pair.Value, other.Value, comparing) {
+				return false
+			}
+		}
+		return true
+	case *Struct:
+		r, ok := right.(*Struct)
+		if !ok || left.Definition != r.Definition {
+			return false
+		}
+		for _, field := range left.Definition.Fields {
+			name := field.Name.Value
+			if !equalObjects(left.Fields[name], r.Fields[name], comparing) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// equalElements compares the elements of two arrays or tuples in order
+func equalElements(left, right []Object, comparing map[[2]Object]bool) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for idx := range left {
+		if !equalObjects(left[idx], right[idx], comparing) {
+			return false
+		}
+	}
+	return true
+}
+
+// HashKey makes Array hashable
+func (a *Array) HashKey() HashKey { return compositeKey(a) }
+
+// HashKey makes Tuple hashable
+func (t *Tuple) HashKey() HashKey { return compositeKey(t) }
+
+// HashKey makes Hash hashable
+func (h *Hash) HashKey() HashKey { return compositeKey(h) }
+
+// HashKey makes Struct hashable
+func (s *Struct) HashKey() HashKey { return compositeKey(s) }
+
+// compositeKey hashes an array, tuple, map or struct from the values it
+// holds, so that values equal under objectsEqual have equal keys. Values
+// that are not hashable contribute only their type; hashKey rejects
+// composite keys holding them before they are used.
+func compositeKey(obj Object) HashKey {
+	h := fnv.New64a()
+	writeKey(h, obj, make(map[Object]bool))
+	return HashKey{Type: obj.Type(), Value: h.Sum64()}
+}
+
+// writeKey writes what identifies a value under objectsEqual to a hash.
+// A value met again while its own contents are being written contributes
+// a marker instead.
+func writeKey(h hash.Hash64, obj Object, visiting map[Object]bool) {
+	switch obj.(type) {
+	case *Array, *Tuple, *Hash, *Struct:
+		if visiting[obj] {
+			h.Write([]byte("cycle"))
+			return
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+	}
+
+	switch obj := obj.(type) {
+	case *Array:
+		writeElements(h, "[", obj.Elements, visiting)
+	case *Tuple:
+		writeElements(h, "(", obj.Elements, visiting)
+	case *Hash:
+		// Entries are combined by addition, so the key does not depend on
+		// their order
+		var sum uint64
+		for _, pair := range obj.Entries() {
+			entry := fnv.New64a()
+			writeKey(entry, pair.Key, visiting)
+			writeKey(entry, pair.Value, visiting)
+			sum += entry.Sum64()
+		}
+		h.Write([]byte("{"))
+		writeUint64(h, uint64(obj.Len()))
+		writeUint64(h, sum)
+	case *Struct:
+		h.Write([]byte(obj.Definition.Name))
+		for _, field := range obj.Definition.Fields {
+			writeKey(h, obj.Fields[field.Name.Value], visiting)
+		}
+	case Hashable:
+		key := obj.HashKey()
+		h.Write([]byte(key.Type))
+		writeUint64(h, key.Value)
+	default:
+		h.Write([]byte(obj.Type()))
+	}
+}
+
+// writeElements writes the elements of an array or tuple to a hash
+func writeElements(h hash.Hash64, open string, elements []Object, visiting map[Object]bool) {
+	h.Write([]byte(open))
+	writeUint64(h, uint64(len(elements)))
+	for _, el := range elements {
+		writeKey(h, el, visiting)
+	}
+}
+
+// writeUint64 writes a number to a hash
+func writeUint64(h hash.Hash64, n uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	h.Write(buf[:])
+}
+
+// hashKey returns the key a value is stored under in a map. Arrays,
+// tuples, maps and structs are usable as keys when every value they hold
+// is.
+func hashKey(obj Object) (HashKey, bool) {
+	if !hashable(obj, make(map[Object]bool)) {
+		return HashKey{}, false
+	}
+	return obj.(Hashable).HashKey(), true
+}
+
+// hashable reports whether a value, and every value it holds, is hashable
+func hashable(obj Object, visiting map[Object]bool) bool {
+	if visiting[obj] {
+		return true
+	}
+
+	var held []Object
+	switch obj := obj.(type) {
+	case *Array:
+		held = obj.Elements
+	case *Tuple:
+		held = obj.Elements
+	case *Hash:
+		for _, pair := range obj.Entries() {
+			held = append(held, pair.Key, pair.Value)
+		}
+	case *Struct:
+		for _, val := range obj.Fields {
+			held = append(held, val)
+		}
+	default:
+		_, ok := obj.(Hashable)
+		return ok
+	}
+
+	visiting[obj] = true
+	for _, val := range held {
+		if !hashable(val, visiting) {
+			return false
+		}
+	}
+	return true
+}
+
+// copyKey copies a composite value stored as a map key, so that changing
+// the value afterwards does not change the key. Other values are stored
+// as they are.
+func copyKey(obj Object) Object {
+	return copyValue(obj, make(map[Object]Object))
+}
+
+// copyValue deeply copies arrays, maps and structs, and tuples holding
+// them, reusing the copies already made of values met before
+func copyValue(obj Object, copies map[Object]Object) Object {
+	if copied, ok := copies[obj]; ok {
+		return copied
+	}
+
+	switch obj := obj.(type) {
+	case *Array:
+		copied := &Array{Elements: make([]Object, len(obj.Elements)), ElementType: obj.ElementType}
+		copies[obj] = copied
+		for idx, el := range obj.Elements {
+			copied.Elements[idx] = copyValue(el, copies)
+		}
+		return copied
+	case *Tuple:
+		copied := &Tuple{Elements: make([]Object, len(obj.Elements))}
+		copies[obj] = copied
+		for idx, el := range obj.Elements {
+			copied.Elements[idx] = copyValue(el, copies)
+		}
+		return copied
+	case *Hash:
+		copied := NewHash()
+		copied.KeyType, copied.ValueType = obj.KeyType, obj.ValueType
+		copies[obj] = copied
+		for _, entry := range obj.entries {
+			copied.Set(entry.hash, HashPair{Key: copyValue(entry.pair.Key, copies), Value: copyValue(entry.pair.Value, copies)})
+		}
+		return copied
+	case *Struct:
+		copied := &Struct{Definition: obj.Definition, Fields: make(map[string]Object, len(obj.Fields))}
+		copies[obj] = copied
+		for name, val := range obj.Fields {
+			copied.Fields[name] = copyValue(val, copies)
+		}
+		return copied
+	default:
+		return obj
+	}
+}
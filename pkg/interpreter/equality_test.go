@@ -0,0 +1,223 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStructuralEquality(t *testing.T) {
+	point := "struct P { x: Int, y: Int }\nstruct Q { x: Int, y: Int }\n"
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"[1, 2, 3] == [1, 2, 3];", true},
+		{"[1, 2, 3] == [1, 2];", false},
+		{"[1, 2] == [2, 1];", false},
+		{"[[1], [2, 3]] == [[1], [2, 3]];", true},
+		{"[1, 2] != [1, 2];", false},
+		{"let a = [1];\nlet b = a;\nb.push(2);\na == [1, 2];", true},
+		{"let a: Array<Uint8> = [1, 2];\na == [1, 2];", true},
+		{"{\"a\": 1, \"b\": 2} == {\"b\": 2, \"a\": 1};", true},
+		{"{\"a\": 1} == {\"a\": 2};", false},
+		{"{\"a\": 1} == {\"a\": 1, \"b\": 2};", false},
+		{"{\"a\": [1]} == {\"a\": [1]};", true},
+		{"(1, \"a\") == (1, \"a\");", true},
+		{"(1, \"a\") == (1, \"b\");", false},
+		{"[1, 2] == (1, 2);", false},
+		{point + "P { x: 1, y: 2 } == P { x: 1, y: 2 };", true},
+		{point + "P { x: 1, y: 2 } == P { x: 2, y: 1 };", false},
+		{point + "P { x: 1, y: 2 } == Q { x: 1, y: 2 };", false},
+		{point + "[P { x: 1, y: 2 }] == [P { x: 1, y: 2 }];", true},
+		{"0xabCDeF0123456789AbcdEf0123456789aBCDEF01 == 0xabcdef0123456789abcdef0123456789abcdef01;", true},
+		{"[0xabCDeF0123456789AbcdEf0123456789aBCDEF01] == [0xabcdef0123456789abcdef0123456789abcdef01];", true},
+		// Other values are equal only to themselves
+		{"enum Color { Red, Green }\n[Color.Red] == [Color.Red];", true},
+		{"enum Color { Red, Green }\n[Color.Red] == [Color.Green];", false},
+		{"let f = function() { 1; };\n[f] == [f];", true},
+		{"[function() { 1; }] == [function() { 1; }];", false},
+		{"contract C { }\nlet c = C();\n[c] == [c];", true},
+		{"contract C { }\nC() == C();", false},
+	}
+
+	for _, tt := range tests {
+		testBooleanObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestStructuralMatch(t *testing.T) {
+	input := `let p = (1, 2);
+	match (p) {
+		(0, 0) => "origin",
+		(1, 2) => "one-two",
+		_ => "other",
+	};`
+
+	result := testEval(t, input)
+	if result == nil || result.Inspect() != "one-two" {
+		t.Errorf("wrong result. got=%v, want=one-two", result)
+	}
+}
+
+func TestCompositeMapKeys(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let m = {[1, 2]: \"a\"};\nm[[1, 2]];", "a"},
+		{"let m: Map<Array<Int>, String> = {};\nm[[1, 2]] = \"a\";\nm[[1, 2]] = \"b\";\ntoString([len(m), m[[1, 2]]]);", "[1, b]"},
+		{"let m: Map<(Int, String), Int> = {};\nm[(1, \"a\")] = 5;\nm[(1, \"a\")];", "5"},
+		{"struct P { x: Int, y: Int }\nlet m = {P { x: 1, y: 2 }: \"p\"};\nm[P { x: 1, y: 2 }];", "p"},
+		{"let m = {{\"a\": 1, \"b\": 2}: \"ab\"};\nm[{\"b\": 2, \"a\": 1}];", "ab"},
+		{"let m = {[1, 2]: \"a\"};\nm.has([1, 2]);", "true"},
+		{"let m = {[1, 2]: \"a\"};\nm.has([2, 1]);", "false"},
+		{"let m = {[1, 2]: \"a\"};\nm[(1, 2)];", "null"},
+		{"let m = {[1, [2]]: \"a\", [1, [3]]: \"b\"};\ntoString([m[[1, [2]]], m[[1, [3]]]]);", "[a, b]"},
+		{"let m = {(1, 2): \"a\", [1, 2]: \"b\"};\nlen(m);", "2"},
+		// Keys are copied when stored, so changing a value used as a key
+		// does not change the key
+		{"let k = [1, 2];\nlet m: Map<Array<Int>, String> = {};\nm[k] = \"a\";\nk.push(3);\ntoString([m.has([1, 2]), m.has([1, 2, 3]), m.keys()]);", "[true, false, [[1, 2]]]"},
+		{
+			// Variants of two enums with the same name are different keys
+			"enum Color { Red }\nlet make = function() { enum Color { Red } return Color.Red; };\nlet other = make();\nlet m = {};\nm[Color.Red] = 1;\nm[other] = 2;\ntoString([len(m), m[Color.Red], m[other]]);",
+			"[2, 1, 2]",
+		},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+// TestHashKeysMatchEquality checks that values equal under objectsEqual
+// have equal hash keys
+func TestHashKeysMatchEquality(t *testing.T) {
+	pairs := []struct {
+		left  string
+		right string
+	}{
+		{"[1, [2, 3]]", "[1, [2, 3]]"},
+		{"{\"a\": 1, \"b\": [2]}", "{\"b\": [2], \"a\": 1}"},
+		{"(1, \"a\", true)", "(1, \"a\", true)"},
+		{"P { x: 1, y: [2] }", "P { x: 1, y: [2] }"},
+		{"[0xabCDeF0123456789AbcdEf0123456789aBCDEF01]", "[0xabcdef0123456789abcdef0123456789abcdef01]"},
+		{"[Uint8(1)]", "[1]"},
+	}
+
+	for _, tt := range pairs {
+		// Both values come from one program, so structs share a definition
+		pair, ok := testEval(t, "struct P { x: Int, y: Array<Int> }\n("+tt.left+", "+tt.right+");").(*Tuple)
+		if !ok {
+			t.Fatalf("cannot evaluate %s and %s", tt.left, tt.right)
+		}
+		left, right := pair.Elements[0], pair.Elements[1]
+
+		if !objectsEqual(left, right) {
+			t.Errorf("%s and %s are not equal", tt.left, tt.right)
+			continue
+		}
+		leftKey, ok := hashKey(left)
+		if !ok {
+			t.Errorf("%s is not usable as a key", tt.left)
+			continue
+		}
+		if rightKey, _ := hashKey(right); leftKey != rightKey {
+			t.Errorf("%s and %s have different keys: %v and %v", tt.left, tt.right, leftKey, rightKey)
+		}
+	}
+}
+
+// TestHashKeyCollisions checks that keys whose hash keys collide are kept
+// apart by comparing the keys themselves
+func TestHashKeyCollisions(t *testing.T) {
+	collision := HashKey{Type: "STRING", Value: 1}
+	a, b := &String{Value: "a"}, &String{Value: "b"}
+
+	h := NewHash()
+	h.Set(collision, HashPair{Key: a, Value: newInteger(1)})
+	h.Set(collision, HashPair{Key: b, Value: newInteger(2)})
+	h.Set(collision, HashPair{Key: &String{Value: "a"}, Value: newInteger(3)})
+
+	if h.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", h.Len())
+	}
+	if pair, ok := h.Get(collision, a); !ok || pair.Value.Inspect() != "3" {
+		t.Errorf("wrong value for a. got=%v", pair.Value)
+	}
+	if pair, ok := h.Get(collision, b); !ok || pair.Value.Inspect() != "2" {
+		t.Errorf("wrong value for b. got=%v", pair.Value)
+	}
+	if _, ok := h.Get(collision, &String{Value: "c"}); ok {
+		t.Errorf("c found without being stored")
+	}
+
+	if !h.Delete(collision, a) || h.Delete(collision, a) {
+		t.Errorf("a should be deleted exactly once")
+	}
+	if pair, ok := h.Get(collision, b); !ok || pair.Value.Inspect() != "2" {
+		t.Errorf("b lost after deleting a. got=%v", pair.Value)
+	}
+	if h.Inspect() != "{b: 2}" {
+		t.Errorf("wrong entries. got=%s", h.Inspect())
+	}
+}
+
+func TestCyclicValues(t *testing.T) {
+	// Untyped functions let a value hold itself
+	input := `let wrap = function(a) { a.push(a); a; };
+	let x = wrap([1]);
+	let y = wrap([1]);
+	let m = {};
+	m[x] = "cyclic";
+	`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x == x;", "true"},
+		{"x == y;", "true"},
+		{"x == wrap([2]);", "false"},
+		{"m[y];", "cyclic"},
+	}
+
+	for _, tt := range tests {
+		i := New(input + tt.input)
+		program := i.parser.ParseProgram()
+		result, err := i.evalProgram(program)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.input, err)
+			continue
+		}
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%s, want=%s", tt.input, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestUnusableHashKeys(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let f = function() { 1; };\nlet m = {[f]: 1};", "unusable as hash key: ARRAY"},
+		{"let f = function() { 1; };\nlet m = {};\nm[(1, f)] = 1;", "unusable as hash key: TUPLE"},
+		{"let m = {};\nm[[null]];", "unusable as hash key: ARRAY"},
+		{"let m = {};\nm.has({\"a\": null});", "unusable as hash key: HASH"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
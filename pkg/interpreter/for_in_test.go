@@ -0,0 +1,136 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestForInArray(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let sum = 0; for (x in [1, 2, 3, 4]) { sum += x; } sum;", 10},
+		{"let sum = 0; for (i, x in [10, 20, 30]) { sum += i * x; } sum;", 80},
+		{"let n = 0; for (x in []) { n += 1; } n;", 0},
+		{
+			`
+			let total = 0;
+			for (x in [1, 2, 3, 4, 5]) {
+				if (x == 2) { continue; }
+				if (x == 4) { break; }
+				total += x;
+			}
+			total;
+			`,
+			4,
+		},
+		{
+			// The body may modify the array being iterated
+			`
+			let arr = [1, 2, 3];
+			let n = 0;
+			for (i, x in arr) {
+				arr[i] = x * 2;
+				n += 1;
+			}
+			n + arr[2];
+			`,
+			9,
+		},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestForInHash(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`
+			let out = "";
+			for (key in {"c": 3, "a": 1, "b": 2}) {
+				out = out + key;
+			}
+			out;
+			`,
+			"cab",
+		},
+		{
+			`
+			let out = "";
+			for (key, value in {"c": 3, "a": 1, "b": 2}) {
+				out = out + key + "=" + value + ";";
+			}
+			out;
+			`,
+			"c=3;a=1;b=2;",
+		},
+		{
+			// New keys go to the end, overwritten keys keep their position
+			`
+			let m = {"x": 1, "y": 2};
+			m["z"] = 3;
+			m["x"] = 10;
+			let out = "";
+			for (key, value in m) {
+				out = out + key + value;
+			}
+			out;
+			`,
+			"x10y2z3",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(t, tt.input)
+		str, ok := evaluated.(*String)
+		if !ok {
+			t.Fatalf("object is not String. got=%T (%+v)", evaluated, evaluated)
+		}
+		if str.Value != tt.expected {
+			t.Errorf("wrong value. expected=%q, got=%q", tt.expected, str.Value)
+		}
+	}
+}
+
+func TestHashInspectIsDeterministic(t *testing.T) {
+	input := `{"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, 6: "six", true: "yes"};`
+	expected := "{one: 1, two: 2, three: 3, four: 4, five: 5, 6: six, true: yes}"
+
+	for n := 0; n < 20; n++ {
+		evaluated := testEval(t, input)
+		if evaluated.Inspect() != expected {
+			t.Fatalf("wrong Inspect. expected=%q, got=%q", expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestForInErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"for (x in 5) { }", "Cannot iterate over INTEGER"},
+		{"for (x in [1]) { } x;", "Identifier not found: x"},
+	}
+
+	for _, tt := range tests {
+		interpreter := New(tt.input)
+		err := runUnchecked(interpreter)
+
+		if err == nil {
+			t.Errorf("expected error but got none for input: %s", tt.input)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("wrong error message. expected=%q to contain %q for input: %s",
+				err.Error(), tt.expectedError, tt.input)
+		}
+	}
+}
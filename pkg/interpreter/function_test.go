@@ -119,15 +119,15 @@ func TestFunctionErrors(t *testing.T) {
 	}{
 		{
 			"let add = function(a, b) { a + b; }; add(1);",
-			"Wrong number of arguments",
+			"TypeError: Wrong number of arguments to add: expected 2, got 1",
 		},
 		{
 			"let add = function(a, b) { a + b; }; add(1, 2, 3);",
-			"Wrong number of arguments",
+			"TypeError: Wrong number of arguments to add: expected 2, got 3",
 		},
 		{
 			"1(1);",
-			"Not a function",
+			"TypeError: Not a function: Int at :1:1",
 		},
 	}
 
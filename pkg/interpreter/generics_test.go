@@ -0,0 +1,90 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenericTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let xs: Array<Uint8> = [1, 2];\ntypeof(xs[0]);", "Uint8"},
+		{"let xs: Array<Uint8> = [];\nxs.push(7);\ntypeof(xs[0]);", "Uint8"},
+		{"let xs: Array<Int> = [1, 2];\nxs[0] = 5;\nxs;", "[5, 2]"},
+		{"let grid: Array<Array<Int>> = [[1], [2, 3]];\ngrid[1][1];", "3"},
+		{"let m: Map<String, Array<Int>> = {\"a\": [1]};\nm[\"a\"].push(2);\nm[\"a\"];", "[1, 2]"},
+		{"let m: Map<String, Uint8> = {\"a\": 1};\ntypeof(m.values()[0]);", "Uint8"},
+		{"struct Bag { items: Array<String> }\nlet b = Bag {};\nb.items.push(\"x\");\nb.items.length;", "1"},
+		{"contract C {\n state { winner: Address? }\n function get(): Address? { return winner; }\n}\nC().get();", "null"},
+		{"let square: fn(Int) -> Int = function(n: Int): Int { return n * n; };\nsquare(4);", "16"},
+		{"function apply(f: fn(Int) -> Int, x: Int): Int { return f(x); }\napply(function(n) { return n + 1; }, 1);", "2"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestGenericFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"function max<T>(a: T, b: T): T { if (a > b) { return a; } return b; }\nmax(3, 7);", "7"},
+		{"function max<T>(a: T, b: T): T { if (a > b) { return a; } return b; }\ntypeof(max(Uint8(3), 7));", "Uint8"},
+		{"function first<T>(xs: Array<T>): T { return xs[0]; }\nfirst([\"a\", \"b\"]);", "a"},
+		{
+			"function map<A, B>(xs: Array<A>, f: fn(A) -> B): Array<B> {\n let out: Array<B> = [];\n for (x in xs) { out.push(f(x)); }\n return out;\n}\nmap([1, 2], function(x: Int): String { return toString(x * 2); });",
+			"[2, 4]",
+		},
+		// Writes through a generic parameter reach the caller's map
+		{"function put<K, V>(m: Map<K, V>, k: K, v: V) { m[k] = v; }\nlet m: Map<String, Int> = {};\nput(m, \"a\", 1);\nm[\"a\"];", "1"},
+		// Untyped collections passed to typed parameters are not copied
+		{"function add(xs: Array<Int>) { xs.push(3); }\nlet xs = [1, 2];\nadd(xs);\nxs;", "[1, 2, 3]"},
+		{"function put(m: Map<String, Int>) { m[\"b\"] = 2; }\nlet m = {\"a\": 1};\nput(m);\nm;", "{a: 1, b: 2}"},
+		{"let xs = [1];\nlet typed: Array<Uint8> = xs;\ntyped.push(2);\n[xs.length, typeof(xs[0])];", "[2, Uint8]"},
+		{
+			"contract Box {\n function pick<T>(flag: Bool, a: T, b: T): T { if (flag) { return a; } return b; }\n}\nBox().pick(false, 1, 2);",
+			"2",
+		},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestGenericTypeRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`let xs: Array<Int> = ["a"];`, "Type mismatch: expected Int, got STRING"},
+		{`let xs: Array<Int> = []; xs.push("a");`, "Type mismatch: expected Int, got STRING"},
+		{`let xs: Array<Uint8> = [1]; xs[0] = 300;`, "Value 300 out of range for Uint8"},
+		{`let xs: Array<Int> = 5;`, "Type mismatch: expected Array<Int>, got INTEGER"},
+		{`let f: fn(Int) -> Int = 5;`, "Type mismatch: expected fn(Int) -> Int, got INTEGER"},
+		{`let f: fn(Int) -> Int = function(a: Int, b: Int) { return a; };`, "expected fn(Int) -> Int, got a function with 2 parameters"},
+		{`function max<T>(a: T, b: T): T { return a; } max(1, "two");`, "Type mismatch: expected Int, got STRING"},
+		{`function same<T>(a: T): T { return 1; } same("a");`, "Type mismatch: expected String, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
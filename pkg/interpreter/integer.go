@@ -0,0 +1,141 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"math/big"
+)
+
+// intBits is the width of the Int type. Int is a signed 256-bit integer,
+// matching the word size of the value types used for balances and supplies.
+const intBits = 256
+
+// IntType describes a sized integer type such as Uint8 or Int256
+type IntType struct {
+	Name   string
+	Bits   int
+	Signed bool
+	Min    *big.Int
+	Max    *big.Int
+}
+
+// intTypes holds the sized integer types by name: Uint8 through Uint256 and
+// Int8 through Int256, in steps of 8 bits
+var intTypes = newIntTypes()
+
+// int256 is the range used for plain Int values
+var int256 = intTypes["Int256"]
+
+// newIntTypes builds the table of sized integer types, with the bounds the
+// parser checks literals against
+func newIntTypes() map[string]*IntType {
+	types := make(map[string]*IntType)
+
+	for bits := 8; bits <= intBits; bits += 8 {
+		for _, signed := range []bool{false, true} {
+			t := &IntType{Name: fmt.Sprintf("Uint%d", bits), Bits: bits, Signed: signed}
+			if signed {
+				t.Name = fmt.Sprintf("Int%d", bits)
+			}
+			t.Min, t.Max, _ = parser.IntegerRange(t.Name)
+			types[t.Name] = t
+		}
+	}
+
+	return types
+}
+
+// lookupIntType returns the sized integer type with the given name
+func lookupIntType(name string) (*IntType, bool) {
+	t, ok := intTypes[name]
+	return t, ok
+}
+
+// Contains reports whether value is representable by the type
+func (t *IntType) Contains(value *big.Int) bool {
+	return value.Cmp(t.Min) >= 0 && value.Cmp(t.Max) <= 0
+}
+
+// Wrap reduces value modulo 2^Bits into the range of the type
+func (t *IntType) Wrap(value *big.Int) *big.Int {
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(t.Bits))
+	result := new(big.Int).Mod(value, modulus)
+	if t.Signed && result.Cmp(t.Max) > 0 {
+		result.Sub(result, modulus)
+	}
+	return result
+}
+
+// Saturate clamps value to the range of the type
+func (t *IntType) Saturate(value *big.Int) *big.Int {
+	if value.Cmp(t.Min) < 0 {
+		return new(big.Int).Set(t.Min)
+	}
+	if value.Cmp(t.Max) > 0 {
+		return new(big.Int).Set(t.Max)
+	}
+	return value
+}
+
+// newInteger creates an Integer object from a machine integer
+func newInteger(value int64) *Integer {
+	return &Integer{Value: big.NewInt(value)}
+}
+
+// inIntRange reports whether value fits in the Int type
+func inIntRange(value *big.Int) bool {
+	return int256.Contains(value)
+}
+
+// rangeOf returns the type whose range bounds an integer of the given kind.
+// Plain Int values have no kind and use the Int256 range.
+func rangeOf(kind *IntType) *IntType {
+	if kind == nil {
+		return int256
+	}
+	return kind
+}
+
+// resultKind determines the sized type of a binary operation on two integers.
+// A plain Int operand, such as a literal, adopts the sized type of the other
+// operand; two different sized types must be converted explicitly.
+func resultKind(operator string, left, right *Integer) (*IntType, error) {
+	switch {
+	case left.Kind == right.Kind:
+		return left.Kind, nil
+	case left.Kind == nil:
+		return right.Kind, nil
+	case right.Kind == nil:
+		return left.Kind, nil
+	default:
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Type mismatch: %s %s %s (use an explicit cast)", left.Kind.Name, operator, right.Kind.Name),
+			0, 0, "")
+	}
+}
+
+// arrayIndex converts an integer index into a position in an array of the
+// given length, reporting false when it is out of bounds
+func arrayIndex(index *Integer, length int) (int, bool) {
+	if !index.Value.IsInt64() {
+		return 0, false
+	}
+
+	idx := index.Value.Int64()
+	if idx < 0 || idx >= int64(length) {
+		return 0, false
+	}
+
+	return int(idx), true
+}
+
+// integerOverflowError creates the error raised when integer arithmetic
+// overflows, positioned at the operator
+func integerOverflowError(operator string, left, right *big.Int, kind *IntType, token parser.Token) error {
+	message := fmt.Sprintf("Integer overflow: %s %s %s", left, operator, right)
+	if kind != nil {
+		message += fmt.Sprintf(" (%s)", kind.Name)
+	}
+	return errors.NewRuntimeError(message, token.Line, token.Column, "")
+}
@@ -0,0 +1,144 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBigIntegerArithmetic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1000000000 * 10 ** 18", "1000000000000000000000000000"},
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775808 - 1", "-9223372036854775809"},
+		{"(2 ** 254 - 1) * 2 + 1", "57896044618658097711785492504343953926634992332820282019728792003956564819967"},
+		{"-(2 ** 254) * 2", "-57896044618658097711785492504343953926634992332820282019728792003956564819968"},
+		{"57896044618658097711785492504343953926634992332820282019728792003956564819967 / 10 ** 70", "5789604"},
+		{"(10 ** 30) % 7", "1"},
+		{"1 << 254 >> 250", "16"},
+		{"-(1 << 200) >> 199", "-2"},
+		{"~(2 ** 100)", "-1267650600228229401496703205377"},
+		{"(2 ** 100) & (2 ** 100 + 1)", "1267650600228229401496703205376"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		integer, ok := result.(*Integer)
+		if !ok {
+			t.Fatalf("object is not Integer for %q. got=%T (%+v)", tt.input, result, result)
+		}
+		if integer.Inspect() != tt.expected {
+			t.Errorf("wrong value for %q. got=%s, want=%s", tt.input, integer.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestBigIntegerComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"10 ** 30 > 10 ** 29", true},
+		{"10 ** 30 == 1000000000000000000000000000000", true},
+		{"-(10 ** 30) < 1", true},
+		{"2 ** 64 != 2 ** 64", false},
+	}
+
+	for _, tt := range tests {
+		testBooleanObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestBigIntegerHashKeys(t *testing.T) {
+	input := `
+	let supply = {};
+	supply[10 ** 30] = "big";
+	supply[-(10 ** 30)] = "negative";
+	supply[1] = "small";
+	supply[1000000000000000000000000000000];
+	`
+
+	result := testEval(t, input)
+	str, ok := result.(*String)
+	if !ok {
+		t.Fatalf("object is not String. got=%T (%+v)", result, result)
+	}
+	if str.Value != "big" {
+		t.Errorf("wrong value. got=%q, want=%q", str.Value, "big")
+	}
+}
+
+func TestIntegerRangeErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"(2 ** 254 - 1) * 2 + 2", "Integer overflow"},
+		{"-(2 ** 254) * 2 - 1", "Integer overflow"},
+		{"-(-(2 ** 254) * 2)", "Integer overflow"},
+		{"(-(2 ** 254) * 2) / -1", "Integer overflow"},
+		{"let x = 2 ** 254; x *= 2;", "Integer overflow"},
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639936", "Integer literal out of range"},
+	}
+
+	for _, tt := range tests {
+		err := New(tt.input).Run()
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
+
+func TestIntegerErrorPositions(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let x: Uint8 = 200;\nlet y = x + 56;", "RuntimeError: Integer overflow: 200 + 56 (Uint8) at :2:11"},
+		{"let x: Uint8 = 200;\nx += 56;", "RuntimeError: Integer overflow: 200 + 56 (Uint8) at :2:3"},
+		{"let x: Uint8 = 255;\nx++;", "RuntimeError: Integer overflow: 255 + 1 (Uint8) at :2:2"},
+		{"let x: Int8 = -128;\n-x;", "RuntimeError: Integer overflow: 0 - -128 (Int8) at :2:1"},
+		{"let x = 1;\nx / (x - 1);", "RuntimeError: Division by zero at :2:3"},
+		{
+			"let max = 0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff;",
+			"TypeError: Integer literal 115792089237316195423570985008687907853269984665640564039457584007913129639935 out of range for Int; declare its type as Uint256 at :1:11",
+		},
+	}
+
+	for _, tt := range tests {
+		err := New(tt.input).Run()
+		if err == nil || err.Error() != tt.expectedError {
+			t.Errorf("wrong error for %q. expected=%q, got=%v", tt.input, tt.expectedError, err)
+		}
+	}
+}
+
+func TestIntegerLiteralForms(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0xff & 0x0f;", "15"},
+		{"1_000 * 2;", "2000"},
+		{"let wei: Uint256 = 5 * 1e18; wei;", "5000000000000000000"},
+		{"-0x10;", "-16"},
+		{
+			// Literals beyond the range of Int need an unsigned type
+			"let max: Uint256 = 0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff; max - 1;",
+			"115792089237316195423570985008687907853269984665640564039457584007913129639934",
+		},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
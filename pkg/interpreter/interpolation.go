@@ -0,0 +1,93 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// evalInterpolatedString evaluates a string literal with embedded
+// expressions. Each value is written as it is inspected, or as its format
+// describes.
+func (i *Interpreter) evalInterpolatedString(node *parser.InterpolatedString) (Object, error) {
+	var out strings.Builder
+
+	for idx, part := range node.Parts {
+		out.WriteString(part)
+		if idx == len(node.Interpolations) {
+			break
+		}
+
+		interpolation := node.Interpolations[idx]
+		val, err := i.evalExpression(interpolation.Expression)
+		if err != nil {
+			return nil, err
+		}
+		text, err := formatValue(val, interpolation.Format)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString(text)
+	}
+
+	return &String{Value: out.String()}, nil
+}
+
+// formatValue writes an interpolated value as its format describes.
+// Verbs and zero padding apply to integers only.
+func formatValue(val Object, format *parser.Format) (string, error) {
+	text := val.Inspect()
+	if format == nil {
+		return text, nil
+	}
+
+	if format.Verb != "" || format.Zero {
+		integer, ok := val.(*Integer)
+		if !ok {
+			return "", errors.NewTypeError(
+				fmt.Sprintf("Format %s applies to integers, got %s", format.String(), typeName(val)),
+				format.Token.Line, format.Token.Column, "")
+		}
+		text = formatInteger(integer.Value, format)
+	}
+
+	if pad := format.Width - utf8.RuneCountInString(text); pad > 0 {
+		if format.Align == "<" {
+			return text + strings.Repeat(" ", pad), nil
+		}
+		return strings.Repeat(" ", pad) + text, nil
+	}
+	return text, nil
+}
+
+// formatInteger writes an integer in the base of a format's verb, padded
+// with zeros after its sign if the format asks for them
+func formatInteger(value *big.Int, format *parser.Format) string {
+	base := 10
+	switch format.Verb {
+	case "x", "X":
+		base = 16
+	case "o":
+		base = 8
+	case "b":
+		base = 2
+	}
+
+	digits := new(big.Int).Abs(value).Text(base)
+	if format.Verb == "X" {
+		digits = strings.ToUpper(digits)
+	}
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	if format.Zero && len(sign)+len(digits) < format.Width {
+		digits = strings.Repeat("0", format.Width-len(sign)-len(digits)) + digits
+	}
+
+	return sign + digits
+}
@@ -0,0 +1,81 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterpolatedStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let amount = 5;\nlet to = \"bob\";\n\"Transfer of ${amount} to ${to}\";", "Transfer of 5 to bob"},
+		{"\"${1 + 2} ${true} ${null} ${[1, 2]} ${(1, \"a\")}\";", "3 true null [1, 2] (1, a)"},
+		{"let m = {\"k\": 1};\n\"${m[\"k\"]}\";", "1"},
+		{"\"outer ${\"inner ${2 * 3}\"}\";", "outer inner 6"},
+		{"\"${\"a\"}\" + \"b\";", "ab"},
+		// \$ writes a dollar sign; a $ not followed by { needs no escape
+		{"\"\\${x} costs $5\";", "${x} costs $5"},
+		{"\"no interpolation\";", "no interpolation"},
+		{"function greet(name: String): String { return \"Hello, ${name}!\"; }\ngreet(\"world\");", "Hello, world!"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestInterpolationFormats(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"\"${255:x}\";", "ff"},
+		{"\"${255:X}\";", "FF"},
+		{"\"${8:o} ${5:b}\";", "10 101"},
+		{"\"${255:08x}\";", "000000ff"},
+		{"\"${-5:05}\";", "-0005"},
+		{"\"${-255:x}\";", "-ff"},
+		{"\"[${42:6}]\";", "[    42]"},
+		{"\"[${\"ab\":<5}]\";", "[ab   ]"},
+		{"\"[${\"ab\":>5}]\";", "[   ab]"},
+		{"\"[${\"héllo\":6}]\";", "[ héllo]"},
+		{"\"[${123456:3}]\";", "[123456]"},
+		{"let x: Uint8 = 10;\n\"${x:04b}\";", "1010"},
+		{"let big = 2 ** 200;\n\"${big:x}\";", "1" + strings.Repeat("0", 50)},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestInterpolationErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"\"${\"a\":x}\";", "TypeError: Format x applies to integers, got String at :1:7"},
+		{"let f = function(v) { \"${v:08}\"; };\nf(true);", "TypeError: Format 08 applies to integers, got Bool at :1:27"},
+		{"\"a\n  ${missing}\";", "Identifier not found: missing at :2:5"},
+		{"\"${1 / 0}\";", "Division by zero"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
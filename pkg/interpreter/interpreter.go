@@ -2,11 +2,15 @@ package interpreter
 
 import (
 	"bytes"
+	"github.com/Stremax-Team/stremax-lang/pkg/checker"
 	"fmt"
 	"github.com/Stremax-Team/stremax-lang/pkg/blockchain"
 	"github.com/Stremax-Team/stremax-lang/pkg/errors"
 	"github.com/Stremax-Team/stremax-lang/pkg/lexer"
 	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"io"
+	"math/big"
+	"os"
 	"strings"
 	"hash/fnv"
 )
@@ -22,16 +26,19 @@ type Object interface {
 	Inspect() string
 }
 
-// Integer represents an integer value
+// Integer represents an integer value. Integers are arbitrary precision
+// internally but are range-checked against their type: the 256-bit Int
+// type, or the sized type recorded in Kind.
 type Integer struct {
-	Value int64
+	Value *big.Int
+	Kind  *IntType // sized integer type such as Uint8; nil for Int
 }
 
 // Type returns the type of the Integer object
 func (i *Integer) Type() string { return "INTEGER" }
 
 // Inspect returns a string representation of the Integer object
-func (i *Integer) Inspect() string { return fmt.Sprintf("%d", i.Value) }
+func (i *Integer) Inspect() string { return i.Value.String() }
 
 // String represents a string value
 type String struct {
@@ -68,11 +75,14 @@ func (a *Address) Inspect() string { return string(a.Value) }
 
 // Function represents a function definition
 type Function struct {
-	Parameters []*parser.ParameterStatement
-	Body       *parser.BlockStatement
-	ReturnType *parser.TypeExpression
-	Env        *Environment
-	Name       string // Optional, for named functions
+	TypeParameters []*parser.Identifier // Optional, for generic functions
+	Parameters     []*parser.ParameterStatement
+	Body           *parser.BlockStatement
+	ReturnType     *parser.TypeExpression
+	Env            *Environment
+	Name           string       // Optional, for named functions
+	Token          parser.Token // the declaration, reported by calls that do not match its parameters
+	Strict         bool         // whether the function was declared in strict mode, which its calls run in
 }
 
 // Type returns the type of the Function object
@@ -94,9 +104,37 @@ func (rv *ReturnValue) Type() string { return "RETURN_VALUE" }
 // Inspect returns a string representation of the ReturnValue object
 func (rv *ReturnValue) Inspect() string { return rv.Value.Inspect() }
 
+// BREAK signals that the innermost enclosing loop should stop
+var BREAK = &Break{}
+
+// Break represents a break out of a loop
+type Break struct{}
+
+// Type returns the type of the Break object
+func (b *Break) Type() string { return "BREAK" }
+
+// Inspect returns a string representation of the Break object
+func (b *Break) Inspect() string { return "break" }
+
+// CONTINUE signals that the innermost enclosing loop should start its next iteration
+var CONTINUE = &Continue{}
+
+// Continue represents a jump to the next iteration of a loop
+type Continue struct{}
+
+// Type returns the type of the Continue object
+func (c *Continue) Type() string { return "CONTINUE" }
+
+// Inspect returns a string representation of the Continue object
+func (c *Continue) Inspect() string { return "continue" }
+
 // Array represents an array object
 type Array struct {
 	Elements []Object
+
+	// ElementType is the declared element type of an Array<T>; it is nil
+	// for untyped array literals
+	ElementType *parser.TypeExpression
 }
 
 // Type returns the type of the Array object
@@ -135,9 +173,116 @@ type HashPair struct {
 	Value Object
 }
 
-// Hash represents a hash map object
+// Hash represents a hash map object.
+// Keys are kept in insertion order, which is the order used for iteration
+// and printing. Contract execution must be reproducible across nodes, so
+// the order never depends on Go's randomized map iteration.
+//
+// Entries are found by the HashKey of their key, but different keys can
+// have the same HashKey, so a key is only matched by a stored key that is
+// equal to it under objectsEqual.
 type Hash struct {
-	Pairs map[HashKey]HashPair
+	entries []*hashEntry             // in insertion order
+	buckets map[HashKey][]*hashEntry // the entries whose keys have each HashKey
+
+	// KeyType and ValueType are the declared types of a Map; both are nil
+	// for untyped hash literals
+	KeyType   *parser.TypeExpression
+	ValueType *parser.TypeExpression
+}
+
+// hashEntry is a key-value pair stored in a hash, with the HashKey of its key
+type hashEntry struct {
+	hash HashKey
+	pair HashPair
+}
+
+// NewHash creates an empty hash
+func NewHash() *Hash {
+	return &Hash{buckets: make(map[HashKey][]*hashEntry)}
+}
+
+// find returns the entry whose key equals obj, whose HashKey is key
+func (h *Hash) find(key HashKey, obj Object) *hashEntry {
+	for _, entry := range h.buckets[key] {
+		if objectsEqual(entry.pair.Key, obj) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Get returns the pair stored under obj, whose HashKey is key
+func (h *Hash) Get(key HashKey, obj Object) (HashPair, bool) {
+	if entry := h.find(key, obj); entry != nil {
+		return entry.pair, true
+	}
+	return HashPair{}, false
+}
+
+// Set stores a key-value pair under the HashKey of its key. Overwriting an
+// existing key keeps its position.
+func (h *Hash) Set(key HashKey, pair HashPair) {
+	if entry := h.find(key, pair.Key); entry != nil {
+		entry.pair = pair
+		return
+	}
+	entry := &hashEntry{hash: key, pair: pair}
+	h.entries = append(h.entries, entry)
+	h.buckets[key] = append(h.buckets[key], entry)
+}
+
+// Delete removes the pair stored under obj, whose HashKey is key, and
+// reports whether there was one
+func (h *Hash) Delete(key HashKey, obj Object) bool {
+	entry := h.find(key, obj)
+	if entry == nil {
+		return false
+	}
+
+	h.buckets[key] = removeEntry(h.buckets[key], entry)
+	if len(h.buckets[key]) == 0 {
+		delete(h.buckets, key)
+	}
+	h.entries = removeEntry(h.entries, entry)
+	return true
+}
+
+// removeEntry removes an entry from a list of entries, keeping the order of
+// the others
+func removeEntry(entries []*hashEntry, entry *hashEntry) []*hashEntry {
+	for idx, e := range entries {
+		if e == entry {
+			return append(entries[:idx:idx], entries[idx+1:]...)
+		}
+	}
+	return entries
+}
+
+// Len returns the number of pairs in the hash
+func (h *Hash) Len() int {
+	return len(h.entries)
+}
+
+// Entries returns the key-value pairs in insertion order
+func (h *Hash) Entries() []HashPair {
+	entries := make([]HashPair, 0, len(h.entries))
+	for _, entry := range h.entries {
+		entries = append(entries, entry.pair)
+	}
+	return entries
+}
+
+// clone copies the entries of a hash, sharing their keys and values
+func (h *Hash) clone() *Hash {
+	cloned := NewHash()
+	cloned.KeyType, cloned.ValueType = h.KeyType, h.ValueType
+	for _, entry := range h.entries {
+		copied := *entry
+		cloned.entries = append(cloned.entries, &copied)
+		cloned.buckets[copied.hash] = append(cloned.buckets[copied.hash], &copied)
+	}
+	return cloned
 }
 
 // Type returns the type of the Hash object
@@ -148,7 +293,7 @@ func (h *Hash) Inspect() string {
 	var out bytes.Buffer
 	
 	pairs := []string{}
-	for _, pair := range h.Pairs {
+	for _, pair := range h.Entries() {
 		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
 	}
 	
@@ -179,14 +324,43 @@ func (b *Boolean) HashKey() HashKey {
 	return HashKey{Type: b.Type(), Value: value}
 }
 
+// AddressHashKey makes Address hashable
+func (a *Address) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(string(a.Value))))
+
+	return HashKey{Type: a.Type(), Value: h.Sum64()}
+}
+
 // IntegerHashKey makes Integer hashable
 func (i *Integer) HashKey() HashKey {
-	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+	if i.Value.IsInt64() {
+		return HashKey{Type: i.Type(), Value: uint64(i.Value.Int64())}
+	}
+
+	h := fnv.New64a()
+	h.Write(i.Value.Bytes())
+	if i.Value.Sign() < 0 {
+		h.Write([]byte{'-'})
+	}
+
+	return HashKey{Type: i.Type(), Value: h.Sum64()}
 }
 
+// bindingKind restricts how a binding may be reassigned
+type bindingKind int
+
+const (
+	variableBinding  bindingKind = iota
+	constantBinding              // declared with const: never reassigned
+	immutableBinding             // an immutable state field: assigned only by the constructor
+)
+
 // Environment represents a variable environment
 type Environment struct {
 	store map[string]Object
+	types map[string]*parser.TypeExpression // declared types of typed bindings
+	kinds map[string]bindingKind            // bindings that cannot be freely reassigned
 	outer *Environment
 }
 
@@ -194,6 +368,8 @@ type Environment struct {
 func NewEnvironment() *Environment {
 	return &Environment{
 		store: make(map[string]Object),
+		types: make(map[string]*parser.TypeExpression),
+		kinds: make(map[string]bindingKind),
 		outer: nil,
 	}
 }
@@ -217,9 +393,74 @@ func (e *Environment) Get(name string) (Object, bool) {
 // Set sets a variable in the environment
 func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
+	delete(e.types, name)
+	delete(e.kinds, name)
 	return val
 }
 
+// SetTyped sets a variable with a declared type in the environment.
+// Later assignments to the variable are checked against that type.
+func (e *Environment) SetTyped(name string, val Object, typ *parser.TypeExpression) Object {
+	e.store[name] = val
+	e.types[name] = typ
+	delete(e.kinds, name)
+	return val
+}
+
+// SetConstant sets a constant in the environment. The type may be nil for
+// an untyped constant.
+func (e *Environment) SetConstant(name string, val Object, typ *parser.TypeExpression) Object {
+	if typ != nil {
+		e.SetTyped(name, val, typ)
+	} else {
+		e.Set(name, val)
+	}
+	e.kinds[name] = constantBinding
+	return val
+}
+
+// IsConstant reports whether a name declared in this environment, not an
+// enclosing one, is a constant
+func (e *Environment) IsConstant(name string) bool {
+	return e.kinds[name] == constantBinding
+}
+
+// bindingKind returns the kind of the binding a name resolves to
+func (e *Environment) bindingKind(name string) bindingKind {
+	if _, ok := e.store[name]; ok {
+		return e.kinds[name]
+	}
+	if e.outer != nil {
+		return e.outer.bindingKind(name)
+	}
+	return variableBinding
+}
+
+// DeclaredType returns the declared type of the binding a name resolves to,
+// or nil if the binding is untyped or does not exist
+func (e *Environment) DeclaredType(name string) *parser.TypeExpression {
+	if _, ok := e.store[name]; ok {
+		return e.types[name]
+	}
+	if e.outer != nil {
+		return e.outer.DeclaredType(name)
+	}
+	return nil
+}
+
+// Assign updates an existing variable, walking the enclosing environments
+// to find the scope that declared it. It reports false if no binding exists.
+func (e *Environment) Assign(name string, val Object) (Object, bool) {
+	if _, ok := e.store[name]; ok {
+		e.store[name] = val
+		return val, true
+	}
+	if e.outer != nil {
+		return e.outer.Assign(name, val)
+	}
+	return nil, false
+}
+
 // Interpreter represents an interpreter for Stremax-Lang.
 // It handles lexing, parsing, and evaluating Stremax-Lang code,
 // maintaining the execution environment and blockchain state.
@@ -229,6 +470,21 @@ type Interpreter struct {
 	parser *parser.Parser
 	env    *Environment
 	bc     *blockchain.Blockchain
+	sender blockchain.Address // the account deploying and calling contracts
+
+	contract     *ContractInstance   // the contract whose function is running, if any
+	constructing *ContractInstance   // the contract whose constructor is running, if any
+	instances    []*ContractInstance // the deployed contracts, in the order they were deployed
+
+	builtins *Environment // the outermost environment, holding the built-in functions
+	out      io.Writer    // where print and program results are written
+
+	path           string                     // the file being evaluated, if known
+	modules        map[string]*Module         // imported modules by absolute path
+	checkerModules map[string]*checker.Module // modules parsed for the type checker by absolute path
+	importStack    []string                   // the files being evaluated, outermost first
+
+	strict bool // whether values are never implicitly converted to strings or booleans
 }
 
 // New creates a new Stremax-Lang interpreter with the given source code.
@@ -244,18 +500,66 @@ func New(source string) *Interpreter {
 	l := lexer.New(source)
 	p := parser.New(l)
 
-	return &Interpreter{
-		source: source,
-		lexer:  l,
-		parser: p,
-		env:    NewEnvironment(),
-		bc:     blockchain.New(),
+	i := &Interpreter{
+		source:         source,
+		lexer:          l,
+		parser:         p,
+		bc:             blockchain.New(),
+		sender:         defaultSender,
+		builtins:       NewEnvironment(),
+		out:            os.Stdout,
+		modules:        make(map[string]*Module),
+		checkerModules: make(map[string]*checker.Module),
 	}
+
+	// Programs run in a global environment enclosed by the built-ins, so
+	// they can shadow any built-in function
+	for name, builtin := range builtins {
+		i.builtins.Set(name, builtin)
+	}
+	i.RegisterBuiltin("print", i.printBuiltin)
+	i.RegisterBuiltin("now", i.nowBuiltin)
+	i.builtins.Set(messageErrorType.Name, messageErrorType)
+	i.builtins.Set(panicErrorType.Name, panicErrorType)
+	i.env = NewEnclosedEnvironment(i.builtins)
+
+	return i
+}
+
+// RegisterBuiltin makes a Go function callable from Stremax-Lang code under
+// the given name, replacing any built-in function of that name.
+//
+// Parameters:
+//   - name: The name the function is called by
+//   - fn: The implementation, which receives the evaluated arguments
+func (i *Interpreter) RegisterBuiltin(name string, fn BuiltinFunction) {
+	i.builtins.Set(name, &Builtin{Name: name, Fn: fn})
+}
+
+// Blockchain returns the blockchain that contracts are deployed to and
+// called on, which holds the receipts of contract calls
+func (i *Interpreter) Blockchain() *blockchain.Blockchain {
+	return i.bc
+}
+
+// SetOutput sets where print and program results are written. The default
+// is standard output.
+func (i *Interpreter) SetOutput(w io.Writer) {
+	i.out = w
+}
+
+// SetStrict selects strict mode, as pragma strict; does for a program. In
+// strict mode values are not converted to strings by + and conditions must
+// be Bool.
+func (i *Interpreter) SetStrict(strict bool) {
+	i.strict = strict
 }
 
 // Run executes the Stremax-Lang source code provided to the interpreter.
 // It parses the program, evaluates it, and returns any errors encountered
-// during execution.
+// during execution. Errors are also written to the output, every parse and
+// type error found followed by a runtime error if evaluation fails, so
+// callers need not print the error they are returned.
 //
 // Returns:
 //   - An error if parsing or evaluation fails, nil otherwise
@@ -264,19 +568,41 @@ func (i *Interpreter) Run() error {
 	program := i.parser.ParseProgram()
 	if len(i.parser.Errors()) != 0 {
 		for _, msg := range i.parser.Errors() {
-			fmt.Printf("Parser error: %s\n", msg)
+			fmt.Fprintf(i.out, "Parser error: %s\n", msg)
+		}
+		// Report the first error that knows where it happened
+		if syntaxErrors := i.parser.SyntaxErrors(); len(syntaxErrors) != 0 {
+			return syntaxErrors[0]
 		}
 		return errors.NewSyntaxError("Failed to parse program", 0, 0, "")
 	}
 
+	// Type errors are reported before anything runs
+	c := checker.New()
+	c.SetStrict(i.strict)
+	c.SetImporter(i.checkerImporter(i.path, i.importStack))
+	c.Check(program)
+	if typeErrors := c.Errors(); len(typeErrors) != 0 {
+		for _, err := range typeErrors {
+			err.File = i.path
+			fmt.Fprintln(i.out, err)
+		}
+		return typeErrors[0]
+	}
+
 	// Evaluate the program
 	result, err := i.evalProgram(program)
 	if err != nil {
+		// Errors inside imported modules already name their file
+		if e, ok := err.(*errors.Error); ok && e.File == "" {
+			e.File = i.path
+		}
+		fmt.Fprintf(i.out, "Error: %s\n", err)
 		return err
 	}
 
 	if result != nil {
-		fmt.Printf("Result: %s\n", result.Inspect())
+		fmt.Fprintf(i.out, "Result: %s\n", result.Inspect())
 	}
 
 	return nil
@@ -287,6 +613,10 @@ func (i *Interpreter) evalProgram(program *parser.Program) (Object, error) {
 	var result Object
 	var err error
 
+	if program.Strict() {
+		i.strict = true
+	}
+
 	for _, stmt := range program.Statements {
 		result, err = i.evalStatement(stmt)
 		if err != nil {
@@ -312,22 +642,81 @@ func (i *Interpreter) evalStatement(stmt parser.Statement) (Object, error) {
 		return i.evalContractStatement(s)
 	case *parser.FunctionStatement:
 		return i.evalFunctionStatement(s)
+	case *parser.StructStatement:
+		return i.evalStructStatement(s)
+	case *parser.EnumStatement:
+		return i.evalEnumStatement(s)
+	case *parser.ImportStatement:
+		return i.evalImportStatement(s)
 	case *parser.RequireStatement:
 		return i.evalRequireStatement(s)
 	case *parser.EmitStatement:
 		return i.evalEmitStatement(s)
+	case *parser.ErrorStatement:
+		return i.evalErrorStatement(s)
+	case *parser.RevertStatement:
+		return i.evalRevertStatement(s)
+	case *parser.AssertStatement:
+		return i.evalAssertStatement(s)
+	case *parser.TryStatement:
+		return i.evalTryStatement(s)
+	case *parser.WhileStatement:
+		return i.evalWhileStatement(s)
+	case *parser.ForStatement:
+		return i.evalForStatement(s)
+	case *parser.ForInStatement:
+		return i.evalForInStatement(s)
+	case *parser.BreakStatement:
+		return BREAK, nil
+	case *parser.ContinueStatement:
+		return CONTINUE, nil
+	case *parser.PragmaStatement:
+		// Pragmas are read before the program runs
+		return nil, nil
 	default:
 		return nil, errors.NewRuntimeError("Unknown statement type", 0, 0, "")
 	}
 }
 
-// evalLetStatement evaluates a let statement
+// evalLetStatement evaluates a let or const statement
 func (i *Interpreter) evalLetStatement(stmt *parser.LetStatement) (Object, error) {
 	val, err := i.evalExpression(stmt.Value)
 	if err != nil {
 		return nil, err
 	}
 
+	if stmt.Type != nil {
+		val, err = i.coerceToType(val, stmt.Type, stmt.Token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if stmt.Pattern != nil {
+		return i.evalDestructuring(stmt, val)
+	}
+
+	// let _ = f(); evaluates f() for its effects only
+	if stmt.Name.Value == "_" {
+		return val, nil
+	}
+
+	if i.env.IsConstant(stmt.Name.Value) {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Cannot redeclare constant %s", stmt.Name.Value),
+			stmt.Name.Token.Line, stmt.Name.Token.Column, "")
+	}
+
+	if stmt.Constant {
+		i.env.SetConstant(stmt.Name.Value, val, stmt.Type)
+		return val, nil
+	}
+
+	if stmt.Type != nil {
+		i.env.SetTyped(stmt.Name.Value, val, stmt.Type)
+		return val, nil
+	}
+
 	i.env.Set(stmt.Name.Value, val)
 	return val, nil
 }
@@ -361,8 +750,8 @@ func (i *Interpreter) evalBlockStatement(block *parser.BlockStatement) (Object,
 			return nil, err
 		}
 		
-		// Check if it's a return value, if so, return early
-		if result != nil && result.Type() == "RETURN_VALUE" {
+		// Check if it's a return value or a loop control signal, if so, return early
+		if isUnwinding(result) {
 			i.env = previousEnv // Restore the previous environment
 			return result, nil
 		}
@@ -374,6 +763,147 @@ func (i *Interpreter) evalBlockStatement(block *parser.BlockStatement) (Object,
 	return result, nil
 }
 
+// isUnwinding reports whether a statement result must propagate out of the
+// enclosing blocks: a return value, or a break or continue signal
+func isUnwinding(result Object) bool {
+	if result == nil {
+		return false
+	}
+
+	switch result.Type() {
+	case "RETURN_VALUE", "BREAK", "CONTINUE":
+		return true
+	default:
+		return false
+	}
+}
+
+// evalWhileStatement evaluates a while loop
+func (i *Interpreter) evalWhileStatement(stmt *parser.WhileStatement) (Object, error) {
+	for {
+		condition, err := i.evalExpression(stmt.Condition)
+		if err != nil {
+			return nil, err
+		}
+
+		if holds, err := i.isTrue(condition, "while", stmt.Token); err != nil || !holds {
+			return nil, err
+		}
+
+		result, done, err := i.evalLoopBody(stmt.Body)
+		if err != nil || done {
+			return result, err
+		}
+	}
+}
+
+// evalForStatement evaluates a C-style for loop
+func (i *Interpreter) evalForStatement(stmt *parser.ForStatement) (Object, error) {
+	// Variables declared in the initializer are scoped to the loop
+	previousEnv := i.env
+	i.env = NewEnclosedEnvironment(i.env)
+	defer func() { i.env = previousEnv }()
+
+	if stmt.Init != nil {
+		if _, err := i.evalStatement(stmt.Init); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if stmt.Condition != nil {
+			condition, err := i.evalExpression(stmt.Condition)
+			if err != nil {
+				return nil, err
+			}
+
+			if holds, err := i.isTrue(condition, "for", stmt.Token); err != nil || !holds {
+				return nil, err
+			}
+		}
+
+		result, done, err := i.evalLoopBody(stmt.Body)
+		if err != nil || done {
+			return result, err
+		}
+
+		if stmt.Update != nil {
+			if _, err := i.evalExpression(stmt.Update); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// evalForInStatement evaluates a for-in loop over an array or hash.
+// With one variable, arrays yield their elements and hashes their keys.
+// With two variables, arrays yield (index, element) and hashes (key, value).
+// Hashes are visited in insertion order, and the collection is snapshotted
+// before the first iteration so the body may modify it.
+func (i *Interpreter) evalForInStatement(stmt *parser.ForInStatement) (Object, error) {
+	iterable, err := i.evalExpression(stmt.Iterable)
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []HashPair
+	var isArray bool
+	switch iterable := iterable.(type) {
+	case *Array:
+		isArray = true
+		for idx, el := range iterable.Elements {
+			pairs = append(pairs, HashPair{Key: newInteger(int64(idx)), Value: el})
+		}
+	case *Hash:
+		pairs = iterable.Entries()
+	default:
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Cannot iterate over %s", iterable.Type()),
+			stmt.Token.Line, stmt.Token.Column, "")
+	}
+
+	previousEnv := i.env
+	defer func() { i.env = previousEnv }()
+
+	for _, pair := range pairs {
+		i.env = NewEnclosedEnvironment(previousEnv)
+		if len(stmt.Variables) == 2 {
+			i.env.Set(stmt.Variables[0].Value, pair.Key)
+			i.env.Set(stmt.Variables[1].Value, pair.Value)
+		} else if isArray {
+			i.env.Set(stmt.Variables[0].Value, pair.Value)
+		} else {
+			i.env.Set(stmt.Variables[0].Value, pair.Key)
+		}
+
+		result, done, err := i.evalLoopBody(stmt.Body)
+		if err != nil || done {
+			return result, err
+		}
+	}
+
+	return nil, nil
+}
+
+// evalLoopBody evaluates one iteration of a loop body. It reports done when
+// the loop must stop, either through break or through a return value, which
+// is passed on to the caller.
+func (i *Interpreter) evalLoopBody(body *parser.BlockStatement) (Object, bool, error) {
+	result, err := i.evalBlockStatement(body)
+	if err != nil {
+		return nil, true, err
+	}
+
+	switch result.(type) {
+	case *ReturnValue:
+		return result, true, nil
+	case *Break:
+		return nil, true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
 // NULL represents a null value
 var NULL = &Null{}
 
@@ -391,11 +921,35 @@ func (i *Interpreter) evalExpression(expr parser.Expression) (Object, error) {
 	// Wrap in a type switch to handle different expression types
 	switch e := expr.(type) {
 	case *parser.IntegerLiteral:
-		return &Integer{Value: e.Value}, nil
+		if inIntRange(e.Value) {
+			return &Integer{Value: e.Value}, nil
+		}
+		// Literals beyond Int, such as 0xff...ff, are Uint256. The checker
+		// only allows them where their type is declared unsigned.
+		if intTypes["Uint256"].Contains(e.Value) {
+			return &Integer{Value: e.Value, Kind: intTypes["Uint256"]}, nil
+		}
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("Integer literal out of range: %s", e.Value),
+			e.Token.Line, e.Token.Column, "")
 	case *parser.StringLiteral:
 		return &String{Value: e.Value}, nil
+	case *parser.InterpolatedString:
+		return i.evalInterpolatedString(e)
+	case *parser.AddressLiteral:
+		address, err := blockchain.ParseAddress(e.Value)
+		if err != nil {
+			return nil, errors.NewSyntaxError(err.Error(), e.Token.Line, e.Token.Column, "")
+		}
+		return &Address{Value: address}, nil
 	case *parser.BooleanLiteral:
 		return &Boolean{Value: e.Value}, nil
+	case *parser.NullLiteral:
+		return NULL, nil
+	case *parser.StructLiteral:
+		return i.evalStructLiteral(e)
+	case *parser.MatchExpression:
+		return i.evalMatchExpression(e)
 	case *parser.PrefixExpression:
 		return i.evalPrefixExpression(e)
 	case *parser.InfixExpression:
@@ -410,10 +964,18 @@ func (i *Interpreter) evalExpression(expr parser.Expression) (Object, error) {
 		return i.evalFunctionLiteral(e)
 	case *parser.ArrayLiteral:
 		return i.evalArrayLiteral(e)
+	case *parser.TupleLiteral:
+		return i.evalTupleLiteral(e)
 	case *parser.IndexExpression:
 		return i.evalIndexExpression(e)
 	case *parser.HashLiteral:
 		return i.evalHashLiteral(e)
+	case *parser.DotExpression:
+		return i.evalDotExpression(e)
+	case *parser.AssignExpression:
+		return i.evalAssignExpression(e)
+	case *parser.PostfixExpression:
+		return i.evalPostfixExpression(e)
 	default:
 		fmt.Printf("DEBUG: Unknown expression type: %T\n", e)
 		return nil, errors.NewRuntimeError(fmt.Sprintf("Unknown expression type: %T", e), 0, 0, "")
@@ -438,9 +1000,16 @@ func (i *Interpreter) evalPrefixExpression(expr *parser.PrefixExpression) (Objec
 
 	switch expr.Operator {
 	case "!":
+		if _, ok := right.(*Boolean); !ok && i.strict {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Operand of ! must be a Bool in strict mode, got %s", typeName(right)),
+				expr.Token.Line, expr.Token.Column, "")
+		}
 		return i.evalBangOperatorExpression(right)
 	case "-":
-		return i.evalMinusPrefixOperatorExpression(right)
+		return i.evalMinusPrefixOperatorExpression(right, expr.Token)
+	case "~":
+		return i.evalBitwiseNotOperatorExpression(right)
 	default:
 		return nil, errors.NewRuntimeError(fmt.Sprintf("Unknown operator: %s", expr.Operator), expr.Token.Line, expr.Token.Column, "")
 	}
@@ -457,17 +1026,44 @@ func (i *Interpreter) evalBangOperatorExpression(right Object) (Object, error) {
 }
 
 // evalMinusPrefixOperatorExpression evaluates a minus prefix operator expression
-func (i *Interpreter) evalMinusPrefixOperatorExpression(right Object) (Object, error) {
+func (i *Interpreter) evalMinusPrefixOperatorExpression(right Object, token parser.Token) (Object, error) {
+	if right.Type() != "INTEGER" {
+		return nil, errors.NewTypeError("Cannot negate non-integer", token.Line, token.Column, "")
+	}
+
+	integer := right.(*Integer)
+	value := new(big.Int).Neg(integer.Value)
+	if !rangeOf(integer.Kind).Contains(value) {
+		return nil, integerOverflowError("-", new(big.Int), integer.Value, integer.Kind, token)
+	}
+	return &Integer{Value: value, Kind: integer.Kind}, nil
+}
+
+// evalBitwiseNotOperatorExpression evaluates a bitwise not (~) operator expression
+func (i *Interpreter) evalBitwiseNotOperatorExpression(right Object) (Object, error) {
 	if right.Type() != "INTEGER" {
-		return nil, errors.NewTypeError("Cannot negate non-integer", 0, 0, "")
+		return nil, errors.NewTypeError("Cannot apply ~ to non-integer", 0, 0, "")
 	}
 
-	value := right.(*Integer).Value
-	return &Integer{Value: -value}, nil
+	integer := right.(*Integer)
+	if integer.Kind != nil && !integer.Kind.Signed {
+		// Unsigned values invert every bit of their declared width
+		return &Integer{Value: new(big.Int).Sub(integer.Kind.Max, integer.Value), Kind: integer.Kind}, nil
+	}
+	return &Integer{Value: new(big.Int).Not(integer.Value), Kind: integer.Kind}, nil
 }
 
 // evalInfixExpression evaluates an infix expression
 func (i *Interpreter) evalInfixExpression(expr *parser.InfixExpression) (Object, error) {
+	// Logical operators short-circuit, so the right operand may never be evaluated
+	if expr.Operator == "&&" || expr.Operator == "||" {
+		return i.evalLogicalExpression(expr)
+	}
+	// The default of ?? is only evaluated if the value is null
+	if expr.Operator == "??" {
+		return i.evalNullCoalesceExpression(expr)
+	}
+
 	left, err := i.evalExpression(expr.Left)
 	if err != nil {
 		return nil, err
@@ -478,44 +1074,50 @@ func (i *Interpreter) evalInfixExpression(expr *parser.InfixExpression) (Object,
 		return nil, err
 	}
 
+	return i.evalInfixOperator(expr.Operator, left, right, expr.Token)
+}
+
+// evalNullCoalesceExpression evaluates a ?? b: the value of a, or of b if
+// a is null
+func (i *Interpreter) evalNullCoalesceExpression(expr *parser.InfixExpression) (Object, error) {
+	left, err := i.evalExpression(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	if left != NULL {
+		return left, nil
+	}
+	return i.evalExpression(expr.Right)
+}
+
+// evalInfixOperator applies a binary operator to two evaluated operands
+func (i *Interpreter) evalInfixOperator(operator string, left, right Object, token parser.Token) (Object, error) {
 	switch {
+	case operator == "in":
+		return i.evalInExpression(left, right, token)
 	case left.Type() == "INTEGER" && right.Type() == "INTEGER":
-		// For "+" operator, support both addition and concatenation
-		if expr.Operator == "+" {
-			// If either integer is very large or has a special format, treat as concatenation
-			leftVal := left.(*Integer).Value
-			rightVal := right.(*Integer).Value
-			leftStr := fmt.Sprintf("%d", leftVal)
-			rightStr := fmt.Sprintf("%d", rightVal)
-			
-			// Check if this should be treated as concatenation
-			if strings.HasPrefix(leftStr, "0") || strings.HasPrefix(rightStr, "0") {
-				// Handle as string concatenation when numbers have leading zeros
-				return &String{Value: leftStr + rightStr}, nil
-			} else {
-				// Regular integer addition
-				return i.evalIntegerInfixExpression(expr.Operator, left, right)
-			}
-		} else {
-			// Other operators proceed with normal integer operations
-			return i.evalIntegerInfixExpression(expr.Operator, left, right)
-		}
+		return i.evalIntegerInfixExpression(operator, left, right, token)
 	case left.Type() == "STRING" && right.Type() == "STRING":
-		return i.evalStringInfixExpression(expr.Operator, left, right)
+		return i.evalStringInfixExpression(operator, left, right)
+	case i.strict && operator == "+" && (left.Type() == "STRING" || right.Type() == "STRING"):
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Type mismatch: %s + %s (strict mode does not convert values to strings; use toString or interpolation)", typeName(left), typeName(right)),
+			token.Line, token.Column, "")
 	// Support string concatenation with other types
-	case left.Type() == "STRING" && expr.Operator == "+":
+	case left.Type() == "STRING" && operator == "+":
 		return i.evalMixedStringConcatExpression(left, right, true)
-	case right.Type() == "STRING" && expr.Operator == "+":
+	case right.Type() == "STRING" && operator == "+":
 		return i.evalMixedStringConcatExpression(right, left, false)
-	case expr.Operator == "==":
-		return &Boolean{Value: left == right}, nil
-	case expr.Operator == "!=":
-		return &Boolean{Value: left != right}, nil
+	case operator == "==":
+		return &Boolean{Value: objectsEqual(left, right)}, nil
+	case operator == "!=":
+		return &Boolean{Value: !objectsEqual(left, right)}, nil
 	default:
 		return nil, errors.NewTypeError(
-			fmt.Sprintf("Type mismatch: %s %s %s", left.Type(), expr.Operator, right.Type()),
-			expr.Token.Line,
-			expr.Token.Column,
+			fmt.Sprintf("Type mismatch: %s %s %s", left.Type(), operator, right.Type()),
+			token.Line,
+			token.Column,
 			"",
 		)
 	}
@@ -580,38 +1182,101 @@ func (i *Interpreter) evalLogicalExpression(expr *parser.InfixExpression) (Objec
 	}
 }
 
-// evalIntegerInfixExpression evaluates an integer infix expression
-func (i *Interpreter) evalIntegerInfixExpression(operator string, left, right Object) (Object, error) {
+// evalIntegerInfixExpression evaluates an integer infix expression.
+// Arithmetic is arbitrary precision, but results must fit the Int range;
+// anything outside it is reported as an overflow instead of wrapping.
+func (i *Interpreter) evalIntegerInfixExpression(operator string, left, right Object, token parser.Token) (Object, error) {
 	leftVal := left.(*Integer).Value
 	rightVal := right.(*Integer).Value
+	result := new(big.Int)
+
+	// The result has the sized type of the operands; for exponents and
+	// shifts the right operand is only a count and the left type wins
+	kind := left.(*Integer).Kind
+	switch operator {
+	case "**", "<<", ">>", "<", ">", "<=", ">=", "==", "!=":
+	default:
+		var err error
+		kind, err = resultKind(operator, left.(*Integer), right.(*Integer))
+		if err != nil {
+			return nil, withPosition(err, token)
+		}
+	}
+	bounds := rangeOf(kind)
 
 	switch operator {
 	case "+":
-		return &Integer{Value: leftVal + rightVal}, nil
+		result.Add(leftVal, rightVal)
 	case "-":
-		return &Integer{Value: leftVal - rightVal}, nil
+		result.Sub(leftVal, rightVal)
 	case "*":
-		return &Integer{Value: leftVal * rightVal}, nil
+		result.Mul(leftVal, rightVal)
 	case "/":
-		if rightVal == 0 {
-			return nil, errors.NewRuntimeError("Division by zero", 0, 0, "")
+		if rightVal.Sign() == 0 {
+			return nil, errors.NewRuntimeError("Division by zero", token.Line, token.Column, "")
+		}
+		result.Quo(leftVal, rightVal)
+	case "%":
+		if rightVal.Sign() == 0 {
+			return nil, errors.NewRuntimeError("Modulo by zero", token.Line, token.Column, "")
+		}
+		result.Rem(leftVal, rightVal)
+	case "**":
+		if rightVal.Sign() < 0 {
+			return nil, errors.NewRuntimeError(fmt.Sprintf("Negative exponent: %s", rightVal), token.Line, token.Column, "")
 		}
-		return &Integer{Value: leftVal / rightVal}, nil
+		// Any base other than 0, 1 and -1 overflows long before the exponent
+		// exceeds the bit width, so avoid computing huge intermediate values
+		if leftVal.CmpAbs(big.NewInt(1)) > 0 && rightVal.Cmp(big.NewInt(intBits)) > 0 {
+			return nil, integerOverflowError(operator, leftVal, rightVal, kind, token)
+		}
+		result.Exp(leftVal, rightVal, nil)
+	case "&":
+		result.And(leftVal, rightVal)
+	case "|":
+		result.Or(leftVal, rightVal)
+	case "^":
+		result.Xor(leftVal, rightVal)
+	case "<<":
+		if rightVal.Sign() < 0 {
+			return nil, errors.NewRuntimeError(fmt.Sprintf("Negative shift amount: %s", rightVal), token.Line, token.Column, "")
+		}
+		if leftVal.Sign() == 0 {
+			return &Integer{Value: result, Kind: kind}, nil
+		}
+		if rightVal.Cmp(big.NewInt(intBits)) >= 0 {
+			return nil, integerOverflowError(operator, leftVal, rightVal, kind, token)
+		}
+		result.Lsh(leftVal, uint(rightVal.Uint64()))
+	case ">>":
+		if rightVal.Sign() < 0 {
+			return nil, errors.NewRuntimeError(fmt.Sprintf("Negative shift amount: %s", rightVal), token.Line, token.Column, "")
+		}
+		shift := uint(intBits)
+		if rightVal.Cmp(big.NewInt(intBits)) < 0 {
+			shift = uint(rightVal.Uint64())
+		}
+		result.Rsh(leftVal, shift)
 	case "<":
-		return &Boolean{Value: leftVal < rightVal}, nil
+		return &Boolean{Value: leftVal.Cmp(rightVal) < 0}, nil
 	case ">":
-		return &Boolean{Value: leftVal > rightVal}, nil
+		return &Boolean{Value: leftVal.Cmp(rightVal) > 0}, nil
 	case "<=":
-		return &Boolean{Value: leftVal <= rightVal}, nil
+		return &Boolean{Value: leftVal.Cmp(rightVal) <= 0}, nil
 	case ">=":
-		return &Boolean{Value: leftVal >= rightVal}, nil
+		return &Boolean{Value: leftVal.Cmp(rightVal) >= 0}, nil
 	case "==":
-		return &Boolean{Value: leftVal == rightVal}, nil
+		return &Boolean{Value: leftVal.Cmp(rightVal) == 0}, nil
 	case "!=":
-		return &Boolean{Value: leftVal != rightVal}, nil
+		return &Boolean{Value: leftVal.Cmp(rightVal) != 0}, nil
 	default:
-		return nil, errors.NewRuntimeError(fmt.Sprintf("Unknown operator: %s", operator), 0, 0, "")
+		return nil, errors.NewRuntimeError(fmt.Sprintf("Unknown operator: %s", operator), token.Line, token.Column, "")
+	}
+
+	if !bounds.Contains(result) {
+		return nil, integerOverflowError(operator, leftVal, rightVal, kind, token)
 	}
+	return &Integer{Value: result, Kind: kind}, nil
 }
 
 // evalStringInfixExpression evaluates a string infix expression
@@ -639,7 +1304,7 @@ func (i *Interpreter) evalStringConcatExpression(strObj Object, otherObj Object)
 	var otherVal string
 	switch other := otherObj.(type) {
 	case *Integer:
-		otherVal = fmt.Sprintf("%d", other.Value)
+		otherVal = other.Value.String()
 	case *Boolean:
 		otherVal = fmt.Sprintf("%t", other.Value)
 	case *String:
@@ -662,7 +1327,7 @@ func (i *Interpreter) evalMixedStringConcatExpression(strObj Object, otherObj Ob
 	var otherVal string
 	switch other := otherObj.(type) {
 	case *Integer:
-		otherVal = fmt.Sprintf("%d", other.Value)
+		otherVal = other.Value.String()
 	case *Boolean:
 		otherVal = fmt.Sprintf("%t", other.Value)
 	case *String:
@@ -688,7 +1353,12 @@ func (i *Interpreter) evalIfExpression(expr *parser.IfExpression) (Object, error
 		return nil, err
 	}
 
-	if isTruthy(condition) {
+	holds, err := i.isTrue(condition, "if", expr.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if holds {
 		return i.evalBlockStatement(expr.Consequence)
 	} else if expr.Alternative != nil {
 		return i.evalBlockStatement(expr.Alternative)
@@ -697,13 +1367,28 @@ func (i *Interpreter) evalIfExpression(expr *parser.IfExpression) (Object, error
 	}
 }
 
+// isTrue decides whether the condition of an if, while, for, require or
+// assert holds. In strict mode the condition must be a Bool; otherwise any
+// value but false and 0 holds.
+func (i *Interpreter) isTrue(condition Object, construct string, token parser.Token) (bool, error) {
+	if b, ok := condition.(*Boolean); ok {
+		return b.Value, nil
+	}
+	if i.strict {
+		return false, errors.NewTypeError(
+			fmt.Sprintf("Condition of %s must be a Bool in strict mode, got %s", construct, typeName(condition)),
+			token.Line, token.Column, "")
+	}
+	return isTruthy(condition), nil
+}
+
 // isTruthy determines if an object is truthy
 func isTruthy(obj Object) bool {
 	switch obj := obj.(type) {
 	case *Boolean:
 		return obj.Value
 	case *Integer:
-		return obj.Value != 0
+		return obj.Value.Sign() != 0
 	default:
 		return true
 	}
@@ -712,14 +1397,32 @@ func isTruthy(obj Object) bool {
 // evalCallExpression evaluates a call expression
 func (i *Interpreter) evalCallExpression(expr *parser.CallExpression) (Object, error) {
 	// Evaluate the function expression to get the function object
-	function, err := i.evalExpression(expr.Function)
-	if err != nil {
-		return nil, err
+	var function Object
+	var err error
+	if dot, ok := expr.Function.(*parser.DotExpression); ok && dot.Optional {
+		// A method called through ?. is not called if the object is null
+		left, err := i.evalExpression(dot.Left)
+		if err != nil {
+			return nil, err
+		}
+		if left == NULL {
+			return NULL, nil
+		}
+		function, err = i.evalFieldAccess(left, dot.Right.(*parser.Identifier).Value, dot.Token)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		function, err = i.evalExpression(expr.Function)
+		if err != nil {
+			return nil, err
+		}
 	}
-	
-	// Check if it's actually a function
-	fn, ok := function.(*Function)
-	if !ok {
+
+	// Check if it's actually callable
+	switch function.(type) {
+	case *Function, *Builtin, *Contract, *BoundMethod, *ErrorType:
+	default:
 		return nil, errors.NewTypeError(
 			fmt.Sprintf("Not a function: %s", function.Type()),
 			expr.Token.Line,
@@ -727,51 +1430,156 @@ func (i *Interpreter) evalCallExpression(expr *parser.CallExpression) (Object, e
 			"",
 		)
 	}
-	
+
 	// Evaluate the arguments
 	args, err := i.evalExpressions(expr.Arguments)
 	if err != nil {
 		return nil, err
 	}
-	
-	// Check if the number of arguments matches the number of parameters
-	if len(args) != len(fn.Parameters) {
+
+	if expr.Names != nil {
+		// Named arguments are put in parameter order; the callee matches
+		// positional arguments itself
+		args, err = i.arrangeArguments(function, args, expr.Names, expr.Token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return i.applyFunction(function, args, expr.Token)
+}
+
+// applyFunction calls a callable object with already evaluated arguments
+func (i *Interpreter) applyFunction(function Object, args []Object, token parser.Token) (Object, error) {
+	switch fn := function.(type) {
+	case *Function:
+		return i.callFunction(fn, args, token)
+	case *Builtin:
+		result, err := fn.Fn(args...)
+		if err != nil {
+			// Builtins have no position of their own; report the call site
+			return nil, withPosition(err, token)
+		}
+		return result, nil
+	case *Contract:
+		return i.deployContract(fn, args, token)
+	case *BoundMethod:
+		if i.contract == nil {
+			return i.transact(fn, args, token)
+		}
+		return i.callMethod(fn, args, token)
+	case *ErrorType:
+		return i.newErrorValue(fn, args, token)
+	default:
 		return nil, errors.NewTypeError(
-			fmt.Sprintf("Wrong number of arguments: expected %d, got %d",
-				len(fn.Parameters), len(args)),
-			expr.Token.Line,
-			expr.Token.Column,
-			"",
-		)
+			fmt.Sprintf("Not a function: %s", function.Type()),
+			token.Line, token.Column, "")
 	}
-	
+}
+
+// callFunction binds arguments to the parameters of a user-defined function
+// and evaluates its body. Arguments and the return value are checked against
+// declared parameter and return types. Omitted arguments take the default
+// values of their parameters, which may refer to the parameters before them,
+// and a variadic parameter collects the remaining arguments into an array.
+func (i *Interpreter) callFunction(fn *Function, args []Object, token parser.Token) (Object, error) {
+	// Match the arguments to the parameters
+	args, err := i.arrangeArguments(fn, args, nil, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// A function keeps the strictness of the file that declared it, even
+	// when called from a file that is not strict
+	previousStrict := i.strict
+	i.strict = fn.Strict
+	defer func() { i.strict = previousStrict }()
+
 	// Create a new environment for the function call
 	extendedEnv := NewEnclosedEnvironment(fn.Env)
-	
+
+	// The type parameters of a generic function take the types of the
+	// arguments they are first used by
+	var bindings map[string]*parser.TypeExpression
+	if len(fn.TypeParameters) != 0 {
+		bindings = make(map[string]*parser.TypeExpression)
+		for _, tp := range fn.TypeParameters {
+			bindings[tp.Value] = nil
+		}
+		for idx, param := range fn.Parameters {
+			if param.Variadic {
+				for _, arg := range args[idx:] {
+					bindTypeParameters(param.Type, arg, bindings)
+				}
+			} else if args[idx] != nil {
+				bindTypeParameters(param.Type, args[idx], bindings)
+			}
+		}
+	}
+
 	// Bind the arguments to the parameters
-	for i, param := range fn.Parameters {
-		extendedEnv.Set(param.Name.Value, args[i])
+	for idx, param := range fn.Parameters {
+		typ := param.Type
+		if bindings != nil {
+			typ = substituteTypeParameters(typ, bindings)
+		}
+
+		if param.Variadic {
+			rest, err := i.typeArray(&Array{Elements: args[idx:]}, &parser.TypeExpression{ElementType: typ}, token)
+			if err != nil {
+				return nil, err
+			}
+			if typ == nil {
+				extendedEnv.Set(param.Name.Value, rest)
+			} else {
+				extendedEnv.SetTyped(param.Name.Value, rest, &parser.TypeExpression{Type: "Array", ElementType: typ})
+			}
+			continue
+		}
+
+		arg := args[idx]
+		if arg == nil {
+			arg, err = i.evalInEnv(extendedEnv, func() (Object, error) { return i.evalExpression(param.Default) })
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if typ == nil {
+			extendedEnv.Set(param.Name.Value, arg)
+			continue
+		}
+
+		arg, err = i.coerceToType(arg, typ, token)
+		if err != nil {
+			return nil, err
+		}
+		extendedEnv.SetTyped(param.Name.Value, arg, typ)
 	}
-	
+
 	// Save the current environment and set the function's environment
 	previousEnv := i.env
 	i.env = extendedEnv
-	
+
 	// Evaluate the function body
 	result, err := i.evalBlockStatement(fn.Body)
-	
+
 	// Restore the previous environment
 	i.env = previousEnv
-	
+
 	if err != nil {
 		return nil, err
 	}
-	
+
 	// Unwrap the return value if it's a return value
 	if returnValue, ok := result.(*ReturnValue); ok {
-		return returnValue.Value, nil
+		returnType := fn.ReturnType
+		if bindings != nil {
+			returnType = substituteTypeParameters(returnType, bindings)
+		}
+		return i.coerceToType(returnValue.Value, returnType, token)
 	}
-	
+
 	return result, nil
 }
 
@@ -807,14 +1615,203 @@ func (i *Interpreter) evalIndexExpression(expr *parser.IndexExpression) (Object,
 
 // evalDotExpression evaluates a dot expression
 func (i *Interpreter) evalDotExpression(expr *parser.DotExpression) (Object, error) {
-	// For now, just return nil
-	return nil, errors.NewRuntimeError("Dot expressions not implemented yet", expr.Token.Line, expr.Token.Column, "")
+	left, err := i.evalExpression(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.Optional && left == NULL {
+		return NULL, nil
+	}
+
+	return i.evalFieldAccess(left, expr.Right.(*parser.Identifier).Value, expr.Token)
 }
 
-// evalContractStatement evaluates a contract statement
-func (i *Interpreter) evalContractStatement(stmt *parser.ContractStatement) (Object, error) {
-	// For now, just return nil
-	return nil, errors.NewRuntimeError("Contract statements not implemented yet", stmt.Token.Line, stmt.Token.Column, "")
+// evalFieldAccess reads a named field from an object
+func (i *Interpreter) evalFieldAccess(obj Object, name string, token parser.Token) (Object, error) {
+	switch obj := obj.(type) {
+	case *Hash:
+		// The methods of maps take precedence over their entries, which
+		// can always be read by index
+		if method, ok, err := i.lookupMethod(obj, name, token); ok {
+			return method, err
+		}
+		return i.evalHashIndexExpression(obj, &String{Value: name}, token)
+	case *Struct:
+		return i.evalStructField(obj, name, token)
+	case *ErrorValue:
+		return i.evalErrorField(obj, name, token)
+	case *EnumType:
+		return i.evalEnumVariant(obj, name, token)
+	case *Module:
+		return i.evalModuleMember(obj, name, token)
+	case *ContractInstance:
+		return i.evalContractMember(obj, name, token)
+	default:
+		if method, ok, err := i.lookupMethod(obj, name, token); ok {
+			return method, err
+		}
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("field access not supported: %s.%s", obj.Type(), name),
+			token.Line, token.Column, "")
+	}
+}
+
+// reference is an assignable location resolved from the target of an assignment
+type reference struct {
+	get func() (Object, error)
+	set func(Object) error
+}
+
+// resolveReference resolves an assignment target to a readable and writable location.
+// The target's sub-expressions are evaluated exactly once.
+func (i *Interpreter) resolveReference(target parser.Expression) (*reference, error) {
+	switch target := target.(type) {
+	case *parser.Identifier:
+		return &reference{
+			get: func() (Object, error) {
+				return i.evalIdentifier(target)
+			},
+			set: func(val Object) error {
+				if err := i.checkReassignable(target); err != nil {
+					return err
+				}
+				val, err := i.coerceToType(val, i.env.DeclaredType(target.Value), target.Token)
+				if err != nil {
+					return err
+				}
+				if _, ok := i.env.Assign(target.Value, val); !ok {
+					return errors.NewReferenceError(
+						fmt.Sprintf("Cannot assign to undeclared variable: %s", target.Value),
+						target.Token.Line, target.Token.Column, "")
+				}
+				return nil
+			},
+		}, nil
+	case *parser.IndexExpression:
+		left, err := i.evalContainer(target.Left)
+		if err != nil {
+			return nil, err
+		}
+
+		index, err := i.evalExpression(target.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		return &reference{
+			get: func() (Object, error) {
+				return i.evalElementAccess(left, index, target.Token)
+			},
+			set: func(val Object) error {
+				return i.setElement(left, index, val, target.Token)
+			},
+		}, nil
+	case *parser.DotExpression:
+		left, err := i.evalContainer(target.Left)
+		if err != nil {
+			return nil, err
+		}
+
+		name := target.Right.(*parser.Identifier).Value
+		return &reference{
+			get: func() (Object, error) {
+				return i.evalFieldAccess(left, name, target.Token)
+			},
+			set: func(val Object) error {
+				return i.setField(left, name, val, target.Token)
+			},
+		}, nil
+	default:
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("Invalid assignment target: %s", target.String()), 0, 0, "")
+	}
+}
+
+// checkReassignable rejects an assignment to a constant, or to an immutable
+// state field outside the constructor of its contract
+func (i *Interpreter) checkReassignable(target *parser.Identifier) error {
+	switch i.env.bindingKind(target.Value) {
+	case constantBinding:
+		return errors.NewTypeError(
+			fmt.Sprintf("Cannot assign to constant %s", target.Value),
+			target.Token.Line, target.Token.Column, "")
+	case immutableBinding:
+		if i.constructing == nil || i.constructing != i.contract {
+			return errors.NewTypeError(
+				fmt.Sprintf("Cannot assign to immutable state field %s outside the constructor", target.Value),
+				target.Token.Line, target.Token.Column, "")
+		}
+	}
+	return nil
+}
+
+// evalAssignExpression evaluates an assignment or compound assignment expression
+func (i *Interpreter) evalAssignExpression(expr *parser.AssignExpression) (Object, error) {
+	ref, err := i.resolveReference(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := i.evalExpression(expr.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compound assignment applies the operator to the current value, e.g. += uses +
+	if expr.Operator != "=" {
+		current, err := ref.get()
+		if err != nil {
+			return nil, err
+		}
+
+		val, err = i.evalInfixOperator(strings.TrimSuffix(expr.Operator, "="), current, val, expr.Token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ref.set(val); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// evalPostfixExpression evaluates a postfix increment or decrement.
+// Like C, the expression yields the value held before the update.
+func (i *Interpreter) evalPostfixExpression(expr *parser.PostfixExpression) (Object, error) {
+	ref, err := i.resolveReference(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := ref.get()
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Type() != "INTEGER" {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Operator %s requires an integer, got %s", expr.Operator, current.Type()),
+			expr.Token.Line, expr.Token.Column, "")
+	}
+
+	operator := "+"
+	if expr.Operator == "--" {
+		operator = "-"
+	}
+
+	updated, err := i.evalIntegerInfixExpression(operator, current, newInteger(1), expr.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ref.set(updated); err != nil {
+		return nil, err
+	}
+
+	return current, nil
 }
 
 // evalFunctionStatement evaluates a function statement
@@ -826,11 +1823,14 @@ func (i *Interpreter) evalFunctionStatement(stmt *parser.FunctionStatement) (Obj
 	}
 	
 	function := &Function{
-		Parameters: stmt.Parameters,
-		Body:       stmt.Body,
-		ReturnType: stmt.ReturnType,
-		Env:        i.env,
-		Name:       name,
+		TypeParameters: stmt.TypeParameters,
+		Parameters:     stmt.Parameters,
+		Body:           stmt.Body,
+		ReturnType:     stmt.ReturnType,
+		Env:            i.env,
+		Name:           name,
+		Token:          stmt.Token,
+		Strict:         i.strict,
 	}
 	
 	// Store the function in the current environment if it has a name
@@ -848,7 +1848,12 @@ func (i *Interpreter) evalRequireStatement(stmt *parser.RequireStatement) (Objec
 		return nil, err
 	}
 
-	if !isTruthy(condition) {
+	holds, err := i.isTrue(condition, "require", stmt.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !holds {
 		message := "Requirement failed"
 		if stmt.Message != nil {
 			msgObj, err := i.evalExpression(stmt.Message)
@@ -860,7 +1865,9 @@ func (i *Interpreter) evalRequireStatement(stmt *parser.RequireStatement) (Objec
 				message = msgObj.(*String).Value
 			}
 		}
-		return nil, errors.NewRuntimeError(message, stmt.Token.Line, stmt.Token.Column, "")
+		failure := errors.NewRuntimeError(message, stmt.Token.Line, stmt.Token.Column, "")
+		failure.Data = newMessageError(messageErrorType, message)
+		return nil, failure
 	}
 
 	return nil, nil
@@ -869,14 +1876,14 @@ func (i *Interpreter) evalRequireStatement(stmt *parser.RequireStatement) (Objec
 // evalEmitStatement evaluates an emit statement
 func (i *Interpreter) evalEmitStatement(stmt *parser.EmitStatement) (Object, error) {
 	// For now, just print the event
-	fmt.Printf("Event emitted: %s\n", stmt.EventName.Value)
+	fmt.Fprintf(i.out, "Event emitted: %s\n", stmt.EventName.Value)
 
 	for _, arg := range stmt.Arguments {
 		argObj, err := i.evalExpression(arg)
 		if err != nil {
 			return nil, err
 		}
-		fmt.Printf("  Argument: %s\n", argObj.Inspect())
+		fmt.Fprintf(i.out, "  Argument: %s\n", argObj.Inspect())
 	}
 
 	return nil, nil
@@ -889,6 +1896,8 @@ func (i *Interpreter) evalFunctionLiteral(fl *parser.FunctionLiteral) (Object, e
 		Body:       fl.Body,
 		ReturnType: fl.ReturnType,
 		Env:        i.env, // Capture the current environment for closures
+		Token:      fl.Token,
+		Strict:     i.strict,
 	}
 	
 	return function, nil
@@ -914,6 +1923,8 @@ func (i *Interpreter) evalElementAccess(left, index Object, token parser.Token)
 	switch {
 	case left.Type() == "ARRAY" && index.Type() == "INTEGER":
 		return i.evalArrayIndexExpression(left, index, token)
+	case left.Type() == "TUPLE" && index.Type() == "INTEGER":
+		return i.evalTupleIndexExpression(left.(*Tuple), index.(*Integer), token)
 	case left.Type() == "HASH":
 		return i.evalHashIndexExpression(left, index, token)
 	default:
@@ -926,58 +1937,135 @@ func (i *Interpreter) evalElementAccess(left, index Object, token parser.Token)
 // evalArrayIndexExpression implements array indexing
 func (i *Interpreter) evalArrayIndexExpression(array, index Object, token parser.Token) (Object, error) {
 	arrayObject := array.(*Array)
-	idx := index.(*Integer).Value
-	max := int64(len(arrayObject.Elements) - 1)
-
-	if idx < 0 || idx > max {
-		return NULL, nil
+	idx, ok := arrayIndex(index.(*Integer), len(arrayObject.Elements))
+	if !ok {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("array index out of range: %s (length %d)", index.(*Integer).Value, len(arrayObject.Elements)),
+			token.Line, token.Column, "")
 	}
 
 	return arrayObject.Elements[idx], nil
 }
 
+// setElement handles writing elements of arrays and hashes
+func (i *Interpreter) setElement(left, index, val Object, token parser.Token) error {
+	switch left := left.(type) {
+	case *Array:
+		idx, ok := index.(*Integer)
+		if !ok {
+			return errors.NewTypeError(
+				fmt.Sprintf("array index must be an integer, got %s", index.Type()),
+				token.Line, token.Column, "")
+		}
+		position, ok := arrayIndex(idx, len(left.Elements))
+		if !ok {
+			return errors.NewRuntimeError(
+				fmt.Sprintf("array index out of range: %s", idx.Value),
+				token.Line, token.Column, "")
+		}
+		val, err := i.coerceToType(val, left.ElementType, token)
+		if err != nil {
+			return err
+		}
+		left.Elements[position] = val
+		return nil
+	case *Hash:
+		key, ok := hashKey(index)
+		if !ok {
+			return errors.NewRuntimeError(
+				fmt.Sprintf("unusable as hash key: %s", index.Type()),
+				token.Line, token.Column, "")
+		}
+		index, err := i.coerceToType(index, left.KeyType, token)
+		if err != nil {
+			return err
+		}
+		val, err := i.coerceToType(val, left.ValueType, token)
+		if err != nil {
+			return err
+		}
+		left.Set(key, HashPair{Key: copyKey(index), Value: val})
+		return nil
+	case *Tuple:
+		return errors.NewTypeError("Cannot assign to an element of a tuple; tuples are immutable",
+			token.Line, token.Column, "")
+	default:
+		return errors.NewRuntimeError(
+			fmt.Sprintf("index assignment not supported: %s", left.Type()),
+			token.Line, token.Column, "")
+	}
+}
+
+// setField handles writing a named field of an object
+func (i *Interpreter) setField(obj Object, name string, val Object, token parser.Token) error {
+	switch obj := obj.(type) {
+	case *Hash:
+		// An entry named after a method could not be read back with a dot
+		if _, ok := methods[obj.Type()][name]; ok {
+			return errors.NewRuntimeError(
+				fmt.Sprintf("Cannot assign to map method %s; use [%q] to set the entry", name, name),
+				token.Line, token.Column, "")
+		}
+		return i.setElement(obj, &String{Value: name}, val, token)
+	case *Struct:
+		return i.setStructField(obj, name, val, token)
+	case *ContractInstance:
+		return errors.NewRuntimeError(
+			fmt.Sprintf("Cannot assign to contract state from outside the contract: %s.%s", obj.Contract.Name, name),
+			token.Line, token.Column, "")
+	default:
+		return errors.NewRuntimeError(
+			fmt.Sprintf("field assignment not supported: %s.%s", obj.Type(), name),
+			token.Line, token.Column, "")
+	}
+}
+
 // evalHashLiteral evaluates a hash literal expression
 func (i *Interpreter) evalHashLiteral(node *parser.HashLiteral) (Object, error) {
-	pairs := make(map[HashKey]HashPair)
+	hash := NewHash()
 
-	for keyNode, valueNode := range node.Pairs {
+	for _, keyNode := range node.Keys {
 		key, err := i.evalExpression(keyNode)
 		if err != nil {
 			return nil, err
 		}
 		
-		hashKey, ok := key.(Hashable)
+		hashed, ok := hashKey(key)
 		if !ok {
 			return nil, errors.NewRuntimeError(
 				fmt.Sprintf("unusable as hash key: %s", key.Type()),
 				node.Token.Line, node.Token.Column, "")
 		}
 
-		value, err := i.evalExpression(valueNode)
+		value, err := i.evalExpression(node.Pairs[keyNode])
 		if err != nil {
 			return nil, err
 		}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = HashPair{Key: key, Value: value}
+		hash.Set(hashed, HashPair{Key: copyKey(key), Value: value})
 	}
 
-	return &Hash{Pairs: pairs}, nil
+	return hash, nil
 }
 
 // evalHashIndexExpression handles hash element access with [key]
 func (i *Interpreter) evalHashIndexExpression(hash, index Object, token parser.Token) (Object, error) {
 	hashObject := hash.(*Hash)
 	
-	key, ok := index.(Hashable)
+	key, ok := hashKey(index)
 	if !ok {
 		return nil, errors.NewRuntimeError(
 			fmt.Sprintf("unusable as hash key: %s", index.Type()),
 			token.Line, token.Column, "")
 	}
 	
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	pair, ok := hashObject.Get(key, index)
 	if !ok {
+		// A declared Map reads missing keys as the zero value of its value
+		// type, like contract storage; untyped maps read them as null
+		if hashObject.ValueType != nil {
+			return i.zeroValue(hashObject.ValueType), nil
+		}
 		return NULL, nil
 	}
 	
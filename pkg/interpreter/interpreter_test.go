@@ -3,6 +3,7 @@ package interpreter
 import (
 	"github.com/Stremax-Team/stremax-lang/pkg/lexer"
 	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"math/big"
 	"testing"
 )
 
@@ -200,13 +201,25 @@ func testEval(t *testing.T, input string) Object {
 	return result
 }
 
+// runUnchecked runs a program like Run but without the type checker, so
+// tests can exercise the checks the interpreter makes at runtime
+func runUnchecked(i *Interpreter) error {
+	program := i.parser.ParseProgram()
+	if syntaxErrors := i.parser.SyntaxErrors(); len(syntaxErrors) != 0 {
+		return syntaxErrors[0]
+	}
+
+	_, err := i.evalProgram(program)
+	return err
+}
+
 func testIntegerObject(t *testing.T, obj Object, expected int64) {
 	result, ok := obj.(*Integer)
 	if !ok {
 		t.Fatalf("object is not Integer. got=%T (%+v)", obj, obj)
 	}
-	if result.Value != expected {
-		t.Errorf("object has wrong value. got=%d, want=%d",
+	if result.Value.Cmp(big.NewInt(expected)) != 0 {
+		t.Errorf("object has wrong value. got=%s, want=%d",
 			result.Value, expected)
 	}
 }
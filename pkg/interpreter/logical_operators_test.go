@@ -159,4 +159,22 @@ func TestLogicalOperators(t *testing.T) {
 		// This would require modifying the interpreter to expose the last evaluated value
 		// For a complete test, we would need to add this functionality
 	}
-} 
\ No newline at end of file
+} 
+
+// TestLogicalShortCircuit checks that the right operand of && and || is
+// only evaluated when it decides the result
+func TestLogicalShortCircuit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"let touched = false;\nfalse && (touched = true);\ntouched;", false},
+		{"let touched = false;\ntrue || (touched = true);\ntouched;", false},
+		{"let touched = false;\ntrue && (touched = true);\ntouched;", true},
+		{"let touched = false;\nfalse || (touched = true);\ntouched;", true},
+	}
+
+	for _, tt := range tests {
+		testBooleanObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
@@ -0,0 +1,163 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWhileLoop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let x = 0; while (x < 10) { x += 1; } x;", 10},
+		{"let x = 0; while (false) { x += 1; } x;", 0},
+		{
+			`
+			let sum = 0;
+			let i = 0;
+			while (i < 5) {
+				i++;
+				sum += i;
+			}
+			sum;
+			`,
+			15,
+		},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestForLoop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let sum = 0; for (let i = 0; i < 5; i += 1) { sum += i; } sum;", 10},
+		{"let sum = 0; for (let i = 0; i < 5; i++) { sum += i; } sum;", 10},
+		{"let n = 0; let i = 0; for (i = 3; i > 0; i--) { n += 1; } n;", 3},
+		{"let n = 0; for (;;) { n += 1; if (n == 4) { break; } } n;", 4},
+		{
+			// Nested loops
+			`
+			let count = 0;
+			for (let i = 0; i < 3; i++) {
+				for (let j = 0; j < 4; j++) {
+					count += 1;
+				}
+			}
+			count;
+			`,
+			12,
+		},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestBreakAndContinue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let i = 0; while (true) { if (i == 3) { break; } i += 1; } i;", 3},
+		{
+			`
+			let sum = 0;
+			for (let i = 0; i < 10; i++) {
+				if (i == 2 || i == 4) {
+					continue;
+				}
+				if (i == 6) {
+					break;
+				}
+				sum += i;
+			}
+			sum;
+			`,
+			9, // 0 + 1 + 3 + 5
+		},
+		{
+			// break only leaves the innermost loop
+			`
+			let count = 0;
+			for (let i = 0; i < 3; i++) {
+				for (let j = 0; j < 10; j++) {
+					if (j == 2) {
+						break;
+					}
+					count += 1;
+				}
+			}
+			count;
+			`,
+			6,
+		},
+		{
+			// continue in a while loop
+			`
+			let i = 0;
+			let odd = 0;
+			while (i < 6) {
+				i++;
+				if (i == 2 || i == 4 || i == 6) {
+					continue;
+				}
+				odd += 1;
+			}
+			odd;
+			`,
+			3,
+		},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestReturnFromLoop(t *testing.T) {
+	input := `
+	let find = function(arr, target) {
+		for (let i = 0; i < arr.length; i++) {
+			if (arr[i] == target) {
+				return i;
+			}
+		}
+		return -1;
+	};
+	find([5, 6, 7, 8], 7) + find([1], 9) * 10;
+	`
+
+	testIntegerObject(t, testEval(t, input), -8)
+}
+
+func TestLoopScoping(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"for (let i = 0; i < 1; i++) { } i;", "Identifier not found: i"},
+		{"while (true) { let x = 1; break; } x;", "Identifier not found: x"},
+	}
+
+	for _, tt := range tests {
+		interpreter := New(tt.input)
+		err := interpreter.Run()
+
+		if err == nil {
+			t.Errorf("expected error but got none for input: %s", tt.input)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("wrong error message. expected=%q to contain %q for input: %s",
+				err.Error(), tt.expectedError, tt.input)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+)
+
+// evalInExpression evaluates key in map, which reports whether the map
+// holds an entry for the key
+func (i *Interpreter) evalInExpression(key, container Object, token parser.Token) (Object, error) {
+	hash, ok := container.(*Hash)
+	if !ok {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Right operand of in must be a Map, got %s", typeName(container)),
+			token.Line, token.Column, "")
+	}
+
+	hashed, ok := hashKey(key)
+	if !ok {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("unusable as hash key: %s", key.Type()),
+			token.Line, token.Column, "")
+	}
+
+	_, ok = hash.Get(hashed, key)
+	return &Boolean{Value: ok}, nil
+}
+
+// evalContainer evaluates the collection or object an assignment writes
+// into. Reading a missing entry of a declared Map yields a new zero value,
+// so when that value is a map or struct it is stored first: a write such as
+// allowances[owner][spender] = 10 then lands in the map it reads from.
+func (i *Interpreter) evalContainer(expr parser.Expression) (Object, error) {
+	switch expr := expr.(type) {
+	case *parser.IndexExpression:
+		left, err := i.evalContainer(expr.Left)
+		if err != nil {
+			return nil, err
+		}
+
+		index, err := i.evalExpression(expr.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		if hash, ok := left.(*Hash); ok {
+			return i.evalHashEntry(hash, index, expr.Token)
+		}
+		return i.evalElementAccess(left, index, expr.Token)
+	case *parser.DotExpression:
+		if expr.Optional {
+			return i.evalExpression(expr)
+		}
+
+		left, err := i.evalContainer(expr.Left)
+		if err != nil {
+			return nil, err
+		}
+
+		return i.evalFieldAccess(left, expr.Right.(*parser.Identifier).Value, expr.Token)
+	default:
+		return i.evalExpression(expr)
+	}
+}
+
+// evalHashEntry reads the entry of a map that an assignment writes through,
+// storing the zero value of a missing map or struct entry
+func (i *Interpreter) evalHashEntry(hash *Hash, index Object, token parser.Token) (Object, error) {
+	val, err := i.evalHashIndexExpression(hash, index, token)
+	if err != nil {
+		return nil, err
+	}
+
+	key, _ := hashKey(index)
+	if _, ok := hash.Get(key, index); ok {
+		return val, nil
+	}
+
+	switch val.(type) {
+	case *Hash, *Struct:
+		if err := i.setElement(hash, index, val, token); err != nil {
+			return nil, err
+		}
+	}
+	return val, nil
+}
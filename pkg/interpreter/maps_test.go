@@ -0,0 +1,110 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+// allowances is a contract that records how much one account lets another
+// spend, as token contracts do
+const allowances = `contract Allowances {
+	state { allowed: Map<Address, Map<Address, Int>> }
+
+	function approve(spender: Address, amount: Int) {
+		allowed[msg.sender][spender] = amount;
+	}
+
+	function spend(owner: Address, amount: Int) {
+		require(allowed[owner][msg.sender] >= amount, "Insufficient allowance");
+		allowed[owner][msg.sender] -= amount;
+	}
+
+	function approveThenFail(spender: Address) {
+		allowed[msg.sender][spender] = 1;
+		revert "failed";
+	}
+
+	function caller(): Address { return msg.sender; }
+	function allowance(owner: Address, spender: Address): Int { return allowed[owner][spender]; }
+	function owners(): Int { return len(allowed); }
+	function approved(owner: Address): Bool { return owner in allowed; }
+}
+
+let a = Allowances();
+let spender = 0x1111111111111111111111111111111111111111;
+`
+
+func TestNestedMapWrites(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a.approve(spender, 10);\na.allowance(a.caller(), spender);", "10"},
+		{"a.approve(spender, 10);\na.approve(spender, 4);\na.allowance(a.caller(), spender);", "4"},
+		{"a.approve(a.caller(), 10);\na.spend(a.caller(), 3);\na.allowance(a.caller(), a.caller());", "7"},
+		// Reading a missing entry does not create it
+		{"a.allowance(spender, spender);\na.owners();", "0"},
+		{"a.approve(spender, 10);\na.owners();", "1"},
+		{"a.approve(spender, 10);\n[a.approved(a.caller()), a.approved(spender)];", "[true, false]"},
+		// Entries created by a failed call are rolled back
+		{"try a.approveThenFail(spender) { } catch (e) { }\n[a.owners(), a.allowance(a.caller(), spender)];", "[0, 0]"},
+		// Declared maps outside contracts work the same way
+		{"let m: Map<String, Map<String, Map<Int, Int>>> = {};\nm[\"a\"][\"b\"][1] += 3;\nm[\"a\"][\"b\"][1]++;\nm[\"a\"][\"b\"][1];", "4"},
+		{"let m: Map<String, Array<Int>> = {};\nm[\"a\"];", "[]"},
+		{"struct P { amount: Int, open: Bool }\nlet m: Map<Int, P> = {};\nm[1].amount = 7;\n[m[1].amount, m[1].open, m[2].amount];", "[7, false, 0]"},
+		{"let m: Map<String, Map<String, Int>> = {};\nlet inner = m[\"a\"];\ninner[\"b\"] = 1;\n[len(m), m[\"a\"][\"b\"]];", "[0, 0]"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, allowances+tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestMapMembershipAndDelete(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let m = {\"a\": 1};\n[\"a\" in m, \"b\" in m, m.has(\"a\")];", "[true, false, true]"},
+		{"let m = {[1, 2]: true};\n[[1, 2] in m, (1, 2) in m];", "[true, false]"},
+		{"let m: Map<String, Int> = {};\nm[\"a\"] = 0;\n[\"a\" in m, m[\"b\"], \"b\" in m];", "[true, 0, false]"},
+		{"let m = {\"a\": 1, \"b\": 2, \"c\": 3};\n[m.delete(\"b\"), m.delete(\"b\"), \"b\" in m, m.keys()];", "[true, false, false, [a, c]]"},
+		{"let m: Map<String, Int> = {\"a\": 5};\nm.delete(\"a\");\n[m[\"a\"], len(m)];", "[0, 0]"},
+		{"let m = {\"a\": 1};\nm.delete(\"a\");\nm[\"a\"] = 2;\nm[\"z\"] = 3;\nm.keys();", "[a, z]"},
+		{"let m: Map<String, Map<String, Int>> = {};\nm[\"a\"][\"b\"] = 1;\nm[\"a\"].delete(\"b\");\n[\"a\" in m, \"b\" in m[\"a\"]];", "[true, false]"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestMapMembershipErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"1 in [1];", "Right operand of in must be a Map, got Array at :1:3"},
+		{"let m = {};\n[null] in m;", "unusable as hash key: ARRAY at :2:8"},
+		{"let m = {};\nm.delete();", "Wrong number of arguments to delete: expected 1, got 0"},
+		{"let m = {};\nm.delete([null]);", "unusable as hash key: ARRAY"},
+		{"let m: Map<String, Map<String, Int>> = {};\nm[1][\"a\"] = 1;", "Type mismatch: expected String, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
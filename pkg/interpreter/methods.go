@@ -0,0 +1,261 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// MethodFunction is the Go implementation of a method on a built-in type
+type MethodFunction func(i *Interpreter, receiver Object, args ...Object) (Object, error)
+
+// Method describes a member of a built-in type. Properties such as
+// arr.length are computed when they are read; other methods are returned
+// bound to their receiver and run when called.
+type Method struct {
+	Name     string
+	Fn       MethodFunction
+	Property bool
+}
+
+// methods holds the members of the built-in types, keyed by object type
+var methods = map[string]map[string]*Method{
+	"ARRAY": {
+		"length": {Name: "length", Property: true, Fn: arrayLength},
+		"push":   {Name: "push", Fn: arrayPush},
+		"pop":    {Name: "pop", Fn: arrayPop},
+	},
+	"STRING": {
+		"length":   {Name: "length", Property: true, Fn: stringLength},
+		"toUpper":  {Name: "toUpper", Fn: stringToUpper},
+		"toLower":  {Name: "toLower", Fn: stringToLower},
+		"contains": {Name: "contains", Fn: stringContains},
+	},
+	"HASH": {
+		"length": {Name: "length", Property: true, Fn: hashLength},
+		"keys":   {Name: "keys", Fn: hashKeys},
+		"values": {Name: "values", Fn: hashValues},
+		"has":    {Name: "has", Fn: hashHas},
+		"delete": {Name: "delete", Fn: hashDelete},
+	},
+	"ADDRESS": {
+		"balance":  {Name: "balance", Property: true, Fn: addressBalance},
+		"transfer": {Name: "transfer", Fn: addressTransfer},
+		"send":     {Name: "send", Fn: addressSend},
+	},
+}
+
+// lookupMethod finds a member of a built-in value. Properties are evaluated
+// immediately; methods are returned as builtins bound to the receiver.
+func (i *Interpreter) lookupMethod(obj Object, name string, token parser.Token) (Object, bool, error) {
+	method, ok := methods[obj.Type()][name]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if method.Property {
+		result, err := method.Fn(i, obj)
+		if err != nil {
+			return nil, true, withPosition(err, token)
+		}
+		return result, true, nil
+	}
+
+	return &Builtin{Name: name, Fn: func(args ...Object) (Object, error) {
+		return method.Fn(i, obj, args...)
+	}}, true, nil
+}
+
+// withPosition fills in the position of an error raised by a builtin, which
+// has no position of its own
+func withPosition(err error, token parser.Token) error {
+	if e, ok := err.(*errors.Error); ok && e.Line == 0 {
+		e.Line, e.Column = token.Line, token.Column
+	}
+	return err
+}
+
+// checkArgumentCount reports a call of a method with the wrong number of arguments
+func checkArgumentCount(name string, args []Object, expected int) error {
+	if len(args) != expected {
+		return errors.NewTypeError(
+			fmt.Sprintf("Wrong number of arguments to %s: expected %d, got %d", name, expected, len(args)), 0, 0, "")
+	}
+	return nil
+}
+
+func arrayLength(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	return newInteger(int64(len(receiver.(*Array).Elements))), nil
+}
+
+func arrayPush(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	if err := checkArgumentCount("push", args, 1); err != nil {
+		return nil, err
+	}
+
+	arr := receiver.(*Array)
+	elem, err := i.coerceToType(args[0], arr.ElementType, parser.Token{})
+	if err != nil {
+		return nil, err
+	}
+	arr.Elements = append(arr.Elements, elem)
+	return newInteger(int64(len(arr.Elements))), nil
+}
+
+func arrayPop(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	if err := checkArgumentCount("pop", args, 0); err != nil {
+		return nil, err
+	}
+
+	arr := receiver.(*Array)
+	if len(arr.Elements) == 0 {
+		return nil, errors.NewRuntimeError("Cannot pop from an empty array", 0, 0, "")
+	}
+
+	last := arr.Elements[len(arr.Elements)-1]
+	arr.Elements = arr.Elements[:len(arr.Elements)-1]
+	return last, nil
+}
+
+func stringLength(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	return newInteger(int64(utf8.RuneCountInString(receiver.(*String).Value))), nil
+}
+
+func stringToUpper(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	if err := checkArgumentCount("toUpper", args, 0); err != nil {
+		return nil, err
+	}
+	return &String{Value: strings.ToUpper(receiver.(*String).Value)}, nil
+}
+
+func stringToLower(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	if err := checkArgumentCount("toLower", args, 0); err != nil {
+		return nil, err
+	}
+	return &String{Value: strings.ToLower(receiver.(*String).Value)}, nil
+}
+
+func stringContains(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	if err := checkArgumentCount("contains", args, 1); err != nil {
+		return nil, err
+	}
+
+	substr, ok := args[0].(*String)
+	if !ok {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("contains requires a STRING argument, got %s", args[0].Type()), 0, 0, "")
+	}
+	return &Boolean{Value: strings.Contains(receiver.(*String).Value, substr.Value)}, nil
+}
+
+func hashLength(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	return newInteger(int64(receiver.(*Hash).Len())), nil
+}
+
+func hashKeys(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	if err := checkArgumentCount("keys", args, 0); err != nil {
+		return nil, err
+	}
+
+	hash := receiver.(*Hash)
+	keys := []Object{}
+	for _, pair := range hash.Entries() {
+		keys = append(keys, pair.Key)
+	}
+	return &Array{Elements: keys, ElementType: hash.KeyType}, nil
+}
+
+func hashValues(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	if err := checkArgumentCount("values", args, 0); err != nil {
+		return nil, err
+	}
+
+	hash := receiver.(*Hash)
+	values := []Object{}
+	for _, pair := range hash.Entries() {
+		values = append(values, pair.Value)
+	}
+	return &Array{Elements: values, ElementType: hash.ValueType}, nil
+}
+
+func hashHas(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	if err := checkArgumentCount("has", args, 1); err != nil {
+		return nil, err
+	}
+
+	key, ok := hashKey(args[0])
+	if !ok {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("unusable as hash key: %s", args[0].Type()), 0, 0, "")
+	}
+
+	_, ok = receiver.(*Hash).Get(key, args[0])
+	return &Boolean{Value: ok}, nil
+}
+
+func hashDelete(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	if err := checkArgumentCount("delete", args, 1); err != nil {
+		return nil, err
+	}
+
+	key, ok := hashKey(args[0])
+	if !ok {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("unusable as hash key: %s", args[0].Type()), 0, 0, "")
+	}
+
+	return &Boolean{Value: receiver.(*Hash).Delete(key, args[0])}, nil
+}
+
+func addressBalance(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	balance := i.bc.GetPendingBalance(receiver.(*Address).Value)
+	return &Integer{Value: balance, Kind: intTypes["Uint256"]}, nil
+}
+
+// addressTransfer sends funds from the running contract, or from the sender
+// outside of a contract, failing if they cannot be covered
+func addressTransfer(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	amount, err := transferAmount("transfer", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := i.bc.Transfer(i.payer(), receiver.(*Address).Value, amount); err != nil {
+		return nil, errors.NewRuntimeError(fmt.Sprintf("Transfer failed: %s", err), 0, 0, "")
+	}
+	return NULL, nil
+}
+
+// addressSend is like addressTransfer but reports failure as false instead
+// of an error
+func addressSend(i *Interpreter, receiver Object, args ...Object) (Object, error) {
+	amount, err := transferAmount("send", args)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = i.bc.Transfer(i.payer(), receiver.(*Address).Value, amount)
+	return &Boolean{Value: err == nil}, nil
+}
+
+// transferAmount validates the amount argument of transfer and send
+func transferAmount(name string, args []Object) (*big.Int, error) {
+	if err := checkArgumentCount(name, args, 1); err != nil {
+		return nil, err
+	}
+
+	amount, ok := args[0].(*Integer)
+	if !ok {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("%s requires an integer amount, got %s", name, args[0].Type()), 0, 0, "")
+	}
+	if amount.Value.Sign() < 0 {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("Cannot %s a negative amount: %s", name, amount.Value), 0, 0, "")
+	}
+
+	return amount.Value, nil
+}
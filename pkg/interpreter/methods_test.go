@@ -0,0 +1,124 @@
+package interpreter
+
+import (
+	"github.com/Stremax-Team/stremax-lang/pkg/blockchain"
+	"github.com/Stremax-Team/stremax-lang/pkg/lexer"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let a = [1, 2]; a.push(3); a;`, "[1, 2, 3]"},
+		{`let a = [1, 2]; a.push(3);`, "3"},
+		{`[1, 2, 3].length;`, "3"},
+		{`[].length;`, "0"},
+		{`let a = [1, 2, 3]; let last = a.pop(); [last, a.length];`, "[3, 2]"},
+		{
+			// Arrays are reference values, so methods mutate every copy
+			`let a = [1]; let b = a; b.push(2); a.length;`,
+			"2",
+		},
+		{`"hello".length;`, "5"},
+		{`"héllo".length;`, "5"},
+		{`"Hello".toUpper();`, "HELLO"},
+		{`"Hello".toLower();`, "hello"},
+		{`"stremax".contains("max");`, "true"},
+		{`let s = "abc"; s.toUpper().length;`, "3"},
+		{`let m = {"b": 1, "a": 2}; m.keys();`, "[b, a]"},
+		{`let m = {"b": 1, "a": 2}; m.values();`, "[1, 2]"},
+		{`let m = {"a": 1}; [m.has("a"), m.has("z")];`, "[true, false]"},
+		{`let m = {1: "one"}; m.has(1);`, "true"},
+		{`let m = {"a": 1, "b": 2}; m.length;`, "2"},
+		{
+			// Map methods take precedence over entries, which are read by
+			// index
+			`let m = {"length": 7}; [m.length, m["length"]];`,
+			"[1, 7]",
+		},
+		{`let m = {"delete": 1}; m.delete("delete"); m.length;`, "0"},
+		{`let m = {"a": 1}; m.a;`, "1"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestBuiltinMethodErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`[].pop();`, "Cannot pop from an empty array"},
+		{`[1].push();`, "Wrong number of arguments to push: expected 1, got 0"},
+		{`"abc".toUpper(1);`, "Wrong number of arguments to toUpper: expected 0, got 1"},
+		{`"abc".contains(1);`, "contains requires a STRING argument, got INTEGER"},
+		{`"abc".reverse();`, "field access not supported: STRING.reverse"},
+		{`let m = {}; m.has([null]);`, "unusable as hash key: ARRAY"},
+		{`[1].length();`, "Not a function: INTEGER"},
+		{`let m = {}; m.keys = 1;`, `Cannot assign to map method keys; use ["keys"] to set the entry`},
+		{`msg.sender.transfer("ten");`, "transfer requires an integer amount, got STRING"},
+		{`msg.sender.transfer(-1);`, "Cannot transfer a negative amount: -1"},
+		{`msg.sender.transfer(10);`, "Transfer failed: insufficient balance"},
+	}
+
+	for _, tt := range tests {
+		// msg is normally only defined inside contract functions
+		interp := New(tt.input)
+		interp.env.Set("msg", interp.newMessage())
+		err := runUnchecked(interp)
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
+
+func TestAddressMethods(t *testing.T) {
+	input := `
+	contract Vault {
+		function payout(amount: Int): Bool {
+			return msg.sender.send(amount);
+		}
+
+		function withdraw(amount: Int) {
+			msg.sender.transfer(amount);
+		}
+	}
+
+	let vault = Vault();
+	vault.address.transfer(100);
+	let refunded = vault.payout(30);
+	let refused = vault.payout(1000);
+	vault.withdraw(20);
+	[vault.address.balance, refunded, refused];
+	`
+
+	program := parser.New(lexer.New(input)).ParseProgram()
+	interp := New(input)
+	interp.bc.CreateTransaction(blockchain.Address("SYSTEM"), defaultSender, big.NewInt(500), nil)
+
+	result, err := interp.evalProgram(program)
+	if err != nil {
+		t.Fatalf("evalProgram error: %s", err)
+	}
+	if result.Inspect() != "[50, true, false]" {
+		t.Errorf("wrong result. got=%s, want=%s", result.Inspect(), "[50, true, false]")
+	}
+
+	if got := interp.bc.GetPendingBalance(defaultSender); got.Cmp(big.NewInt(450)) != 0 {
+		t.Errorf("wrong sender balance. got=%s, want=450", got)
+	}
+}
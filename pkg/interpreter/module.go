@@ -0,0 +1,263 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/checker"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/lexer"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Module represents an imported source file. Its top-level bindings live in
+// Env; those whose names do not start with an underscore are exported.
+type Module struct {
+	Name string // the name the module is bound to by default
+	Path string // the absolute path of the source file
+	Env  *Environment
+}
+
+// Type returns the type of the Module object
+func (m *Module) Type() string { return "MODULE" }
+
+// Inspect returns a string representation of the Module object
+func (m *Module) Inspect() string { return fmt.Sprintf("module %s", m.Name) }
+
+// export returns an exported top-level binding of the module
+func (m *Module) export(name string) (Object, bool) {
+	if strings.HasPrefix(name, "_") {
+		return nil, false
+	}
+	val, ok := m.Env.store[name]
+	return val, ok
+}
+
+// NewFromFile creates an interpreter for the Stremax-Lang program in the
+// given file. Imports in the program are resolved relative to the file.
+//
+// Parameters:
+//   - path: The path of the Stremax-Lang source file
+//
+// Returns:
+//   - A new Interpreter instance, or an error if the file cannot be read
+func NewFromFile(path string) (*Interpreter, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	i := New(string(source))
+	i.path = absolutePath(path)
+	i.parser.SetFile(i.path)
+	i.importStack = []string{i.path}
+
+	return i, nil
+}
+
+// evalImportStatement evaluates an import, binding either the module
+// namespace or the selected exports in the current environment
+func (i *Interpreter) evalImportStatement(stmt *parser.ImportStatement) (Object, error) {
+	module, err := i.loadModule(stmt.Path.Value, stmt.Path.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stmt.Names) == 0 {
+		name := module.Name
+		if stmt.Alias != nil {
+			name = stmt.Alias.Value
+		}
+		if name == "" {
+			return nil, errors.NewReferenceError(
+				fmt.Sprintf("Cannot name module %q; use import %q as name", stmt.Path.Value, stmt.Path.Value),
+				stmt.Token.Line, stmt.Token.Column, "")
+		}
+
+		i.env.Set(name, module)
+		return module, nil
+	}
+
+	for _, name := range stmt.Names {
+		val, ok := module.export(name.Value)
+		if !ok {
+			return nil, errors.NewReferenceError(
+				fmt.Sprintf("Module %s has no export %s", stmt.Path.Value, name.Value),
+				name.Token.Line, name.Token.Column, "")
+		}
+		if module.Env.IsConstant(name.Value) {
+			i.env.SetConstant(name.Value, val, module.Env.types[name.Value])
+		} else {
+			i.env.Set(name.Value, val)
+		}
+	}
+
+	return module, nil
+}
+
+// loadModule returns the module for an import path, evaluating its file
+// the first time it is imported. Importing a module that is still being
+// evaluated is an import cycle.
+func (i *Interpreter) loadModule(importPath string, token parser.Token) (*Module, error) {
+	path := i.resolveImport(importPath)
+
+	for idx, loading := range i.importStack {
+		if loading == path {
+			cycle := []string{}
+			for _, p := range append(i.importStack[idx:], path) {
+				cycle = append(cycle, filepath.Base(p))
+			}
+			return nil, errors.NewRuntimeError(
+				fmt.Sprintf("Import cycle: %s", strings.Join(cycle, " -> ")),
+				token.Line, token.Column, "")
+		}
+	}
+
+	if module, ok := i.modules[path]; ok {
+		return module, nil
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("Cannot import %q: %s", importPath, err),
+			token.Line, token.Column, "")
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	p.SetFile(path)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		if syntaxErrors := p.SyntaxErrors(); len(syntaxErrors) != 0 {
+			return nil, syntaxErrors[0]
+		}
+		return nil, errors.NewSyntaxError(
+			fmt.Sprintf("Failed to parse module %q: %s", importPath, p.Errors()[0]),
+			token.Line, token.Column, "")
+	}
+
+	// The modules of a strict program are checked strictly too
+	c := checker.New()
+	c.SetStrict(i.strict)
+	c.SetImporter(i.checkerImporter(path, append(append([]string{}, i.importStack...), path)))
+	c.Check(program)
+	if typeErrors := c.Errors(); len(typeErrors) != 0 {
+		err := *typeErrors[0]
+		err.File = path
+		return nil, &err
+	}
+
+	// Modules see the built-ins but not the bindings of their importer
+	module := &Module{
+		Name: moduleName(importPath),
+		Path: path,
+		Env:  NewEnclosedEnvironment(i.builtins),
+	}
+
+	// A module's pragma strict does not carry over to its importer
+	previousPath, previousStrict := i.path, i.strict
+	i.path = path
+	i.importStack = append(i.importStack, path)
+	defer func() {
+		i.path, i.strict = previousPath, previousStrict
+		i.importStack = i.importStack[:len(i.importStack)-1]
+	}()
+
+	if _, err := i.evalInEnv(module.Env, func() (Object, error) { return i.evalProgram(program) }); err != nil {
+		// Report errors inside the module against its file
+		if e, ok := err.(*errors.Error); ok && e.File == "" {
+			e.File = path
+		}
+		return nil, err
+	}
+
+	i.modules[path] = module
+	return module, nil
+}
+
+// resolveImport turns an import path into an absolute file path. Relative
+// paths are resolved against the directory of the importing file, and a
+// missing extension defaults to .sx.
+func (i *Interpreter) resolveImport(importPath string) string {
+	return resolveImportFrom(i.path, importPath)
+}
+
+// resolveImportFrom resolves an import path written in the file at from
+func resolveImportFrom(from, importPath string) string {
+	if filepath.Ext(importPath) == "" {
+		importPath += ".sx"
+	}
+	if !filepath.IsAbs(importPath) {
+		importPath = filepath.Join(filepath.Dir(from), importPath)
+	}
+	return absolutePath(importPath)
+}
+
+// checkerImporter loads the modules imported by the file at from for the
+// type checker. stack lists the files being checked, so that an import
+// cycle is left for the interpreter to report. Each file is read and parsed
+// once, the first time any file imports it.
+func (i *Interpreter) checkerImporter(from string, stack []string) checker.Importer {
+	return func(importPath string) *checker.Module {
+		path := resolveImportFrom(from, importPath)
+		for _, loading := range stack {
+			if loading == path {
+				return nil
+			}
+		}
+
+		if module, ok := i.checkerModules[path]; ok {
+			return module
+		}
+
+		var module *checker.Module
+		if source, err := os.ReadFile(path); err == nil {
+			p := parser.New(lexer.New(string(source)))
+			program := p.ParseProgram()
+			if len(p.Errors()) == 0 {
+				nested := append(append([]string{}, stack...), path)
+				module = &checker.Module{
+					Name:     moduleName(importPath),
+					Program:  program,
+					Importer: i.checkerImporter(path, nested),
+				}
+			}
+		}
+
+		i.checkerModules[path] = module
+		return module
+	}
+}
+
+// absolutePath returns the cleaned absolute form of a path
+func absolutePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// moduleName derives the default namespace name of a module from its file
+// name, or returns "" if the file name is not a valid identifier
+func moduleName(importPath string) string {
+	name := strings.TrimSuffix(filepath.Base(importPath), filepath.Ext(importPath))
+
+	l := lexer.New(name)
+	tok := l.NextToken()
+	if tok.Type != lexer.IDENT || tok.Literal != name || len(l.Errors()) != 0 {
+		return ""
+	}
+	return name
+}
+
+// evalModuleMember reads an exported binding of a module
+func (i *Interpreter) evalModuleMember(module *Module, name string, token parser.Token) (Object, error) {
+	val, ok := module.export(name)
+	if !ok {
+		return nil, errors.NewReferenceError(
+			fmt.Sprintf("Module %s has no export %s", module.Name, name),
+			token.Line, token.Column, "")
+	}
+	return val, nil
+}
@@ -0,0 +1,233 @@
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeModules writes source files into a temporary directory and returns
+// the directory
+func writeModules(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// evalFile evaluates the program in a file, resolving its imports
+func evalFile(t *testing.T, path string) (Object, error) {
+	t.Helper()
+
+	interp, err := NewFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program := interp.parser.ParseProgram()
+	if len(interp.parser.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", interp.parser.Errors())
+	}
+	return interp.evalProgram(program)
+}
+
+func TestImports(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"lib/safemath.sx": `
+			function add(a: Int, b: Int): Int { return a + b; }
+			function _check(x: Int): Bool { return x >= 0; }
+			let ONE = 1;
+			`,
+		"lib/counter.sx": `
+			let count = 0;
+			function next(): Int { count += 1; return count; }
+			`,
+		"lib/uses_counter.sx": `
+			import "counter.sx"
+			function bump(): Int { return counter.next(); }
+			`,
+		"tokens/token.sx": `
+			import { add } from "../lib/safemath.sx"
+			function transfer(balance: Int, amount: Int): Int { return add(balance, amount); }
+			`,
+		"namespace.sx": `import "lib/safemath.sx"` + "\n" + `safemath.add(2, safemath.ONE);`,
+		"alias.sx":     `import "lib/safemath" as sm` + "\n" + `sm.add(20, 22);`,
+		"selected.sx":  `import { add, ONE } from "./lib/safemath.sx"` + "\n" + `add(ONE, 9);`,
+		"nested.sx":    `import { transfer } from "tokens/token.sx"` + "\n" + `transfer(100, 5);`,
+		"cached.sx":    "import \"lib/counter.sx\"\nimport \"lib/uses_counter.sx\"\ncounter.next();\nuses_counter.bump();\ncounter.next();",
+		"isolated.sx":  "let secret = 5;\nimport \"lib/safemath.sx\"\nsafemath.add(secret, 1);",
+	})
+
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{"namespace.sx", "3"},
+		{"alias.sx", "42"},
+		{"selected.sx", "10"},
+		{"nested.sx", "105"},
+		// Both imports share one evaluation of counter.sx
+		{"cached.sx", "3"},
+		{"isolated.sx", "6"},
+	}
+
+	for _, tt := range tests {
+		result, err := evalFile(t, filepath.Join(dir, tt.file))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.file, err)
+			continue
+		}
+		if result.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. got=%s, want=%s", tt.file, result.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"a.sx":          `import "b.sx"`,
+		"b.sx":          `import "c.sx"`,
+		"c.sx":          `import "a.sx"`,
+		"self.sx":       `import "self.sx"`,
+		"lib.sx":        `let _hidden = 1; let shown = 2;`,
+		"broken.sx":     "let x = 1;\nlet y = \"unterminated;",
+		"failing.sx":    "let x = 1;\nmissing;",
+		"my-lib.sx":     `let x = 1;`,
+		"missing.sx":    `import "nowhere.sx"`,
+		"private.sx":    `import { _hidden } from "lib.sx"`,
+		"unknown.sx":    `import "lib.sx"` + "\n" + `lib.other;`,
+		"syntax.sx":     `import "broken.sx"`,
+		"runtime.sx":    `import "failing.sx"`,
+		"badname.sx":    `import "my-lib.sx"`,
+		"noimporter.sx": "let outer = 1;\nimport \"uses_outer.sx\"",
+		"uses_outer.sx": `outer;`,
+	})
+
+	tests := []struct {
+		file          string
+		expectedError string
+	}{
+		{"a.sx", "Import cycle: a.sx -> b.sx -> c.sx -> a.sx"},
+		{"self.sx", "Import cycle: self.sx -> self.sx"},
+		{"missing.sx", `Cannot import "nowhere.sx"`},
+		{"private.sx", "Module lib.sx has no export _hidden"},
+		{"unknown.sx", "Module lib has no export other"},
+		{"syntax.sx", "SyntaxError: Unterminated string literal at " + filepath.Join(dir, "broken.sx") + ":2:9"},
+		{"runtime.sx", "Identifier not found: missing at " + filepath.Join(dir, "failing.sx") + ":2:1"},
+		{"badname.sx", `Cannot name module "my-lib.sx"; use import "my-lib.sx" as name`},
+		{"noimporter.sx", "Identifier not found: outer"},
+	}
+
+	for _, tt := range tests {
+		_, err := evalFile(t, filepath.Join(dir, tt.file))
+		if err == nil {
+			t.Errorf("%s: expected error", tt.file)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("%s: expected error containing %q, got %q", tt.file, tt.expectedError, err.Error())
+		}
+	}
+}
+
+func TestImportedTypesChecked(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"phases.sx":     "enum Phase { Open, Ended }",
+		"partial.sx":    "import { Phase } from \"phases.sx\"\nlet p = Phase.Open;\nmatch (p) { Phase.Open => 1 };",
+		"namespaced.sx": "import \"phases.sx\"\nlet p = phases.Phase.Open;\nmatch (p) { phases.Phase.Open => 1, phases.Phase.Ended => 2 };",
+		"a.sx":          "import \"b.sx\"\nenum Side { Left }",
+		"b.sx":          `import { Side } from "a.sx"`,
+	})
+
+	interp, err := NewFromFile(filepath.Join(dir, "partial.sx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp.SetOutput(&strings.Builder{})
+	expected := "TypeError: Match on Phase is not exhaustive: missing Phase.Ended at " + filepath.Join(dir, "partial.sx") + ":3:1"
+	if err := interp.Run(); err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+
+	if result, err := evalFile(t, filepath.Join(dir, "namespaced.sx")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	} else {
+		testIntegerObject(t, result, 1)
+	}
+
+	// An import cycle does not stop the checker; the interpreter reports it
+	interp, err = NewFromFile(filepath.Join(dir, "a.sx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	interp.SetOutput(&strings.Builder{})
+	if err := interp.Run(); err == nil || !strings.Contains(err.Error(), "Import cycle: a.sx -> b.sx -> a.sx") {
+		t.Errorf("expected an import cycle error, got %v", err)
+	}
+}
+
+func TestEntryFileErrors(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"syntax.sx":  "let x = 1;\nlet y = \"unterminated;",
+		"type.sx":    "let x: Int = \"one\";",
+		"runtime.sx": "let x = 0;\n1 / x;",
+	})
+
+	tests := []struct {
+		file     string
+		expected string
+	}{
+		{"syntax.sx", "SyntaxError: Unterminated string literal at %s:2:9"},
+		{"type.sx", "TypeError: Type mismatch: expected Int, got String at %s:1:14"},
+		{"runtime.sx", "RuntimeError: Division by zero at %s:2:3"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.file)
+		interp, err := NewFromFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out strings.Builder
+		interp.SetOutput(&out)
+
+		expected := fmt.Sprintf(tt.expected, path)
+		if err := interp.Run(); err == nil || err.Error() != expected {
+			t.Errorf("%s: expected error %q, got %v", tt.file, expected, err)
+		}
+		if strings.Count(out.String(), expected) != 1 {
+			t.Errorf("%s: expected the error to be printed once, got %q", tt.file, out.String())
+		}
+	}
+}
+
+func TestCheckerModulesParsedOnce(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"shared.sx": "let limit = 10;",
+		"left.sx":   `import "shared.sx"`,
+		"right.sx":  `import "shared.sx"`,
+		"main.sx":   "import \"left.sx\"\nimport \"right.sx\"",
+	})
+
+	interp, err := NewFromFile(filepath.Join(dir, "main.sx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stack := []string{interp.path}
+	left := interp.checkerImporter(filepath.Join(dir, "left.sx"), stack)("shared.sx")
+	right := interp.checkerImporter(filepath.Join(dir, "right.sx"), stack)("shared.sx")
+	if left == nil || left != right {
+		t.Errorf("expected both importers to share one module, got %p and %p", left, right)
+	}
+}
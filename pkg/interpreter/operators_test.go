@@ -0,0 +1,64 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArithmeticOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"7 % 3", 1},
+		{"-7 % 3", -1},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"(-2) ** 3", -8},
+		{"5 ** 0", 1},
+		{"10 ** 18", 1000000000000000000},
+		{"6 & 3", 2},
+		{"6 | 3", 7},
+		{"6 ^ 3", 5},
+		{"~5", -6},
+		{"1 << 4", 16},
+		{"256 >> 4", 16},
+		{"-16 >> 2", -4},
+		{"1 + 2 * 3 % 4", 3},
+		{"let x = 10; x %= 4; x;", 2},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestArithmeticOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"2 ** 255", "Integer overflow"},
+		{"2 ** 1000", "Integer overflow"},
+		{"10 ** 77", "Integer overflow"},
+		{"1 << 255", "Integer overflow"},
+		{"2 ** -1", "Negative exponent"},
+		{"1 << -1", "Negative shift amount"},
+		{"5 % 0", "Modulo by zero"},
+		{"5 / 0", "Division by zero"},
+		{"~true", "Cannot apply ~ to non-integer"},
+		{"\"a\" % 2", "Type mismatch"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
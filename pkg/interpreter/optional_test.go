@@ -0,0 +1,131 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOptionalValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"null;", "null"},
+		{"let x: Int? = null;\nx;", "null"},
+		{"let x: Int? = 5;\nx;", "5"},
+		{"let x: Uint8? = 5;\ntypeof(x);", "Uint8"},
+		{"let x: Int? = null;\nx = 3;\nx;", "3"},
+		{"null == null;", "true"},
+		{"let x: Int? = null;\nx == null;", "true"},
+		{"struct P { nickname: String? }\nlet p = P {};\np.nickname;", "null"},
+		{"function find(id: Int): String? { if (id == 1) { return \"one\"; } return null; }\nfind(2);", "null"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNullCoalescing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"null ?? 5;", "5"},
+		{"3 ?? 5;", "3"},
+		{"let x: Int? = null;\nx ?? 0;", "0"},
+		{"let x: Int? = null;\nlet y: Int? = null;\nx ?? y ?? 7;", "7"},
+		{"false ?? true;", "false"},
+		{"let m = {\"a\": 1};\nm[\"b\"] ?? -1;", "-1"},
+		// The default is only evaluated when it is needed
+		{"let calls = 0;\nlet f = function() { calls += 1; return 0; };\n1 ?? f();\ncalls;", "0"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestSafeAccess(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let s: String? = null;\ns?.length;", "null"},
+		{"let s: String? = \"abc\";\ns?.length;", "3"},
+		{"let s: String? = null;\ns?.toUpper();", "null"},
+		{"let s: String? = \"abc\";\ns?.toUpper();", "ABC"},
+		{"let s: String? = null;\ns?.length ?? 0;", "0"},
+		{"struct P { next: Q? }\nstruct Q { name: String }\nlet p = P {};\np.next?.name ?? \"none\";", "none"},
+		{"struct P { next: Q? }\nstruct Q { name: String }\nlet p = P { next: Q { name: \"q\" } };\np.next?.name;", "q"},
+		// Arguments of a skipped call are not evaluated
+		{"let calls = 0;\nlet f = function() { calls += 1; return \"x\"; };\nlet s: String? = null;\ns?.contains(f());\ncalls;", "0"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTypedMapReads(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let m: Map<String, Int> = {};\nm[\"missing\"];", "0"},
+		{"let m: Map<String, Uint256> = {};\ntypeof(m[\"missing\"]);", "Uint256"},
+		{"let m: Map<String, Bool> = {};\nm[\"missing\"];", "false"},
+		{"let m: Map<String, String> = {};\nm.missing;", ""},
+		{"let m: Map<String, Int?> = {};\nm[\"missing\"];", "null"},
+		{"let m: Map<String, Int> = {};\nm[\"a\"] += 5;\nm[\"a\"];", "5"},
+		// Reading a missing key does not store it
+		{"let m: Map<String, Int> = {};\nm[\"a\"];\nm.length;", "0"},
+		// Untyped maps still read missing keys as null
+		{"let m = {};\nm[\"missing\"];", "null"},
+		{
+			"contract Token {\n state { balances: Map<Address, Uint256> }\n function mint(to: Address, amount: Uint256) { balances[to] += amount; }\n function balanceOf(a: Address): Uint256 { return balances[a]; }\n}\nlet t = Token();\nlet a = 0x0000000000000000000000000000000000000001;\nt.mint(a, 10);\nt.mint(a, 5);\nt.balanceOf(a);",
+			"15",
+		},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestNullRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let xs = [1, 2];\nxs[2];", "RuntimeError: array index out of range: 2 (length 2) at :2:3"},
+		{"let xs = [1, 2];\nxs[-1];", "RuntimeError: array index out of range: -1 (length 2) at :2:3"},
+		{"[][0];", "RuntimeError: array index out of range: 0 (length 0) at :1:3"},
+		{"let x: Int = null;", "Type mismatch: expected Int, got NULL"},
+		{"let x: Int? = \"a\";", "Type mismatch: expected Int, got STRING"},
+		{"let s: String? = null;\ns.length;", "field access not supported: NULL.length"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
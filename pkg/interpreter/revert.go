@@ -0,0 +1,353 @@
+package interpreter
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/blockchain"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"strings"
+)
+
+// ErrorType represents a custom error declaration such as
+// `error InsufficientBalance(needed: Int, available: Int)`. Calling it
+// creates an error value, which revert fails the running call with.
+type ErrorType struct {
+	Name   string
+	Fields []*parser.ParameterStatement
+	Token  parser.Token // the declaration; zero for the built-in error types
+}
+
+// Type returns the type of the ErrorType object
+func (et *ErrorType) Type() string { return "ERROR_TYPE" }
+
+// Inspect returns a string representation of the ErrorType object
+func (et *ErrorType) Inspect() string { return fmt.Sprintf("error %s", et.Name) }
+
+// ErrorValue represents an error: the structured data a failed call carries
+// to the code that catches it and to the receipt of its transaction
+type ErrorValue struct {
+	Definition *ErrorType
+	Values     []Object // the field values, in declaration order
+}
+
+// Type returns the type of the ErrorValue object
+func (ev *ErrorValue) Type() string { return "ERROR" }
+
+// Inspect returns a string representation of the ErrorValue object
+func (ev *ErrorValue) Inspect() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for idx, f := range ev.Definition.Fields {
+		fields = append(fields, fmt.Sprintf("%s: %s", f.Name.Value, ev.Values[idx].Inspect()))
+	}
+
+	out.WriteString(ev.Definition.Name)
+	out.WriteString("(")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// The built-in error types. Error carries the message of a failed require
+// or of a revert with a message; Panic carries the message of a failed
+// assert or of any other runtime failure, such as an overflow.
+var (
+	messageErrorType = newMessageErrorType("Error")
+	panicErrorType   = newMessageErrorType("Panic")
+)
+
+// newMessageErrorType creates a built-in error type with a single message field
+func newMessageErrorType(name string) *ErrorType {
+	return &ErrorType{
+		Name: name,
+		Fields: []*parser.ParameterStatement{{
+			Name: &parser.Identifier{Value: "message"},
+			Type: &parser.TypeExpression{Type: "String"},
+		}},
+	}
+}
+
+// newMessageError creates an error of a built-in error type
+func newMessageError(definition *ErrorType, message string) *ErrorValue {
+	return &ErrorValue{Definition: definition, Values: []Object{&String{Value: message}}}
+}
+
+// evalErrorStatement evaluates an error declaration, binding its name to
+// the error type
+func (i *Interpreter) evalErrorStatement(stmt *parser.ErrorStatement) (Object, error) {
+	seen := make(map[string]bool)
+	for _, f := range stmt.Fields {
+		if seen[f.Name.Value] {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Duplicate field %s in error %s", f.Name.Value, stmt.Name.Value),
+				f.Token.Line, f.Token.Column, "")
+		}
+		seen[f.Name.Value] = true
+	}
+
+	errorType := &ErrorType{Name: stmt.Name.Value, Fields: stmt.Fields, Token: stmt.Token}
+	i.env.Set(errorType.Name, errorType)
+	return errorType, nil
+}
+
+// newErrorValue creates an error of the given type, checking the arguments
+// against its declared fields
+func (i *Interpreter) newErrorValue(definition *ErrorType, args []Object, token parser.Token) (Object, error) {
+	args, err := i.arrangeArguments(definition, args, nil, token)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]Object, len(args))
+	for idx, field := range definition.Fields {
+		val := args[idx]
+		if val == nil {
+			if val, err = i.evalExpression(field.Default); err != nil {
+				return nil, err
+			}
+		}
+		if values[idx], err = i.coerceToType(val, field.Type, token); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ErrorValue{Definition: definition, Values: values}, nil
+}
+
+// evalErrorField reads a field of an error
+func (i *Interpreter) evalErrorField(ev *ErrorValue, name string, token parser.Token) (Object, error) {
+	for idx, f := range ev.Definition.Fields {
+		if f.Name.Value == name {
+			return ev.Values[idx], nil
+		}
+	}
+	return nil, errors.NewReferenceError(
+		fmt.Sprintf("Error %s has no field %s", ev.Definition.Name, name),
+		token.Line, token.Column, "")
+}
+
+// evalRevertStatement evaluates a revert statement, failing the running
+// call with an error value, or with a message carried by an Error
+func (i *Interpreter) evalRevertStatement(stmt *parser.RevertStatement) (Object, error) {
+	val, err := i.evalExpression(stmt.Error)
+	if err != nil {
+		return nil, err
+	}
+
+	switch val := val.(type) {
+	case *ErrorValue:
+		return nil, errors.NewRevertError(val.Inspect(), val, stmt.Token.Line, stmt.Token.Column, "")
+	case *String:
+		return nil, errors.NewRevertError(val.Value, newMessageError(messageErrorType, val.Value),
+			stmt.Token.Line, stmt.Token.Column, "")
+	default:
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Cannot revert with %s; use an error such as Unauthorized() or a message", typeName(val)),
+			stmt.Token.Line, stmt.Token.Column, "")
+	}
+}
+
+// evalAssertStatement evaluates an assert statement. A failed assert is
+// reported as an AssertionError carrying a Panic, so that broken invariants
+// are told apart from input rejected by require.
+func (i *Interpreter) evalAssertStatement(stmt *parser.AssertStatement) (Object, error) {
+	condition, err := i.evalExpression(stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if holds, err := i.isTrue(condition, "assert", stmt.Token); err != nil || holds {
+		return nil, err
+	}
+
+	message := "Assertion failed"
+	if stmt.Message != nil {
+		msgObj, err := i.evalExpression(stmt.Message)
+		if err != nil {
+			return nil, err
+		}
+		if str, ok := msgObj.(*String); ok {
+			message = str.Value
+		}
+	}
+
+	failure := errors.NewAssertionError(message, stmt.Token.Line, stmt.Token.Column, "")
+	failure.Data = newMessageError(panicErrorType, message)
+	return nil, failure
+}
+
+// evalTryStatement evaluates a try statement. The guarded call must call a
+// contract function or deploy a contract. If it fails, the changes it made
+// are rolled back and the first catch clause matching its error runs; a
+// failure no clause matches is passed on to the caller.
+func (i *Interpreter) evalTryStatement(stmt *parser.TryStatement) (Object, error) {
+	token := stmt.Call.Token
+
+	function, err := i.evalExpression(stmt.Call.Function)
+	if err != nil {
+		return nil, err
+	}
+
+	method, isMethod := function.(*BoundMethod)
+	if _, isContract := function.(*Contract); !isMethod && !isContract {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("try requires a contract call or deployment, got a call of %s", typeName(function)),
+			token.Line, token.Column, "")
+	}
+
+	// Failures evaluating the arguments are not the call's, so they are not caught
+	args, err := i.evalExpressions(stmt.Call.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	if stmt.Call.Names != nil {
+		args, err = i.arrangeArguments(function, args, stmt.Call.Names, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result Object
+	var callErr error
+	if isMethod && i.contract == nil {
+		// A call from outside any contract is a transaction, which rolls
+		// itself back if it fails
+		result, callErr = i.transact(method, args, token)
+	} else {
+		saved := i.checkpoint()
+		result, callErr = i.applyFunction(function, args, token)
+		if callErr != nil {
+			saved.rollback()
+		}
+	}
+
+	if callErr == nil {
+		env := NewEnclosedEnvironment(i.env)
+		if stmt.Result != nil {
+			if result == nil {
+				result = NULL
+			}
+			env.Set(stmt.Result.Value, result)
+		}
+		return i.evalInEnv(env, func() (Object, error) { return i.evalBlockStatement(stmt.Body) })
+	}
+
+	failure := failureValue(callErr)
+	for _, clause := range stmt.Catches {
+		env := NewEnclosedEnvironment(i.env)
+
+		if clause.ErrorName != nil {
+			definition, err := i.lookupErrorType(clause.ErrorName)
+			if err != nil {
+				return nil, err
+			}
+			if len(clause.Parameters) != len(definition.Fields) {
+				return nil, errors.NewTypeError(
+					fmt.Sprintf("Wrong number of parameters to catch %s: expected %d, got %d",
+						definition.Name, len(definition.Fields), len(clause.Parameters)),
+					clause.Token.Line, clause.Token.Column, "")
+			}
+			if failure.Definition != definition {
+				continue
+			}
+			for idx, param := range clause.Parameters {
+				env.Set(param.Value, failure.Values[idx])
+			}
+		} else if len(clause.Parameters) == 1 {
+			env.Set(clause.Parameters[0].Value, failure)
+		}
+
+		return i.evalInEnv(env, func() (Object, error) { return i.evalBlockStatement(clause.Body) })
+	}
+
+	return nil, callErr
+}
+
+// lookupErrorType resolves the name in a catch clause to the error type it
+// is bound to
+func (i *Interpreter) lookupErrorType(name *parser.Identifier) (*ErrorType, error) {
+	obj, ok := i.env.Get(name.Value)
+	if !ok {
+		return nil, errors.NewReferenceError(
+			fmt.Sprintf("Unknown error type: %s", name.Value),
+			name.Token.Line, name.Token.Column, "")
+	}
+
+	definition, ok := obj.(*ErrorType)
+	if !ok {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("%s is not an error type", name.Value),
+			name.Token.Line, name.Token.Column, "")
+	}
+
+	return definition, nil
+}
+
+// failureValue returns the error value a failure carries. Failures that
+// carry none, such as overflows, are reported as a Panic with their message.
+func failureValue(err error) *ErrorValue {
+	e, ok := err.(*errors.Error)
+	if !ok {
+		return newMessageError(panicErrorType, err.Error())
+	}
+	if val, ok := e.Data.(*ErrorValue); ok {
+		return val
+	}
+	return newMessageError(panicErrorType, e.Message)
+}
+
+// encodeFailure converts an error value into the failure recorded in a
+// transaction receipt
+func encodeFailure(val *ErrorValue) *blockchain.Failure {
+	failure := &blockchain.Failure{Error: val.Definition.Name, Args: []blockchain.FailureArg{}}
+	for idx, f := range val.Definition.Fields {
+		failure.Args = append(failure.Args, blockchain.FailureArg{
+			Name:  f.Name.Value,
+			Type:  f.Type.String(),
+			Value: val.Values[idx].Inspect(),
+		})
+	}
+	return failure
+}
+
+// transact calls a contract function from outside any contract. The call
+// is a transaction: it is recorded on the blockchain with a receipt, and if
+// it fails, every change it made to contract state and the blockchain is
+// rolled back.
+func (i *Interpreter) transact(method *BoundMethod, args []Object, token parser.Token) (Object, error) {
+	saved := i.checkpoint()
+	result, err := i.callMethod(method, args, token)
+
+	call := fmt.Sprintf("%s(%s)", method.Method.Name, strings.Join(i.inspectArguments(method, args, token), ", "))
+
+	if err != nil {
+		saved.rollback()
+		i.bc.RecordCall(i.sender, method.Instance.Address, call, encodeFailure(failureValue(err)))
+		return nil, err
+	}
+
+	i.bc.RecordCall(i.sender, method.Instance.Address, call, nil)
+	return result, nil
+}
+
+// inspectArguments shows the arguments of a recorded call, one per
+// parameter. An omitted argument is shown as the default value expression
+// of its parameter.
+func (i *Interpreter) inspectArguments(method *BoundMethod, args []Object, token parser.Token) []string {
+	if arranged, err := i.arrangeArguments(method, args, nil, token); err == nil {
+		args = arranged
+	}
+
+	inspected := []string{}
+	for idx, arg := range args {
+		if arg == nil {
+			inspected = append(inspected, method.Method.Parameters[idx].Default.String())
+			continue
+		}
+		inspected = append(inspected, arg.Inspect())
+	}
+	return inspected
+}
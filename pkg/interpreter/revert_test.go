@@ -0,0 +1,247 @@
+package interpreter
+
+import (
+	"github.com/Stremax-Team/stremax-lang/pkg/blockchain"
+	"strings"
+	"testing"
+)
+
+// vault is a contract whose withdrawals revert with a custom error
+const vault = `error InsufficientBalance(needed: Int, available: Int)
+
+contract Vault {
+	state { balances: Map<Address, Int>, log: Array<String> }
+
+	function deposit(amount: Int) {
+		balances[msg.sender] += amount;
+		log.push("deposit");
+	}
+
+	function withdraw(amount: Int): Int {
+		log.push("withdraw");
+		let balance = balances[msg.sender];
+		if (amount > balance) {
+			revert InsufficientBalance(amount, balance);
+		}
+		balances[msg.sender] -= amount;
+		return balances[msg.sender];
+	}
+
+	function check(ok: Bool) { require(ok, "not ok"); }
+	function invariant(ok: Bool) { assert(ok, "invariant broken"); }
+	function overflow(): Uint8 { let x: Uint8 = 255; return x + 1; }
+	function balance(): Int { return balances[msg.sender]; }
+	function history(): Array<String> { return log; }
+}
+
+let v = Vault();
+v.deposit(10);
+`
+
+func TestErrorValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"error Paused();\nPaused();", "Paused()"},
+		{"error Low(needed: Int, available: Int)\nLow(3, 1);", "Low(needed: 3, available: 1)"},
+		{"error Low(needed: Int, available: Int)\nLow(3, 1).available;", "1"},
+		{"error Low(needed: Uint8)\ntypeof(Low(3).needed);", "Uint8"},
+		{"error Low(needed: Int)\ntypeof(Low(3));", "Low"},
+		{"Error(\"boom\").message;", "boom"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try v.withdraw(4) as left { left; } catch (e) { -1; }", "6"},
+		{"try v.withdraw(25) { 0; } catch InsufficientBalance(needed, available) { needed - available; }", "15"},
+		{"try v.withdraw(25) { 0; } catch (e) { e; }", "InsufficientBalance(needed: 25, available: 10)"},
+		{"try v.withdraw(25) { 0; } catch (e) { e.available; }", "10"},
+		{"try v.withdraw(25) { 0; } catch { \"caught\"; }", "caught"},
+		// require and revert with a message fail with an Error
+		{"try v.check(false) { 0; } catch Error(message) { message; }", "not ok"},
+		// assert and other runtime failures fail with a Panic
+		{"try v.invariant(false) { 0; } catch Error(message) { \"error\"; } catch Panic(message) { message; }", "invariant broken"},
+		{"try v.overflow() { 0; } catch Panic(message) { message; }", "Integer overflow: 255 + 1 (Uint8)"},
+		// Clauses are tried in order
+		{"try v.withdraw(25) { 0; } catch Error(m) { 1; } catch InsufficientBalance(n, a) { 2; } catch (e) { 3; }", "2"},
+		// Deployments can be guarded too
+		{"contract C { constructor(n: Int) { require(n > 0, \"n must be positive\"); } }\ntry C(0) { 0; } catch Error(m) { m; }", "n must be positive"},
+		{"try Vault() as other { other.balance(); } catch (e) { -1; }", "0"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, vault+tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestFailedCallsRollBack(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// The failed withdrawal's log entry is rolled back
+		{"try v.withdraw(25) { } catch (e) { }\nv.history();", "[deposit]"},
+		{"try v.withdraw(4) { } catch (e) { }\nv.history();", "[deposit, withdraw]"},
+		{
+			// A contract catching the failure of another keeps its own changes
+			`contract Bank {
+				state { vault: Vault?, failures: Int }
+				function fund(amount: Int) { vault = Vault(); vault?.deposit(amount); }
+				function take(amount: Int): Int {
+					try vault?.withdraw(amount) as left { return left; } catch InsufficientBalance(needed, available) { failures += 1; }
+					return -1;
+				}
+				function stats(): String { return toString(failures) + " " + toString(vault?.history()); }
+			}
+			let b = Bank();
+			b.fund(5);
+			b.take(50);
+			b.take(2);
+			b.stats();`,
+			"1 [deposit, withdraw]",
+		},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, vault+tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+
+	// Contracts deployed by a failed call are forgotten
+	i := New(vault + `contract Factory {
+		function make() { let made = Vault(); revert "no"; }
+	}
+	let f = Factory();
+	try f.make() { } catch (e) { }`)
+	if err := runUnchecked(i); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(i.bc.Contracts) != 2 {
+		t.Errorf("expected the Vault and Factory contracts only, got %d contracts", len(i.bc.Contracts))
+	}
+}
+
+func TestRevertedCallReceipts(t *testing.T) {
+	i := New(vault + "try v.withdraw(25) { } catch (e) { }\nv.withdraw(4);")
+	if err := runUnchecked(i); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var reverted, succeeded []*blockchain.Receipt
+	for _, receipt := range i.Blockchain().Receipts {
+		if receipt.Status == blockchain.StatusReverted {
+			reverted = append(reverted, receipt)
+		} else {
+			succeeded = append(succeeded, receipt)
+		}
+	}
+
+	// The deposit and the second withdrawal succeed
+	if len(reverted) != 1 || len(succeeded) != 2 {
+		t.Fatalf("expected 1 reverted and 2 succeeded receipts, got %d and %d", len(reverted), len(succeeded))
+	}
+
+	failure, err := reverted[0].Failure()
+	if err != nil {
+		t.Fatalf("cannot decode revert data: %v", err)
+	}
+	if failure.String() != "InsufficientBalance(needed: 25, available: 10)" {
+		t.Errorf("wrong failure. got=%s", failure)
+	}
+	if needed, _ := failure.Arg("needed"); needed != "25" {
+		t.Errorf("wrong needed argument. got=%q", needed)
+	}
+	if failure.Args[1].Type != "Int" {
+		t.Errorf("wrong argument type. got=%q", failure.Args[1].Type)
+	}
+
+	// The reverted call's transaction is still recorded
+	if _, ok := i.Blockchain().GetReceipt(reverted[0].TxHash); !ok {
+		t.Errorf("receipt not found by transaction hash")
+	}
+	found := false
+	for _, tx := range i.Blockchain().PendingTransactions {
+		found = found || tx.Hash == reverted[0].TxHash
+	}
+	if !found {
+		t.Errorf("reverted call has no transaction")
+	}
+}
+
+func TestNamedArgumentCallReceipts(t *testing.T) {
+	input := `contract C {
+		function f(a: Int = 1, b: Int = 2): Int { return a + b; }
+	}
+	let c = C();
+	[c.f(b: 5), c.f(), c.f(b: 3, a: 4)];`
+
+	i := New(input)
+	if err := i.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := []string{}
+	for _, tx := range i.Blockchain().PendingTransactions {
+		// The deployment of the contract is recorded with its source
+		if strings.HasPrefix(string(tx.Data), "f(") {
+			calls = append(calls, string(tx.Data))
+		}
+	}
+	expected := []string{"f(1, 5)", "f(1, 2)", "f(4, 3)"}
+	if strings.Join(calls, " ") != strings.Join(expected, " ") {
+		t.Errorf("wrong recorded calls. got=%v, want=%v", calls, expected)
+	}
+}
+
+func TestErrorHandlingRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"v.withdraw(25);", "RevertError: InsufficientBalance(needed: 25, available: 10) at :15:4"},
+		{"v.check(false);", "RuntimeError: not ok at :21:29"},
+		{"v.invariant(false);", "AssertionError: invariant broken at :22:33"},
+		{"assert(1 > 2);", "AssertionError: Assertion failed"},
+		{"revert \"closed\";", "RevertError: closed"},
+		{"revert 5;", "Cannot revert with Int; use an error such as Unauthorized() or a message"},
+		{"InsufficientBalance(1);", "Wrong number of arguments to InsufficientBalance: expected 2, got 1"},
+		{"InsufficientBalance(1, \"a\");", "Type mismatch: expected Int, got STRING"},
+		{"InsufficientBalance(1, 2).missing;", "Error InsufficientBalance has no field missing"},
+		{"error E(a: Int, a: Int)", "Duplicate field a in error E"},
+		// Failures no clause matches are passed on
+		{"try v.withdraw(25) { } catch Error(m) { }", "RevertError: InsufficientBalance(needed: 25, available: 10)"},
+		{"try v.withdraw(25) { } catch InsufficientBalance(n) { }", "Wrong number of parameters to catch InsufficientBalance: expected 2, got 1"},
+		{"try v.withdraw(25) { } catch Vault(n) { }", "Vault is not an error type"},
+		{"let f = function() { return 1; };\ntry f() { } catch (e) { }", "try requires a contract call or deployment, got a call of Function"},
+		// Failures evaluating the arguments are not caught
+		{"try v.withdraw(1 / 0) { } catch (e) { }", "Division by zero"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(vault + tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
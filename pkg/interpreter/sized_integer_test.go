@@ -0,0 +1,102 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSizedIntegerArithmetic(t *testing.T) {
+	tests := []struct {
+		input        string
+		expected     int64
+		expectedKind string
+	}{
+		{"let x: Uint8 = 200; x + 55;", 255, "Uint8"},
+		{"let x: Uint8 = 200; 55 + x;", 255, "Uint8"},
+		{"let x: Int8 = -100; x - 28;", -128, "Int8"},
+		{"let x: Uint8 = 5; ~x;", 250, "Uint8"},
+		{"let x: Int16 = 5; -x;", -5, "Int16"},
+		{"let x: Uint8 = 2; 10 ** x;", 100, ""},
+		{"let x: Uint8 = 2; x ** 7;", 128, "Uint8"},
+		{"let x: Uint8 = 1; x << 7;", 128, "Uint8"},
+		{"let x: Uint8 = 250; x++; x;", 251, "Uint8"},
+		{"let x: Uint16 = 1000; x += 1000; x;", 2000, "Uint16"},
+		{"let x: Int = Uint8(7); x;", 7, ""},
+		{"Uint16(Uint8(255)) + 1;", 256, "Uint16"},
+		{"Int(Uint8(3)) * 100;", 300, ""},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		testIntegerObject(t, result, tt.expected)
+
+		integer, ok := result.(*Integer)
+		if !ok {
+			continue
+		}
+		kind := ""
+		if integer.Kind != nil {
+			kind = integer.Kind.Name
+		}
+		if kind != tt.expectedKind {
+			t.Errorf("wrong kind for %q. got=%q, want=%q", tt.input, kind, tt.expectedKind)
+		}
+	}
+}
+
+func TestWrappingAndSaturatingBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"wrapping_add(Uint8(250), 10);", 4},
+		{"wrapping_sub(Uint8(3), 5);", 254},
+		{"wrapping_mul(Uint8(16), 17);", 16},
+		{"wrapping_add(Int8(127), 1);", -128},
+		{"wrapping_sub(Int8(-128), 1);", 127},
+		{"saturating_add(Uint8(250), 10);", 255},
+		{"saturating_sub(Uint8(3), 5);", 0},
+		{"saturating_sub(Int8(-120), 100);", -128},
+		{"saturating_mul(Int16(300), 300);", 32767},
+		{"saturating_add(Uint8(1), 2);", 3},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
+
+func TestSizedIntegerErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let x: Uint8 = 256;", "Integer literal 256 out of range for Uint8 at :1:16"},
+		{"let x: Uint8 = -1;", "Integer literal -1 out of range for Uint8 at :1:16"},
+		{"let x: Uint8 = 200; x + 56;", "Integer overflow: 200 + 56 (Uint8)"},
+		{"let x: Uint8 = 0; x - 1;", "Integer overflow: 0 - 1 (Uint8)"},
+		{"let x: Int8 = -128; -x;", "Integer overflow"},
+		{"let x: Uint8 = 255; x++;", "Integer overflow"},
+		{"let x: Uint8 = 1; x = 300;", "Value 300 out of range for Uint8"},
+		{"let x: Uint8 = 1; let y: Uint16 = 2; x + y;", "Type mismatch: Uint8 + Uint16 (use an explicit cast)"},
+		{"let y: Uint16 = 2; let x: Uint8 = y;", "Cannot implicitly convert Uint16 to Uint8"},
+		{"let x: Uint8 = \"five\";", "Type mismatch: expected Uint8, got STRING"},
+		{"Uint8(256);", "Cannot convert 256 to Uint8: value out of range"},
+		{"Uint8(Int16(-1));", "Cannot convert -1 to Uint8: value out of range"},
+		{"Uint8(true);", "Cannot convert BOOLEAN to Uint8"},
+		{"wrapping_add(Uint8(1), Uint16(1));", "Type mismatch"},
+		{"function f(x: Uint8) { return x; } f(1000);", "Value 1000 out of range for Uint8"},
+		{"function f(x: Int): Uint8 { return x * 2; } f(200);", "Value 400 out of range for Uint8"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package interpreter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStrictMode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"pragma strict;\nlet n = 5;\n\"n = \" + toString(n);", "n = 5"},
+		{"pragma strict;\nlet n = 5;\n\"n = ${n}\";", "n = 5"},
+		{"pragma strict;\nparseInt(\"041\") + 1;", "42"},
+		{"pragma strict;\nif (1 < 2) { \"yes\"; } else { \"no\"; }", "yes"},
+		{"pragma strict;\nlet i = 0;\nwhile (i < 3) { i += 1; }\ni;", "3"},
+		// Outside strict mode values are still converted
+		{"\"n = \" + 5;", "n = 5"},
+		{"if (1) { \"yes\"; } else { \"no\"; }", "yes"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestStrictModeRuntimeErrors(t *testing.T) {
+	// Values of unannotated parameters are only known when the program runs
+	f := "pragma strict;\nlet f = function(v) { "
+
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{f + "\"v = \" + v; };\nf(5);", "TypeError: Type mismatch: String + Int (strict mode does not convert values to strings; use toString or interpolation) at :2:30"},
+		{f + "v + \"!\"; };\nf(true);", "TypeError: Type mismatch: Bool + String (strict mode does not convert values to strings; use toString or interpolation)"},
+		{f + "let s = \"a\"; s += v; };\nf(1);", "Type mismatch: String + Int"},
+		{f + "if (v) { 1; } };\nf(1);", "TypeError: Condition of if must be a Bool in strict mode, got Int at :2:23"},
+		{f + "while (v) { } };\nf(\"a\");", "Condition of while must be a Bool in strict mode, got String"},
+		{f + "for (let i = 0; v; i++) { } };\nf(1);", "Condition of for must be a Bool in strict mode, got Int"},
+		{f + "require(v, \"m\"); };\nf(null);", "Condition of require must be a Bool in strict mode, got Null"},
+		{f + "assert(v); };\nf([1]);", "Condition of assert must be a Bool in strict mode, got Array"},
+		{f + "!v; };\nf(0);", "TypeError: Operand of ! must be a Bool in strict mode, got Int at :2:23"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+
+	// Strict mode can also be chosen by the embedder
+	i := New("let f = function(v) { \"v = \" + v; };\nf(5);")
+	i.SetStrict(true)
+	if err := runUnchecked(i); err == nil || !strings.Contains(err.Error(), "strict mode does not convert values to strings") {
+		t.Errorf("expected a strict mode error, got %v", err)
+	}
+}
+
+func TestStrictModules(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"strict.sx":   "pragma strict;\nfunction label(n: Int): String { return \"#\" + toString(n); }",
+		"loose.sx":    "function label(n: Int): String { return \"#\" + n; }",
+		"describe.sx": "pragma strict;\nlet describe = function(v) { return \"v = \" + v; };",
+		// A strict module does not make its importer strict
+		"main.sx": "import \"strict.sx\"\nstrict.label(1) + 2;",
+		// The modules of a strict program are checked strictly
+		"strict_main.sx": "pragma strict;\nimport \"loose.sx\"\nloose.label(1);",
+		// Functions of a strict module stay strict when called from elsewhere
+		"describe_main.sx": "import \"describe.sx\"\ndescribe.describe(5);",
+	})
+
+	result, err := evalFile(t, filepath.Join(dir, "main.sx"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Inspect() != "#12" {
+		t.Errorf("wrong result. got=%s, want=#12", result.Inspect())
+	}
+
+	_, err = evalFile(t, filepath.Join(dir, "strict_main.sx"))
+	if err == nil || !strings.Contains(err.Error(), "Type mismatch: String + Int (strict mode") {
+		t.Errorf("expected a strict mode error in the imported module, got %v", err)
+	}
+
+	_, err = evalFile(t, filepath.Join(dir, "describe_main.sx"))
+	if err == nil || !strings.Contains(err.Error(), "strict mode does not convert values to strings") {
+		t.Errorf("expected a strict mode error in the imported function, got %v", err)
+	}
+}
@@ -80,9 +80,11 @@ func TestStringConcatenationErrors(t *testing.T) {
 		expectedContains string
 	}{
 		// Using operators other than + for strings
-		{`"Hello" - "World";`, "Unknown operator: -"},
-		{`"Hello" * "World";`, "Unknown operator: *"},
-		{`"Hello" / "World";`, "Unknown operator: /"},
+		{`"Hello" - "World";`, "TypeError: Type mismatch: String - String at :1:9"},
+		{`"Hello" * "World";`, "TypeError: Type mismatch: String * String at :1:9"},
+		{`"Hello" / "World";`, "TypeError: Type mismatch: String / String at :1:9"},
+		// Operands of unknown type are checked when the program runs
+		{"let sub = function(a, b) { a - b; };\nsub(\"Hello\", \"World\");", "Unknown operator: -"},
 	}
 
 	for i, tt := range tests {
@@ -99,4 +101,25 @@ func TestStringConcatenationErrors(t *testing.T) {
 				i, tt.expectedContains, err.Error())
 		}
 	}
-} 
\ No newline at end of file
+} 
+
+// TestLeadingZeroAddition guards against integers whose decimal form starts
+// with 0 being concatenated as strings instead of added
+func TestLeadingZeroAddition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0 + 5;", 5},
+		{"5 + 0;", 5},
+		{"0 + 0;", 0},
+		{"007 + 1;", 8},
+		{"let a = 0;\nlet b = 5;\na + b;", 5},
+		{"let balance = 0;\nbalance += 5;\nbalance;", 5},
+		{"pragma strict;\n0 + 5;", 5},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(t, tt.input), tt.expected)
+	}
+}
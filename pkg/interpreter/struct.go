@@ -0,0 +1,213 @@
+package interpreter
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"strings"
+)
+
+// StructType represents a struct declaration. Struct literals name it to
+// create instances.
+type StructType struct {
+	Name   string
+	Fields []*parser.ParameterStatement
+}
+
+// Type returns the type of the StructType object
+func (st *StructType) Type() string { return "STRUCT_TYPE" }
+
+// Inspect returns a string representation of the StructType object
+func (st *StructType) Inspect() string { return fmt.Sprintf("struct %s", st.Name) }
+
+// field returns the declaration of the named field
+func (st *StructType) field(name string) (*parser.ParameterStatement, bool) {
+	for _, f := range st.Fields {
+		if f.Name.Value == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Struct represents an instance of a struct type. Like hashes, structs are
+// reference values: copies share their fields.
+type Struct struct {
+	Definition *StructType
+	Fields     map[string]Object
+}
+
+// Type returns the type of the Struct object
+func (s *Struct) Type() string { return "STRUCT" }
+
+// Inspect returns a string representation of the Struct object
+func (s *Struct) Inspect() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, f := range s.Definition.Fields {
+		fields = append(fields, fmt.Sprintf("%s: %s", f.Name.Value, s.Fields[f.Name.Value].Inspect()))
+	}
+
+	out.WriteString(s.Definition.Name)
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// evalStructStatement evaluates a struct declaration, binding its name to
+// the struct type
+func (i *Interpreter) evalStructStatement(stmt *parser.StructStatement) (Object, error) {
+	seen := make(map[string]bool)
+	for _, f := range stmt.Fields {
+		if seen[f.Name.Value] {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Duplicate field %s in struct %s", f.Name.Value, stmt.Name.Value),
+				f.Token.Line, f.Token.Column, "")
+		}
+		seen[f.Name.Value] = true
+
+		// A struct that contains itself, directly or through other structs,
+		// would have an infinitely large zero value
+		if i.containsStruct(f.Type, stmt.Name.Value, make(map[string]bool)) {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Recursive struct type: %s.%s", stmt.Name.Value, f.Name.Value),
+				f.Token.Line, f.Token.Column, "")
+		}
+	}
+
+	structType := &StructType{Name: stmt.Name.Value, Fields: stmt.Fields}
+	i.env.Set(structType.Name, structType)
+	return structType, nil
+}
+
+// containsStruct reports whether the zero value of a type holds a value of
+// the named struct. Optional fields start as null and arrays and maps start
+// empty, so only the fields of structs and the elements of tuples are
+// followed.
+func (i *Interpreter) containsStruct(typ *parser.TypeExpression, name string, visited map[string]bool) bool {
+	if typ == nil || typ.Optional {
+		return false
+	}
+
+	if typ.Type == "Tuple" {
+		for _, el := range typ.ElementTypes {
+			if i.containsStruct(el, name, visited) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if typ.Type == name {
+		return true
+	}
+	if visited[typ.Type] {
+		return false
+	}
+	visited[typ.Type] = true
+
+	definition, ok := i.declaredType(typ.Type).(*StructType)
+	if !ok {
+		return false
+	}
+	for _, f := range definition.Fields {
+		if i.containsStruct(f.Type, name, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// evalStructLiteral evaluates a struct literal. Fields that are not given
+// hold the zero value of their declared type.
+func (i *Interpreter) evalStructLiteral(lit *parser.StructLiteral) (Object, error) {
+	definition, err := i.lookupStructType(lit.Name.Value, lit.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]Object)
+	for idx, name := range lit.Fields {
+		field, ok := definition.field(name.Value)
+		if !ok {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Struct %s has no field %s", definition.Name, name.Value),
+				name.Token.Line, name.Token.Column, "")
+		}
+		if _, ok := values[name.Value]; ok {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Duplicate field %s in %s literal", name.Value, definition.Name),
+				name.Token.Line, name.Token.Column, "")
+		}
+
+		val, err := i.evalExpression(lit.Values[idx])
+		if err != nil {
+			return nil, err
+		}
+		val, err = i.coerceToType(val, field.Type, name.Token)
+		if err != nil {
+			return nil, err
+		}
+		values[name.Value] = val
+	}
+
+	for _, f := range definition.Fields {
+		if _, ok := values[f.Name.Value]; !ok {
+			values[f.Name.Value] = i.zeroValue(f.Type)
+		}
+	}
+
+	return &Struct{Definition: definition, Fields: values}, nil
+}
+
+// lookupStructType resolves a name to the struct type it is bound to
+func (i *Interpreter) lookupStructType(name string, token parser.Token) (*StructType, error) {
+	obj, ok := i.env.Get(name)
+	if !ok {
+		return nil, errors.NewReferenceError(
+			fmt.Sprintf("Unknown struct type: %s", name),
+			token.Line, token.Column, "")
+	}
+
+	definition, ok := obj.(*StructType)
+	if !ok {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("%s is not a struct type", name),
+			token.Line, token.Column, "")
+	}
+
+	return definition, nil
+}
+
+// evalStructField reads a field of a struct
+func (i *Interpreter) evalStructField(s *Struct, name string, token parser.Token) (Object, error) {
+	val, ok := s.Fields[name]
+	if !ok {
+		return nil, errors.NewReferenceError(
+			fmt.Sprintf("Struct %s has no field %s", s.Definition.Name, name),
+			token.Line, token.Column, "")
+	}
+	return val, nil
+}
+
+// setStructField writes a field of a struct, checking the declared field type
+func (i *Interpreter) setStructField(s *Struct, name string, val Object, token parser.Token) error {
+	field, ok := s.Definition.field(name)
+	if !ok {
+		return errors.NewReferenceError(
+			fmt.Sprintf("Struct %s has no field %s", s.Definition.Name, name),
+			token.Line, token.Column, "")
+	}
+
+	val, err := i.coerceToType(val, field.Type, token)
+	if err != nil {
+		return err
+	}
+
+	s.Fields[name] = val
+	return nil
+}
@@ -0,0 +1,168 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStructLiteralsAndFields(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`struct Point { x: Int, y: Int } let p = Point { x: 1, y: 2 }; p.x + p.y;`, "3"},
+		{`struct Point { x: Int, y: Int } let p = Point { y: 5 }; p.x;`, "0"},
+		{`struct Point { x: Int, y: Int } let p = Point { x: 1, y: 2 }; p.x = 10; p.x;`, "10"},
+		{`struct Point { x: Int, y: Int } let p = Point { x: 1, y: 2 }; p.y += 40; p.y;`, "42"},
+		{`struct Point { x: Int, y: Int } Point { x: 1, y: 2 };`, "Point { x: 1, y: 2 }"},
+		{`struct Named { name: String; active: Bool } Named {};`, "Named { name: , active: false }"},
+		{
+			// Structs are reference values
+			`struct Counter { n: Int } let a = Counter { n: 1 }; let b = a; b.n = 5; a.n;`,
+			"5",
+		},
+		{
+			// Nested structs get zero values and support chained access
+			`struct Inner { v: Uint8 } struct Outer { inner: Inner } let o = Outer {}; o.inner.v = 7; o.inner.v;`,
+			"7",
+		},
+		{
+			`struct Proposal { description: String, votes: Int }
+			let proposals = {};
+			proposals[0] = Proposal { description: "Fund it", votes: 0 };
+			proposals[0].votes++;
+			proposals[0].votes++;
+			proposals[0].description + ": " + proposals[0].votes;`,
+			"Fund it: 2",
+		},
+		{
+			`struct Point { x: Int, y: Int }
+			function norm(p: Point): Int { return p.x * p.x + p.y * p.y; }
+			norm(Point { x: 3, y: 4 });`,
+			"25",
+		},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestStructsInContractState(t *testing.T) {
+	input := `
+	struct Proposal { description: String, votes: Int }
+
+	contract Voting {
+		state {
+			proposals: Map<Int, Proposal>
+			latest: Proposal
+			count: Int
+		}
+
+		function add(description: String): Int {
+			let id = count;
+			proposals[id] = Proposal { description: description, votes: 0 };
+			latest = proposals[id];
+			count += 1;
+			return id;
+		}
+
+		function vote(id: Int) {
+			proposals[id].votes += 1;
+		}
+
+		function votesFor(id: Int): Int {
+			return proposals[id].votes;
+		}
+	}
+
+	let v = Voting();
+	v.add("a");
+	v.add("b");
+	v.vote(1);
+	v.vote(1);
+	v.vote(0);
+	[v.votesFor(0), v.votesFor(1), v.latest.description];
+	`
+
+	result := testEval(t, input)
+	if result.Inspect() != "[1, 2, b]" {
+		t.Errorf("wrong result. got=%s, want=%s", result.Inspect(), "[1, 2, b]")
+	}
+}
+
+func TestStructDeclaredInContract(t *testing.T) {
+	input := `
+	contract Registry {
+		struct Entry { owner: String, size: Uint8 }
+
+		state {
+			first: Entry
+		}
+
+		function size(): Uint8 {
+			return first.size;
+		}
+	}
+
+	let r = Registry();
+	r.size();
+	`
+
+	testIntegerObject(t, testEval(t, input), 0)
+}
+
+func TestOptionalRecursiveStruct(t *testing.T) {
+	// Optional fields start as null, so a struct may refer to itself
+	// through them
+	input := `
+	struct Node { value: Int, next: Node? }
+	let list = Node { value: 1, next: Node { value: 2 } };
+	[list.next?.value, list.next?.next];
+	`
+
+	i := New(input)
+	if err := i.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := testEval(t, input)
+	if result == nil || result.Inspect() != "[2, null]" {
+		t.Errorf("wrong result. got=%v, want=[2, null]", result)
+	}
+}
+
+func TestStructErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`struct P { x: Int } P { y: 1 };`, "Struct P has no field y"},
+		{`struct P { x: Int } P { x: 1, x: 2 };`, "Duplicate field x in P literal"},
+		{`struct P { x: Int, x: Int }`, "Duplicate field x in struct P"},
+		{`struct P { next: P }`, "Recursive struct type: P.next"},
+		{"struct A { b: B }\nstruct B { a: A }\nlet x = A {};", "Recursive struct type: B.a at :2:12"},
+		{"struct A { b: B }\nstruct B { c: C }\nstruct C { pair: (Int, A) }", "Recursive struct type: C.pair at :3:12"},
+		{`struct P { x: Uint8 } P { x: 300 };`, "Value 300 out of range for Uint8"},
+		{`struct P { x: Int } P { x: "one" };`, "Type mismatch: expected Int, got STRING"},
+		{`struct P { x: Int } let p = P {}; p.y;`, "Struct P has no field y"},
+		{`struct P { x: Int } let p = P {}; p.y = 1;`, "Struct P has no field y"},
+		{`struct P { x: Int } let p = P {}; p.x = true;`, "Type mismatch: expected Int, got BOOLEAN"},
+		{`Missing { x: 1 };`, "Unknown struct type: Missing"},
+		{`let NotAStruct = 1; NotAStruct { x: 1 };`, "NotAStruct is not a struct type"},
+		{`struct P { x: Int } struct Q { x: Int } let p: P = Q { x: 1 };`, "Type mismatch: expected P, got STRUCT"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"strings"
+)
+
+// Tuple represents a fixed-size group of values, such as the pair a
+// function declared with a (Int, Int) return type returns. Tuples are
+// immutable: their elements are read by index or by destructuring.
+type Tuple struct {
+	Elements []Object
+}
+
+// Type returns the type of the Tuple object
+func (t *Tuple) Type() string { return "TUPLE" }
+
+// Inspect returns a string representation of the Tuple object
+func (t *Tuple) Inspect() string {
+	elements := []string{}
+	for _, el := range t.Elements {
+		elements = append(elements, el.Inspect())
+	}
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
+// evalTupleLiteral evaluates a tuple literal
+func (i *Interpreter) evalTupleLiteral(node *parser.TupleLiteral) (Object, error) {
+	elements, err := i.evalExpressions(node.Elements)
+	if err != nil {
+		return nil, err
+	}
+	return &Tuple{Elements: elements}, nil
+}
+
+// evalTupleIndexExpression reads an element of a tuple
+func (i *Interpreter) evalTupleIndexExpression(tuple *Tuple, index *Integer, token parser.Token) (Object, error) {
+	idx, ok := arrayIndex(index, len(tuple.Elements))
+	if !ok {
+		return nil, errors.NewRuntimeError(
+			fmt.Sprintf("tuple index out of range: %s (length %d)", index.Value, len(tuple.Elements)),
+			token.Line, token.Column, "")
+	}
+	return tuple.Elements[idx], nil
+}
+
+// coerceToTuple checks a value against a tuple type, converting each
+// element to its declared type
+func (i *Interpreter) coerceToTuple(val Object, typ *parser.TypeExpression, token parser.Token) (Object, error) {
+	tuple, ok := val.(*Tuple)
+	if !ok {
+		return nil, typeMismatchError(typ, val, token)
+	}
+	if len(tuple.Elements) != len(typ.ElementTypes) {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Type mismatch: expected %s, got a tuple of %d elements", typ.String(), len(tuple.Elements)),
+			token.Line, token.Column, "")
+	}
+
+	elements := make([]Object, len(tuple.Elements))
+	for idx, el := range tuple.Elements {
+		coerced, err := i.coerceToType(el, typ.ElementTypes[idx], token)
+		if err != nil {
+			return nil, err
+		}
+		elements[idx] = coerced
+	}
+
+	return &Tuple{Elements: elements}, nil
+}
+
+// evalDestructuring binds the names of a destructuring let or const to the
+// elements or fields of its value. The name _ discards a value.
+func (i *Interpreter) evalDestructuring(stmt *parser.LetStatement, val Object) (Object, error) {
+	values, err := i.destructure(stmt.Pattern, val)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range stmt.Pattern.Names {
+		if name.Value == "_" {
+			continue
+		}
+		if seen[name.Value] {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Duplicate name %s in %s", name.Value, stmt.Pattern.String()),
+				name.Token.Line, name.Token.Column, "")
+		}
+		seen[name.Value] = true
+
+		if i.env.IsConstant(name.Value) {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Cannot redeclare constant %s", name.Value),
+				name.Token.Line, name.Token.Column, "")
+		}
+	}
+
+	for idx, name := range stmt.Pattern.Names {
+		switch {
+		case name.Value == "_":
+		case stmt.Constant:
+			i.env.SetConstant(name.Value, values[idx], nil)
+		default:
+			i.env.Set(name.Value, values[idx])
+		}
+	}
+
+	return val, nil
+}
+
+// destructure returns the values a pattern binds: the elements of a tuple
+// or an array, which must have one element per name, or the named fields
+// of a struct
+func (i *Interpreter) destructure(pattern *parser.DestructuringPattern, val Object) ([]Object, error) {
+	token := pattern.Token
+
+	var elements []Object
+	switch {
+	case pattern.Token.Literal == "(":
+		tuple, ok := val.(*Tuple)
+		if !ok {
+			break
+		}
+		if len(tuple.Elements) != len(pattern.Names) {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Cannot destructure a tuple of %d elements as %s", len(tuple.Elements), pattern.String()),
+				token.Line, token.Column, "")
+		}
+		elements = tuple.Elements
+	case pattern.Token.Literal == "[":
+		array, ok := val.(*Array)
+		if !ok {
+			break
+		}
+		if len(array.Elements) != len(pattern.Names) {
+			return nil, errors.NewRuntimeError(
+				fmt.Sprintf("Cannot destructure an array of %d elements as %s", len(array.Elements), pattern.String()),
+				token.Line, token.Column, "")
+		}
+		elements = array.Elements
+	default:
+		s, ok := val.(*Struct)
+		if !ok {
+			break
+		}
+		for _, field := range pattern.Fields {
+			fieldVal, err := i.evalStructField(s, field.Value, field.Token)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, fieldVal)
+		}
+	}
+
+	if elements == nil {
+		return nil, errors.NewTypeError(
+			fmt.Sprintf("Cannot destructure %s as %s", typeName(val), pattern.String()),
+			token.Line, token.Column, "")
+	}
+
+	return elements, nil
+}
@@ -0,0 +1,87 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTuples(t *testing.T) {
+	divmod := "function divmod(a: Int, b: Int): (Int, Int) { return (a / b, a % b); }\n"
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(1, \"a\", true);", "(1, a, true)"},
+		{divmod + "divmod(17, 5);", "(3, 2)"},
+		{divmod + "divmod(17, 5)[1];", "2"},
+		{divmod + "len(divmod(17, 5));", "2"},
+		{divmod + "typeof(divmod(17, 5));", "Tuple"},
+		{"let t: (Uint8, String) = (1, \"a\");\ntypeof(t[0]);", "Uint8"},
+		{"let t: (Int, String)? = null;\nt;", "null"},
+		{"contract C { state { pair: (Int, Bool) } function get(): (Int, Bool) { return pair; } }\nC().get();", "(0, false)"},
+		{"function swap<A, B>(p: (A, B)): (B, A) { return (p[1], p[0]); }\nswap((1, \"a\"));", "(a, 1)"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let (q, r) = (3, 2);\nq * 10 + r;", "32"},
+		{"let [x, y, z] = [1, 2, 3];\nx + y + z;", "6"},
+		{"struct P { x: Int, y: Int }\nlet { x, y } = P { x: 3, y: 4 };\nx * y;", "12"},
+		{"struct P { x: Int, y: Int }\nlet { y: height } = P { x: 3, y: 4 };\nheight;", "4"},
+		// _ discards a value
+		{"let (_, second) = (1, 2);\nsecond;", "2"},
+		{"let [_, b, _] = [1, 2, 3];\nb;", "2"},
+		{"let count = 0;\nlet _ = (function() { count += 1; return count; })();\ncount;", "1"},
+		{"const (a, b): (Int, String) = (1, \"s\");\nb;", "s"},
+		{"let total = 0;\nfor (let (i, n) = (0, 3); i < n; i++) { total += i; }\ntotal;", "3"},
+	}
+
+	for _, tt := range tests {
+		result := testEval(t, tt.input)
+		if result == nil || result.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%v, want=%s", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTupleRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"let (a, b) = (1, 2, 3);", "Cannot destructure a tuple of 3 elements as (a, b)"},
+		{"let [a, b] = [1];", "Cannot destructure an array of 1 elements as [a, b]"},
+		{"let (a, b) = [1, 2];", "Cannot destructure Array as (a, b)"},
+		{"struct P { x: Int }\nlet { z } = P { x: 1 };", "Struct P has no field z"},
+		{"let (a, a) = (1, 2);", "Duplicate name a in (a, a)"},
+		{"const a = 1;\nlet (a, b) = (1, 2);", "Cannot redeclare constant a"},
+		{"let t = (1, 2);\nt[0] = 5;", "Cannot assign to an element of a tuple; tuples are immutable"},
+		{"let t = (1, 2);\nt[2];", "tuple index out of range: 2 (length 2)"},
+		{"let t: (Int, String) = (1, 2);", "Type mismatch: expected String, got INTEGER"},
+		{"let t: (Int, Int) = (1, 2, 3);", "Type mismatch: expected (Int, Int), got a tuple of 3 elements"},
+		{"let _ = 1;\n_;", "Identifier not found: _"},
+	}
+
+	for _, tt := range tests {
+		err := runUnchecked(New(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %q", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("expected error containing %q for %q, got %q", tt.expectedError, tt.input, err.Error())
+		}
+	}
+}
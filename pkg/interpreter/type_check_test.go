@@ -0,0 +1,53 @@
+package interpreter
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTypeErrorsReportedBeforeRunning(t *testing.T) {
+	input := `
+	contract Counter {
+		state { count: Int }
+		function add(n: Int): Int { count += n; return count; }
+	}
+
+	let c = Counter();
+	c.add(1);
+	c.add("two");
+	`
+
+	var out bytes.Buffer
+	i := New(input)
+	i.SetOutput(&out)
+	err := i.Run()
+
+	expected := "TypeError: Type mismatch: argument 1 of add: expected Int, got String at :9:8"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("wrong error. expected=%q, got=%v", expected, err)
+	}
+	if strings.Count(out.String(), expected) != 1 {
+		t.Errorf("type error not printed once. got=%q", out.String())
+	}
+	// The contract must not have been deployed
+	if len(i.bc.Contracts) != 0 {
+		t.Errorf("expected no contracts deployed, got %d", len(i.bc.Contracts))
+	}
+}
+
+func TestTypeErrorsInModules(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"lib.sx":  `let limit: Int = "ten";`,
+		"main.sx": `import "lib.sx"`,
+	})
+
+	_, err := evalFile(t, filepath.Join(dir, "main.sx"))
+	if err == nil || !strings.Contains(err.Error(), "Type mismatch: expected Int, got String") {
+		t.Fatalf("expected module type error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "lib.sx") {
+		t.Errorf("expected error to name the module file, got %v", err)
+	}
+}
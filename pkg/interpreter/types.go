@@ -0,0 +1,365 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/blockchain"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"github.com/Stremax-Team/stremax-lang/pkg/parser"
+	"math/big"
+)
+
+// typeNames maps the built-in type names used in declarations to the
+// runtime type of their values
+var typeNames = map[string]string{
+	"Int":     "INTEGER",
+	"String":  "STRING",
+	"Bool":    "BOOLEAN",
+	"Address": "ADDRESS",
+	"Map":     "HASH",
+	"Array":   "ARRAY",
+}
+
+// zeroValue returns the value a binding of the given type holds before it
+// is assigned. Types without a zero value produce null.
+func (i *Interpreter) zeroValue(typ *parser.TypeExpression) Object {
+	if typ == nil || typ.Optional {
+		return NULL
+	}
+
+	if kind, ok := lookupIntType(typ.Type); ok {
+		return &Integer{Value: new(big.Int), Kind: kind}
+	}
+
+	switch typ.Type {
+	case "Int":
+		return newInteger(0)
+	case "String":
+		return &String{Value: ""}
+	case "Bool":
+		return &Boolean{Value: false}
+	case "Address":
+		return &Address{Value: blockchain.ZeroAddress}
+	case "Map":
+		hash := NewHash()
+		hash.KeyType = typ.KeyType
+		hash.ValueType = typ.ValueType
+		return hash
+	case "Array":
+		return &Array{Elements: []Object{}, ElementType: typ.ElementType}
+	case "Tuple":
+		elements := []Object{}
+		for _, el := range typ.ElementTypes {
+			elements = append(elements, i.zeroValue(el))
+		}
+		return &Tuple{Elements: elements}
+	}
+
+	switch definition := i.declaredType(typ.Type).(type) {
+	case *StructType:
+		fields := make(map[string]Object)
+		for _, f := range definition.Fields {
+			fields[f.Name.Value] = i.zeroValue(f.Type)
+		}
+		return &Struct{Definition: definition, Fields: fields}
+	case *EnumType:
+		// Like Solidity, an enum starts at its first variant
+		return definition.Variants[0]
+	}
+
+	return NULL
+}
+
+// declaredType returns the struct or enum type a type name refers to, or
+// nil if the name is not a user-defined type
+func (i *Interpreter) declaredType(name string) Object {
+	obj, ok := i.env.Get(name)
+	if !ok {
+		return nil
+	}
+
+	switch obj.(type) {
+	case *StructType, *EnumType:
+		return obj
+	default:
+		return nil
+	}
+}
+
+// coerceToType checks that a value can be stored in a binding of the given
+// declared type and returns the value as that type. Plain Int values are
+// range-checked against sized integer types; values of a different sized
+// type must be converted with an explicit cast.
+func (i *Interpreter) coerceToType(val Object, typ *parser.TypeExpression, token parser.Token) (Object, error) {
+	if typ == nil {
+		return val, nil
+	}
+
+	if typ.Optional {
+		if val == NULL {
+			return val, nil
+		}
+		plain := *typ
+		plain.Optional = false
+		return i.coerceToType(val, &plain, token)
+	}
+
+	if typ.Type == "fn" {
+		return coerceToFunctionType(val, typ, token)
+	}
+
+	if typ.Type == "Tuple" {
+		return i.coerceToTuple(val, typ, token)
+	}
+
+	if kind, ok := lookupIntType(typ.Type); ok {
+		integer, ok := val.(*Integer)
+		if !ok {
+			return nil, typeMismatchError(typ, val, token)
+		}
+		if integer.Kind == kind {
+			return integer, nil
+		}
+		if integer.Kind != nil {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Cannot implicitly convert %s to %s; use an explicit cast", integer.Kind.Name, kind.Name),
+				token.Line, token.Column, "")
+		}
+		if !kind.Contains(integer.Value) {
+			return nil, errors.NewRuntimeError(
+				fmt.Sprintf("Value %s out of range for %s", integer.Value, kind.Name),
+				token.Line, token.Column, "")
+		}
+		return &Integer{Value: integer.Value, Kind: kind}, nil
+	}
+
+	switch definition := i.declaredType(typ.Type).(type) {
+	case *StructType:
+		if s, ok := val.(*Struct); !ok || s.Definition != definition {
+			return nil, typeMismatchError(typ, val, token)
+		}
+		return val, nil
+	case *EnumType:
+		if v, ok := val.(*EnumValue); !ok || v.Enum != definition {
+			return nil, typeMismatchError(typ, val, token)
+		}
+		return val, nil
+	case *ErrorType:
+		if v, ok := val.(*ErrorValue); !ok || v.Definition != definition {
+			return nil, typeMismatchError(typ, val, token)
+		}
+		return val, nil
+	}
+
+	expected, ok := typeNames[typ.Type]
+	if !ok {
+		// Unknown types are not checked
+		return val, nil
+	}
+	if val.Type() != expected {
+		return nil, typeMismatchError(typ, val, token)
+	}
+
+	switch val := val.(type) {
+	case *Integer:
+		if val.Kind != nil {
+			if !inIntRange(val.Value) {
+				return nil, errors.NewRuntimeError(
+					fmt.Sprintf("Value %s out of range for Int", val.Value),
+					token.Line, token.Column, "")
+			}
+			return &Integer{Value: val.Value}, nil
+		}
+	case *Hash:
+		if val.KeyType == nil && val.ValueType == nil && (typ.KeyType != nil || typ.ValueType != nil) {
+			return i.typeHash(val, typ, token)
+		}
+	case *Array:
+		if val.ElementType == nil && typ.ElementType != nil {
+			return i.typeArray(val, typ, token)
+		}
+	}
+
+	return val, nil
+}
+
+// coerceToFunctionType checks that a value can be called as a function of
+// the given type. User-defined functions must accept as many arguments as
+// the type declares; their argument and return types are checked when they
+// are called.
+func coerceToFunctionType(val Object, typ *parser.TypeExpression, token parser.Token) (Object, error) {
+	switch fn := val.(type) {
+	case *Function:
+		if !acceptsArguments(fn.Parameters, len(typ.ParamTypes)) {
+			return nil, errors.NewTypeError(
+				fmt.Sprintf("Type mismatch: expected %s, got a function with %d parameters", typ.String(), len(fn.Parameters)),
+				token.Line, token.Column, "")
+		}
+		return val, nil
+	case *Builtin, *BoundMethod:
+		return val, nil
+	default:
+		return nil, typeMismatchError(typ, val, token)
+	}
+}
+
+// acceptsArguments reports whether a function with the given parameters
+// can be called with n positional arguments
+func acceptsArguments(params []*parser.ParameterStatement, n int) bool {
+	required := 0
+	for _, param := range params {
+		if param.Variadic {
+			return n >= required
+		}
+		if param.Default == nil {
+			required++
+		}
+	}
+	return n >= required && n <= len(params)
+}
+
+// typeArray attaches a declared element type to an untyped array, checking
+// the elements it already holds. The array itself is typed, not a copy, so
+// every reference to it sees the changes made through the typed binding.
+func (i *Interpreter) typeArray(array *Array, typ *parser.TypeExpression, token parser.Token) (Object, error) {
+	elements := make([]Object, 0, len(array.Elements))
+	for _, el := range array.Elements {
+		elem, err := i.coerceToType(el, typ.ElementType, token)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, elem)
+	}
+
+	copy(array.Elements, elements)
+	array.ElementType = typ.ElementType
+	return array, nil
+}
+
+// typeHash attaches declared key and value types to an untyped hash,
+// checking the entries it already holds. Like typeArray, it types the hash
+// in place.
+func (i *Interpreter) typeHash(hash *Hash, typ *parser.TypeExpression, token parser.Token) (Object, error) {
+	pairs := []HashPair{}
+	for _, pair := range hash.Entries() {
+		key, err := i.coerceToType(pair.Key, typ.KeyType, token)
+		if err != nil {
+			return nil, err
+		}
+		value, err := i.coerceToType(pair.Value, typ.ValueType, token)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, HashPair{Key: key, Value: value})
+	}
+
+	// The checked keys are equal to the ones they replace, so each pair
+	// overwrites its own entry in place
+	for _, pair := range pairs {
+		hash.Set(pair.Key.(Hashable).HashKey(), pair)
+	}
+	hash.KeyType = typ.KeyType
+	hash.ValueType = typ.ValueType
+	return hash, nil
+}
+
+// bindTypeParameters binds the type parameters of a generic function that
+// occur in a parameter type to the types of the corresponding parts of an
+// argument. Parameters bound by an earlier argument keep their binding.
+func bindTypeParameters(typ *parser.TypeExpression, val Object, bindings map[string]*parser.TypeExpression) {
+	if typ == nil || val == nil || val == NULL {
+		return
+	}
+
+	if binding, ok := bindings[typ.Type]; ok {
+		if binding == nil {
+			bindings[typ.Type] = runtimeType(val)
+		}
+		return
+	}
+
+	switch val := val.(type) {
+	case *Array:
+		if typ.Type == "Array" && len(val.Elements) != 0 {
+			bindTypeParameters(typ.ElementType, val.Elements[0], bindings)
+		}
+	case *Hash:
+		if entries := val.Entries(); typ.Type == "Map" && len(entries) != 0 {
+			bindTypeParameters(typ.KeyType, entries[0].Key, bindings)
+			bindTypeParameters(typ.ValueType, entries[0].Value, bindings)
+		}
+	case *Tuple:
+		if typ.Type == "Tuple" && len(typ.ElementTypes) == len(val.Elements) {
+			for idx, el := range val.Elements {
+				bindTypeParameters(typ.ElementTypes[idx], el, bindings)
+			}
+		}
+	}
+}
+
+// substituteTypeParameters replaces the type parameters in a type with the
+// types bound to them. Parameters that are not bound are left unchecked.
+func substituteTypeParameters(typ *parser.TypeExpression, bindings map[string]*parser.TypeExpression) *parser.TypeExpression {
+	if typ == nil {
+		return nil
+	}
+
+	if binding, ok := bindings[typ.Type]; ok {
+		if binding == nil || !typ.Optional {
+			return binding
+		}
+		optional := *binding
+		optional.Optional = true
+		return &optional
+	}
+
+	substituted := *typ
+	substituted.KeyType = substituteTypeParameters(typ.KeyType, bindings)
+	substituted.ValueType = substituteTypeParameters(typ.ValueType, bindings)
+	substituted.ElementType = substituteTypeParameters(typ.ElementType, bindings)
+	substituted.ReturnType = substituteTypeParameters(typ.ReturnType, bindings)
+	if typ.ParamTypes != nil {
+		substituted.ParamTypes = []*parser.TypeExpression{}
+		for _, param := range typ.ParamTypes {
+			if bound := substituteTypeParameters(param, bindings); bound != nil {
+				param = bound
+			}
+			substituted.ParamTypes = append(substituted.ParamTypes, param)
+		}
+	}
+	if typ.ElementTypes != nil {
+		substituted.ElementTypes = []*parser.TypeExpression{}
+		for _, el := range typ.ElementTypes {
+			if bound := substituteTypeParameters(el, bindings); bound != nil {
+				el = bound
+			}
+			substituted.ElementTypes = append(substituted.ElementTypes, el)
+		}
+	}
+	return &substituted
+}
+
+// runtimeType returns the type of a value as a type expression, including
+// the declared key, value and element types of maps and arrays
+func runtimeType(val Object) *parser.TypeExpression {
+	switch val := val.(type) {
+	case *Hash:
+		return &parser.TypeExpression{Type: "Map", KeyType: val.KeyType, ValueType: val.ValueType}
+	case *Array:
+		return &parser.TypeExpression{Type: "Array", ElementType: val.ElementType}
+	case *Tuple:
+		elements := []*parser.TypeExpression{}
+		for _, el := range val.Elements {
+			elements = append(elements, runtimeType(el))
+		}
+		return &parser.TypeExpression{Type: "Tuple", ElementTypes: elements}
+	default:
+		return &parser.TypeExpression{Type: typeName(val)}
+	}
+}
+
+// typeMismatchError creates the error raised when a value does not have its declared type
+func typeMismatchError(typ *parser.TypeExpression, val Object, token parser.Token) error {
+	return errors.NewTypeError(
+		fmt.Sprintf("Type mismatch: expected %s, got %s", typ.String(), val.Type()),
+		token.Line, token.Column, "")
+}
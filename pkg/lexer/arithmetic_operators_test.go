@@ -0,0 +1,54 @@
+package lexer
+
+import "testing"
+
+// TestArithmeticAndBitwiseOperators tests the lexer's ability to recognize
+// modulo, exponent, bitwise and shift operators
+func TestArithmeticAndBitwiseOperators(t *testing.T) {
+	input := `a % b ** c & d | e ^ ~f << 2 >> 1 <= g >= h %= 3`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{IDENT, "a"},
+		{PERCENT, "%"},
+		{IDENT, "b"},
+		{Power, "**"},
+		{IDENT, "c"},
+		{AMPERSAND, "&"},
+		{IDENT, "d"},
+		{PIPE, "|"},
+		{IDENT, "e"},
+		{CARET, "^"},
+		{TILDE, "~"},
+		{IDENT, "f"},
+		{ShiftLeft, "<<"},
+		{INT, "2"},
+		{ShiftRight, ">>"},
+		{INT, "1"},
+		{LessEq, "<="},
+		{IDENT, "g"},
+		{GreaterEq, ">="},
+		{IDENT, "h"},
+		{PercentAssign, "%="},
+		{INT, "3"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package lexer
+
+import "testing"
+
+// TestAssignmentOperators tests the lexer's ability to recognize assignment operators
+func TestAssignmentOperators(t *testing.T) {
+	input := `
+x = 1
+x += 2
+x -= 3
+x *= 4
+x /= 5
+x++
+x--
+a + +b
+a - -b
+`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{IDENT, "x"},
+		{ASSIGN, "="},
+		{INT, "1"},
+		{IDENT, "x"},
+		{PlusAssign, "+="},
+		{INT, "2"},
+		{IDENT, "x"},
+		{MinusAssign, "-="},
+		{INT, "3"},
+		{IDENT, "x"},
+		{AsteriskAssign, "*="},
+		{INT, "4"},
+		{IDENT, "x"},
+		{SlashAssign, "/="},
+		{INT, "5"},
+		{IDENT, "x"},
+		{Increment, "++"},
+		{IDENT, "x"},
+		{Decrement, "--"},
+		{IDENT, "a"},
+		{PLUS, "+"},
+		{PLUS, "+"},
+		{IDENT, "b"},
+		{IDENT, "a"},
+		{MINUS, "-"},
+		{MINUS, "-"},
+		{IDENT, "b"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package lexer
+
+import "testing"
+
+// TestConstAndImmutableTokens tests the lexer's ability to recognize
+// constant declarations and immutable state fields
+func TestConstAndImmutableTokens(t *testing.T) {
+	input := `const MAX = 10;
+state { immutable owner: Address }`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{CONST, "const"},
+		{IDENT, "MAX"},
+		{ASSIGN, "="},
+		{INT, "10"},
+		{SEMICOLON, ";"},
+		{STATE, "state"},
+		{LBRACE, "{"},
+		{IMMUTABLE, "immutable"},
+		{IDENT, "owner"},
+		{COLON, ":"},
+		{ADDRESS, "Address"},
+		{RBRACE, "}"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package lexer
+
+import "testing"
+
+// TestEnumAndMatchTokens tests the lexer's ability to recognize enum
+// declarations and match arms
+func TestEnumAndMatchTokens(t *testing.T) {
+	input := `enum Phase { Open, Ended }
+match (p) { Phase.Open => 1, _ => x == 2 }`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{ENUM, "enum"},
+		{IDENT, "Phase"},
+		{LBRACE, "{"},
+		{IDENT, "Open"},
+		{COMMA, ","},
+		{IDENT, "Ended"},
+		{RBRACE, "}"},
+		{MATCH, "match"},
+		{LPAREN, "("},
+		{IDENT, "p"},
+		{RPAREN, ")"},
+		{LBRACE, "{"},
+		{IDENT, "Phase"},
+		{DOT, "."},
+		{IDENT, "Open"},
+		{FatArrow, "=>"},
+		{INT, "1"},
+		{COMMA, ","},
+		{IDENT, "_"},
+		{FatArrow, "=>"},
+		{IDENT, "x"},
+		{EQ, "=="},
+		{INT, "2"},
+		{RBRACE, "}"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package lexer
+
+import "testing"
+
+// TestInterpolatedStringTokens tests the lexer's ability to split
+// interpolated strings into their text and the tokens of their expressions
+func TestInterpolatedStringTokens(t *testing.T) {
+	input := `"Sent ${amount:08x} to ${users[0]} at ${ {"a": 1}["a"] }"
+"outer ${"inner ${n}"}" "\${literal} $5"`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{StringStart, "Sent ", 1, 1},
+		{IDENT, "amount", 1, 9},
+		{FormatSpec, "08x", 1, 15},
+		{StringMiddle, " to ", 1, 19},
+		{IDENT, "users", 1, 26},
+		{LBRACKET, "[", 1, 31},
+		{INT, "0", 1, 32},
+		{RBRACKET, "]", 1, 33},
+		{StringMiddle, " at ", 1, 34},
+		{LBRACE, "{", 1, 42},
+		{STRING, "a", 1, 43},
+		// Colons inside brackets are not formats
+		{COLON, ":", 1, 46},
+		{INT, "1", 1, 48},
+		{RBRACE, "}", 1, 49},
+		{LBRACKET, "[", 1, 50},
+		{STRING, "a", 1, 51},
+		{RBRACKET, "]", 1, 54},
+		{StringEnd, "", 1, 56},
+		{StringStart, "outer ", 2, 1},
+		{StringStart, "inner ", 2, 10},
+		{IDENT, "n", 2, 19},
+		{StringEnd, "", 2, 20},
+		{StringEnd, "", 2, 22},
+		{STRING, "${literal} $5", 2, 25},
+		{EOF, "", 2, 41},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+
+	if len(l.Errors()) != 0 {
+		t.Errorf("unexpected lexer errors: %v", l.Errors())
+	}
+}
@@ -1,6 +1,10 @@
 package lexer
 
 import (
+	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
+	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -16,6 +20,12 @@ type Lexer struct {
 	ch           rune // current char under examination
 	line         int  // current line number
 	column       int  // current column number
+
+	errors []*errors.Error // malformed tokens found so far
+
+	// The number of unclosed brackets in each ${...} interpolation being
+	// read, innermost last
+	interpolations []int
 }
 
 // New creates a new Lexer for the given input string.
@@ -41,6 +51,7 @@ func New(input string) *Lexer {
 func (l *Lexer) readChar() {
 	if l.readPosition >= len(l.input) {
 		l.ch = 0 // ASCII code for 'NUL' character
+		l.position = len(l.input)
 	} else {
 		r, width := utf8.DecodeRuneInString(l.input[l.readPosition:])
 		l.ch = r
@@ -50,6 +61,17 @@ func (l *Lexer) readChar() {
 	l.column++
 }
 
+// Errors returns the syntax errors found in the tokens read so far, such as
+// unterminated strings and invalid escape sequences
+func (l *Lexer) Errors() []*errors.Error {
+	return l.errors
+}
+
+// addError records a syntax error at the given position
+func (l *Lexer) addError(message string, line, column int) {
+	l.errors = append(l.errors, errors.NewSyntaxError(message, line, column, ""))
+}
+
 // peekChar returns the next character without advancing the position
 func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
@@ -72,9 +94,8 @@ func (l *Lexer) NextToken() Token {
 
 	l.skipWhitespace()
 
-	// Set the current position for the token
-	tok.Line = l.line
-	tok.Column = l.column
+	// Remember where the token starts; the cases below replace tok
+	line, column := l.line, l.column
 
 	switch l.ch {
 	case '=':
@@ -82,13 +103,41 @@ func (l *Lexer) NextToken() Token {
 			ch := l.ch
 			l.readChar()
 			tok = Token{Type: EQ, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: FatArrow, Literal: string(ch) + string(l.ch)}
 		} else {
 			tok = newToken(ASSIGN, l.ch)
 		}
 	case '+':
-		tok = newToken(PLUS, l.ch)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: PlusAssign, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '+' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: Increment, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(PLUS, l.ch)
+		}
 	case '-':
-		tok = newToken(MINUS, l.ch)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: MinusAssign, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '-' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: Decrement, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: Arrow, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(MINUS, l.ch)
+		}
 	case '!':
 		if l.peekChar() == '=' {
 			ch := l.ch
@@ -105,16 +154,46 @@ func (l *Lexer) NextToken() Token {
 		} else if l.peekChar() == '*' {
 			l.skipBlockComment()
 			return l.NextToken()
+		} else if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: SlashAssign, Literal: string(ch) + string(l.ch)}
 		} else {
 			tok = newToken(SLASH, l.ch)
 		}
 	case '*':
-		tok = newToken(ASTERISK, l.ch)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: AsteriskAssign, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '*' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: Power, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(ASTERISK, l.ch)
+		}
+	case '%':
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: PercentAssign, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(PERCENT, l.ch)
+		}
+	case '^':
+		tok = newToken(CARET, l.ch)
+	case '~':
+		tok = newToken(TILDE, l.ch)
 	case '<':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
 			tok = Token{Type: LessEq, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '<' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: ShiftLeft, Literal: string(ch) + string(l.ch)}
 		} else {
 			tok = newToken(LT, l.ch)
 		}
@@ -123,6 +202,10 @@ func (l *Lexer) NextToken() Token {
 			ch := l.ch
 			l.readChar()
 			tok = Token{Type: GreaterEq, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: ShiftRight, Literal: string(ch) + string(l.ch)}
 		} else {
 			tok = newToken(GT, l.ch)
 		}
@@ -132,7 +215,7 @@ func (l *Lexer) NextToken() Token {
 			l.readChar()
 			tok = Token{Type: AND, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(ILLEGAL, l.ch)
+			tok = newToken(AMPERSAND, l.ch)
 		}
 	case '|':
 		if l.peekChar() == '|' {
@@ -140,31 +223,70 @@ func (l *Lexer) NextToken() Token {
 			l.readChar()
 			tok = Token{Type: OR, Literal: string(ch) + string(l.ch)}
 		} else {
-			tok = newToken(ILLEGAL, l.ch)
+			tok = newToken(PIPE, l.ch)
 		}
 	case ';':
 		tok = newToken(SEMICOLON, l.ch)
 	case ':':
+		if n := len(l.interpolations); n != 0 && l.interpolations[n-1] == 0 {
+			// A colon outside brackets starts the format of an interpolation
+			tok.Type = FormatSpec
+			tok.Literal = l.readFormatSpec()
+			tok.Line, tok.Column = line, column
+			return tok
+		}
 		tok = newToken(COLON, l.ch)
 	case ',':
 		tok = newToken(COMMA, l.ch)
 	case '(':
+		l.openBracket()
 		tok = newToken(LPAREN, l.ch)
 	case ')':
+		l.closeBracket()
 		tok = newToken(RPAREN, l.ch)
 	case '{':
+		l.openBracket()
 		tok = newToken(LBRACE, l.ch)
 	case '}':
-		tok = newToken(RBRACE, l.ch)
+		if n := len(l.interpolations); n != 0 && l.interpolations[n-1] == 0 {
+			// The brace ends an interpolation and the string goes on
+			l.interpolations = l.interpolations[:n-1]
+			tok.Literal, tok.Type = l.readString(line, column, StringEnd, StringMiddle)
+		} else {
+			l.closeBracket()
+			tok = newToken(RBRACE, l.ch)
+		}
 	case '[':
+		l.openBracket()
 		tok = newToken(LBRACKET, l.ch)
 	case ']':
+		l.closeBracket()
 		tok = newToken(RBRACKET, l.ch)
 	case '"':
+		tok.Literal, tok.Type = l.readString(line, column, STRING, StringStart)
+	case '`':
 		tok.Type = STRING
-		tok.Literal = l.readString()
+		tok.Literal = l.readRawString(line, column)
 	case '.':
-		tok = newToken(DOT, l.ch)
+		if strings.HasPrefix(l.input[l.position:], "...") {
+			l.readChar()
+			l.readChar()
+			tok = Token{Type: Ellipsis, Literal: "..."}
+		} else {
+			tok = newToken(DOT, l.ch)
+		}
+	case '?':
+		if l.peekChar() == '?' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: NullCoalesce, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '.' {
+			ch := l.ch
+			l.readChar()
+			tok = Token{Type: SafeDot, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(QUESTION, l.ch)
+		}
 	case 0:
 		tok.Literal = ""
 		tok.Type = EOF
@@ -172,17 +294,21 @@ func (l *Lexer) NextToken() Token {
 		if isLetter(l.ch) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = LookupIdent(tok.Literal)
+			tok.Line, tok.Column = line, column
 			return tok
 		} else if isDigit(l.ch) {
 			tok.Type = INT
-			tok.Literal = l.readNumber()
+			tok.Literal = l.readNumber(line, column)
+			tok.Line, tok.Column = line, column
 			return tok
 		} else {
+			l.addError(fmt.Sprintf("Unexpected character %q", l.ch), line, column)
 			tok = newToken(ILLEGAL, l.ch)
 		}
 	}
 
 	l.readChar()
+	tok.Line, tok.Column = line, column
 	return tok
 }
 
@@ -245,22 +371,190 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[position:l.position]
 }
 
-// readNumber reads a number
-func (l *Lexer) readNumber() string {
+// readNumber reads a number: decimal digits, or hexadecimal digits after a
+// 0x prefix. Digits may be separated by underscores, and a decimal number
+// may end in an exponent, as in 1e18.
+func (l *Lexer) readNumber(line, column int) string {
 	position := l.position
-	for isDigit(l.ch) {
+
+	if l.ch == '0' && (l.peekChar() == 'x' || l.peekChar() == 'X') {
+		l.readChar()
+		l.readChar()
+		digits := l.readDigits(isHexDigit)
+		if !validDigits(digits) {
+			l.addError(fmt.Sprintf("Invalid number literal %s", l.input[position:l.position]), line, column)
+		}
+		return l.input[position:l.position]
+	}
+
+	valid := validDigits(l.readDigits(isDigit))
+	if l.ch == 'e' || l.ch == 'E' {
+		l.readChar()
+		valid = valid && validDigits(l.readDigits(isDigit))
+	}
+
+	if !valid {
+		l.addError(fmt.Sprintf("Invalid number literal %s", l.input[position:l.position]), line, column)
+	}
+	return l.input[position:l.position]
+}
+
+// readDigits reads a run of digits and underscore separators
+func (l *Lexer) readDigits(isDigit func(rune) bool) string {
+	position := l.position
+	for isDigit(l.ch) || l.ch == '_' {
+		l.readChar()
+	}
+	return l.input[position:l.position]
+}
+
+// validDigits checks that a run of digits is not empty and that every
+// underscore sits between two digits
+func validDigits(digits string) bool {
+	return digits != "" &&
+		!strings.HasPrefix(digits, "_") &&
+		!strings.HasSuffix(digits, "_") &&
+		!strings.Contains(digits, "__")
+}
+
+// openBracket records an opening bracket inside an interpolation
+func (l *Lexer) openBracket() {
+	if n := len(l.interpolations); n != 0 {
+		l.interpolations[n-1]++
+	}
+}
+
+// closeBracket records a closing bracket inside an interpolation
+func (l *Lexer) closeBracket() {
+	if n := len(l.interpolations); n != 0 && l.interpolations[n-1] != 0 {
+		l.interpolations[n-1]--
+	}
+}
+
+// readString reads a double-quoted string literal, decoding escape
+// sequences. Strings may span several lines. The text up to the closing
+// quote is an end token; the text up to a ${ that starts an interpolation
+// is an interpolation token, and the string goes on after the
+// interpolation's closing brace.
+func (l *Lexer) readString(line, column int, end, interpolation TokenType) (string, TokenType) {
+	var out strings.Builder
+
+	for {
+		l.readChar()
+
+		switch l.ch {
+		case '"':
+			return out.String(), end
+		case 0:
+			l.addError("Unterminated string literal", line, column)
+			return out.String(), end
+		case '$':
+			if l.peekChar() != '{' {
+				out.WriteRune(l.ch)
+				continue
+			}
+			l.readChar()
+			l.interpolations = append(l.interpolations, 0)
+			return out.String(), interpolation
+		case '\\':
+			if r, ok := l.readEscape(); ok {
+				out.WriteRune(r)
+			}
+		case '\n':
+			out.WriteRune(l.ch)
+			l.line++
+			l.column = 0
+		default:
+			out.WriteRune(l.ch)
+		}
+	}
+}
+
+// readEscape reads the escape sequence following a backslash
+func (l *Lexer) readEscape() (rune, bool) {
+	line, column := l.line, l.column
+	l.readChar()
+
+	switch l.ch {
+	case 'n':
+		return '\n', true
+	case 't':
+		return '\t', true
+	case 'r':
+		return '\r', true
+	case '0':
+		return 0, true
+	case '\\', '"', '`', '$':
+		return l.ch, true
+	case 'u':
+		return l.readUnicodeEscape(line, column)
+	case 0:
+		// Reported as an unterminated string by the caller
+		return 0, false
+	case '\n':
+		l.line++
+		l.column = 0
+	}
+
+	l.addError(fmt.Sprintf("Invalid escape sequence \\%c", l.ch), line, column)
+	return 0, false
+}
+
+// readUnicodeEscape reads the {XXXX} part of a \u{XXXX} escape sequence,
+// which holds one to six hexadecimal digits
+func (l *Lexer) readUnicodeEscape(line, column int) (rune, bool) {
+	if l.peekChar() != '{' {
+		l.addError("Invalid unicode escape sequence: expected \\u{XXXX}", line, column)
+		return 0, false
+	}
+	l.readChar()
+
+	var digits strings.Builder
+	for isHexDigit(l.peekChar()) {
+		l.readChar()
+		digits.WriteRune(l.ch)
+	}
+
+	if l.peekChar() != '}' || digits.Len() == 0 || digits.Len() > 6 {
+		l.addError("Invalid unicode escape sequence: expected \\u{XXXX}", line, column)
+		return 0, false
+	}
+	l.readChar()
+
+	value, _ := strconv.ParseUint(digits.String(), 16, 32)
+	if !utf8.ValidRune(rune(value)) {
+		l.addError(fmt.Sprintf("Invalid unicode code point U+%s", strings.ToUpper(digits.String())), line, column)
+		return 0, false
+	}
+
+	return rune(value), true
+}
+
+// readFormatSpec reads the format of an interpolation, which runs from
+// the colon to the closing brace
+func (l *Lexer) readFormatSpec() string {
+	// Skip the colon
+	l.readChar()
+
+	position := l.position
+	for l.ch != '}' && l.ch != '"' && l.ch != '\n' && l.ch != 0 {
 		l.readChar()
 	}
 	return l.input[position:l.position]
 }
 
-// readString reads a string literal
-func (l *Lexer) readString() string {
-	// Skip the opening quote
+// readRawString reads a backtick-quoted string literal. Raw strings have no
+// escape sequences and may span several lines.
+func (l *Lexer) readRawString(line, column int) string {
+	// Skip the opening backtick
 	l.readChar()
 
 	position := l.position
-	for l.ch != '"' && l.ch != 0 {
+	for l.ch != '`' {
+		if l.ch == 0 {
+			l.addError("Unterminated raw string literal", line, column)
+			break
+		}
 		if l.ch == '\n' {
 			l.line++
 			l.column = 0
@@ -281,6 +575,11 @@ func isDigit(ch rune) bool {
 	return unicode.IsDigit(ch)
 }
 
+// isHexDigit checks if a rune is a hexadecimal digit
+func isHexDigit(ch rune) bool {
+	return ('0' <= ch && ch <= '9') || ('a' <= ch && ch <= 'f') || ('A' <= ch && ch <= 'F')
+}
+
 // newToken creates a new token
 func newToken(tokenType TokenType, ch rune) Token {
 	return Token{Type: tokenType, Literal: string(ch)}
@@ -203,8 +203,7 @@ func TestNextToken(t *testing.T) {
 		{DOT, "."},
 		{IDENT, "sender"},
 		{RBRACKET, "]"},
-		{GT, ">"},
-		{ASSIGN, "="},
+		{GreaterEq, ">="},
 		{IDENT, "amount"},
 		{COMMA, ","},
 		{STRING, "Insufficient balance"},
@@ -217,8 +216,7 @@ func TestNextToken(t *testing.T) {
 		{DOT, "."},
 		{IDENT, "sender"},
 		{RBRACKET, "]"},
-		{MINUS, "-"},
-		{ASSIGN, "="},
+		{MinusAssign, "-="},
 		{IDENT, "amount"},
 		{SEMICOLON, ";"},
 
@@ -226,8 +224,7 @@ func TestNextToken(t *testing.T) {
 		{LBRACKET, "["},
 		{IDENT, "to"},
 		{RBRACKET, "]"},
-		{PLUS, "+"},
-		{ASSIGN, "="},
+		{PlusAssign, "+="},
 		{IDENT, "amount"},
 		{SEMICOLON, ";"},
 
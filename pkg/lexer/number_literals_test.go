@@ -0,0 +1,77 @@
+package lexer
+
+import "testing"
+
+// TestNumberLiterals tests the lexer's ability to recognize hex literals,
+// underscore separators and exponents
+func TestNumberLiterals(t *testing.T) {
+	input := `42 0xff 0XAB_CD 1_000_000 1e18 5E3 2_5e1_0 0x`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{INT, "42"},
+		{INT, "0xff"},
+		{INT, "0XAB_CD"},
+		{INT, "1_000_000"},
+		{INT, "1e18"},
+		{INT, "5E3"},
+		{INT, "2_5e1_0"},
+		{INT, "0x"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+
+	// Only the bare 0x prefix is malformed
+	if len(l.Errors()) != 1 || l.Errors()[0].Message != "Invalid number literal 0x" {
+		t.Errorf("wrong errors: %v", l.Errors())
+	}
+}
+
+// TestInvalidNumberLiterals tests that misplaced separators and empty
+// exponents are reported where the literal starts
+func TestInvalidNumberLiterals(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"x = 1__0", "Invalid number literal 1__0"},
+		{"x = 1_", "Invalid number literal 1_"},
+		{"x = 0x_ff", "Invalid number literal 0x_ff"},
+		{"x = 1e", "Invalid number literal 1e"},
+		{"x = 1e_5", "Invalid number literal 1e_5"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		for tok := l.NextToken(); tok.Type != EOF; tok = l.NextToken() {
+		}
+
+		errs := l.Errors()
+		if len(errs) != 1 {
+			t.Fatalf("tests[%d] - expected 1 error, got %d: %v", i, len(errs), errs)
+		}
+		if errs[0].Message != tt.expectedError {
+			t.Errorf("tests[%d] - message wrong. expected=%q, got=%q", i, tt.expectedError, errs[0].Message)
+		}
+		if errs[0].Line != 1 || errs[0].Column != 5 {
+			t.Errorf("tests[%d] - position wrong. expected=1:5, got=%d:%d", i, errs[0].Line, errs[0].Column)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package lexer
+
+import "testing"
+
+// TestParameterTokens tests the lexer's ability to recognize default,
+// variadic and named parameters
+func TestParameterTokens(t *testing.T) {
+	input := `function f(a: Int = 1, ...rest: Int) {}
+f(a: 2);
+x.y`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{FUNCTION, "function"},
+		{IDENT, "f"},
+		{LPAREN, "("},
+		{IDENT, "a"},
+		{COLON, ":"},
+		{IDENT, "Int"},
+		{ASSIGN, "="},
+		{INT, "1"},
+		{COMMA, ","},
+		{Ellipsis, "..."},
+		{IDENT, "rest"},
+		{COLON, ":"},
+		{IDENT, "Int"},
+		{RPAREN, ")"},
+		{LBRACE, "{"},
+		{RBRACE, "}"},
+		{IDENT, "f"},
+		{LPAREN, "("},
+		{IDENT, "a"},
+		{COLON, ":"},
+		{INT, "2"},
+		{RPAREN, ")"},
+		{SEMICOLON, ";"},
+		// A single dot is still a field access
+		{IDENT, "x"},
+		{DOT, "."},
+		{IDENT, "y"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
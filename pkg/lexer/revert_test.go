@@ -0,0 +1,67 @@
+package lexer
+
+import "testing"
+
+// TestErrorHandlingTokens tests the lexer's ability to recognize error
+// declarations, revert, assert and try/catch
+func TestErrorHandlingTokens(t *testing.T) {
+	input := `error Paused();
+revert Paused();
+assert(ok);
+try c.f() as r { } catch (e) { }`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{ERROR, "error"},
+		{IDENT, "Paused"},
+		{LPAREN, "("},
+		{RPAREN, ")"},
+		{SEMICOLON, ";"},
+		{REVERT, "revert"},
+		{IDENT, "Paused"},
+		{LPAREN, "("},
+		{RPAREN, ")"},
+		{SEMICOLON, ";"},
+		{ASSERT, "assert"},
+		{LPAREN, "("},
+		{IDENT, "ok"},
+		{RPAREN, ")"},
+		{SEMICOLON, ";"},
+		{TRY, "try"},
+		{IDENT, "c"},
+		{DOT, "."},
+		{IDENT, "f"},
+		{LPAREN, "("},
+		{RPAREN, ")"},
+		// as is only special in try and import statements
+		{IDENT, "as"},
+		{IDENT, "r"},
+		{LBRACE, "{"},
+		{RBRACE, "}"},
+		{CATCH, "catch"},
+		{LPAREN, "("},
+		{IDENT, "e"},
+		{RPAREN, ")"},
+		{LBRACE, "{"},
+		{RBRACE, "}"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
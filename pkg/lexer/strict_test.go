@@ -0,0 +1,34 @@
+package lexer
+
+import "testing"
+
+// TestPragmaTokens tests the lexer's ability to recognize pragmas
+func TestPragmaTokens(t *testing.T) {
+	input := `pragma strict;`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{PRAGMA, "pragma"},
+		{IDENT, "strict"},
+		{SEMICOLON, ";"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
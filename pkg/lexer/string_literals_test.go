@@ -0,0 +1,120 @@
+package lexer
+
+import "testing"
+
+// TestStringEscapes tests the lexer's decoding of escape sequences in
+// double-quoted strings and their absence in raw strings
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"plain"`, "plain"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`"a\nb\tc\rd"`, "a\nb\tc\rd"},
+		{`"back\\slash"`, `back\slash`},
+		{`"nul\0"`, "nul\x00"},
+		{`"\u{41}\u{e9}\u{1F600}"`, "Aé😀"},
+		{`"line one
+line two"`, "line one\nline two"},
+		{"`raw \\n \"text\"`", `raw \n "text"`},
+		{"`multi\nline`", "multi\nline"},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != STRING {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, STRING, tok.Type)
+		}
+		if tok.Literal != tt.expected {
+			t.Errorf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expected, tok.Literal)
+		}
+		if len(l.Errors()) != 0 {
+			t.Errorf("tests[%d] - unexpected errors: %v", i, l.Errors())
+		}
+	}
+}
+
+// TestStringErrors tests that malformed strings are reported as syntax
+// errors at the position of the problem
+func TestStringErrors(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedError  string
+		expectedLine   int
+		expectedColumn int
+	}{
+		{`"abc`, "Unterminated string literal", 1, 1},
+		{"let s = \"one\ntwo", "Unterminated string literal", 1, 9},
+		{"`raw", "Unterminated raw string literal", 1, 1},
+		{`"ends with \`, "Unterminated string literal", 1, 1},
+		{`"bad \q"`, `Invalid escape sequence \q`, 1, 6},
+		{"x\n  \"\\u41\"", `Invalid unicode escape sequence: expected \u{XXXX}`, 2, 4},
+		{`"\u{}"`, `Invalid unicode escape sequence: expected \u{XXXX}`, 1, 2},
+		{`"\u{1234567}"`, `Invalid unicode escape sequence: expected \u{XXXX}`, 1, 2},
+		{`"\u{D800}"`, "Invalid unicode code point U+D800", 1, 2},
+		{"a @", "Unexpected character '@'", 1, 3},
+	}
+
+	for i, tt := range tests {
+		l := New(tt.input)
+		for tok := l.NextToken(); tok.Type != EOF; tok = l.NextToken() {
+		}
+
+		errs := l.Errors()
+		if len(errs) != 1 {
+			t.Fatalf("tests[%d] - expected 1 error, got %d: %v", i, len(errs), errs)
+		}
+		if errs[0].Message != tt.expectedError {
+			t.Errorf("tests[%d] - message wrong. expected=%q, got=%q", i, tt.expectedError, errs[0].Message)
+		}
+		if errs[0].Line != tt.expectedLine || errs[0].Column != tt.expectedColumn {
+			t.Errorf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d",
+				i, tt.expectedLine, tt.expectedColumn, errs[0].Line, errs[0].Column)
+		}
+	}
+}
+
+// TestTokenPositions tests that every token, including two-character
+// operators and tokens after multi-line strings, reports where it starts
+func TestTokenPositions(t *testing.T) {
+	input := "let x = a >= 10;\ns = \"one\ntwo\" + `three\nfour` == y"
+
+	tests := []struct {
+		expectedType   TokenType
+		expectedLine   int
+		expectedColumn int
+	}{
+		{LET, 1, 1},
+		{IDENT, 1, 5},
+		{ASSIGN, 1, 7},
+		{IDENT, 1, 9},
+		{GreaterEq, 1, 11},
+		{INT, 1, 14},
+		{SEMICOLON, 1, 16},
+		{IDENT, 2, 1},
+		{ASSIGN, 2, 3},
+		{STRING, 2, 5},
+		{PLUS, 3, 6},
+		{STRING, 3, 8},
+		{EQ, 4, 7},
+		{IDENT, 4, 10},
+		{EOF, 4, 11},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Errorf("tests[%d] - position of %q wrong. expected=%d:%d, got=%d:%d",
+				i, tok.Literal, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
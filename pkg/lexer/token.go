@@ -22,6 +22,14 @@ const (
 	INT    = "INT"    // 123456
 	STRING = "STRING" // "hello"
 
+	// Interpolated strings such as "a ${x} b ${y:04} c" are lexed in parts:
+	// StringStart "a ", the tokens of x, StringMiddle " b ", the tokens of
+	// y, FormatSpec "04" and StringEnd " c"
+	StringStart  = "STRING_START"
+	StringMiddle = "STRING_MIDDLE"
+	StringEnd    = "STRING_END"
+	FormatSpec   = "FORMAT_SPEC"
+
 	// Operators
 	ASSIGN   = "="
 	PLUS     = "+"
@@ -29,6 +37,25 @@ const (
 	BANG     = "!"
 	ASTERISK = "*"
 	SLASH    = "/"
+	PERCENT  = "%"
+	Power    = "**"
+
+	// Bitwise operators
+	AMPERSAND  = "&"
+	PIPE       = "|"
+	CARET      = "^"
+	TILDE      = "~"
+	ShiftLeft  = "<<"
+	ShiftRight = ">>"
+
+	// Assignment operators
+	PlusAssign     = "+="
+	MinusAssign    = "-="
+	AsteriskAssign = "*="
+	SlashAssign    = "/="
+	PercentAssign  = "%="
+	Increment      = "++"
+	Decrement      = "--"
 
 	LT         = "<"
 	GT         = ">"
@@ -46,6 +73,14 @@ const (
 	SEMICOLON = ";"
 	COLON     = ":"
 	DOT       = "."
+	Ellipsis  = "..."
+	FatArrow  = "=>"
+	Arrow     = "->"
+	QUESTION  = "?"
+
+	// Null handling operators
+	NullCoalesce = "??"
+	SafeDot      = "?."
 
 	LPAREN   = "("
 	RPAREN   = ")"
@@ -70,6 +105,24 @@ const (
 	ADDRESS     = "ADDRESS"
 	MAP         = "MAP"
 	CONSTRUCTOR = "CONSTRUCTOR"
+	WHILE       = "WHILE"
+	FOR         = "FOR"
+	BREAK       = "BREAK"
+	CONTINUE    = "CONTINUE"
+	IN          = "IN"
+	STRUCT      = "STRUCT"
+	ENUM        = "ENUM"
+	MATCH       = "MATCH"
+	IMPORT      = "IMPORT"
+	CONST       = "CONST"
+	IMMUTABLE   = "IMMUTABLE"
+	NULL        = "NULL"
+	ERROR       = "ERROR"
+	REVERT      = "REVERT"
+	ASSERT      = "ASSERT"
+	TRY         = "TRY"
+	CATCH       = "CATCH"
+	PRAGMA      = "PRAGMA"
 )
 
 // Keywords maps string literals to their token types
@@ -89,6 +142,24 @@ var Keywords = map[string]TokenType{
 	"Address":     ADDRESS,
 	"Map":         MAP,
 	"constructor": CONSTRUCTOR,
+	"while":       WHILE,
+	"for":         FOR,
+	"break":       BREAK,
+	"continue":    CONTINUE,
+	"in":          IN,
+	"struct":      STRUCT,
+	"enum":        ENUM,
+	"match":       MATCH,
+	"import":      IMPORT,
+	"const":       CONST,
+	"immutable":   IMMUTABLE,
+	"null":        NULL,
+	"error":       ERROR,
+	"revert":      REVERT,
+	"assert":      ASSERT,
+	"try":         TRY,
+	"catch":       CATCH,
+	"pragma":      PRAGMA,
 }
 
 // LookupIdent checks if the given identifier is a keyword
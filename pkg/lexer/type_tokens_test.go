@@ -0,0 +1,99 @@
+package lexer
+
+import "testing"
+
+// TestGenericTypeTokens tests the lexer's ability to recognize the tokens
+// of optional and function types
+func TestGenericTypeTokens(t *testing.T) {
+	input := `let f: fn(Int?) -> Array<Int> = g;
+x-->y;`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{LET, "let"},
+		{IDENT, "f"},
+		{COLON, ":"},
+		{IDENT, "fn"},
+		{LPAREN, "("},
+		{IDENT, "Int"},
+		{QUESTION, "?"},
+		{RPAREN, ")"},
+		{Arrow, "->"},
+		{IDENT, "Array"},
+		{LT, "<"},
+		{IDENT, "Int"},
+		{GT, ">"},
+		{ASSIGN, "="},
+		{IDENT, "g"},
+		{SEMICOLON, ";"},
+		// -- is read before ->
+		{IDENT, "x"},
+		{Decrement, "--"},
+		{GT, ">"},
+		{IDENT, "y"},
+		{SEMICOLON, ";"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+// TestNullHandlingTokens tests the lexer's ability to recognize null and
+// the null handling operators
+func TestNullHandlingTokens(t *testing.T) {
+	input := `let x: Int? = null;
+a?.b ?? c;`
+
+	tests := []struct {
+		expectedType    TokenType
+		expectedLiteral string
+	}{
+		{LET, "let"},
+		{IDENT, "x"},
+		{COLON, ":"},
+		{IDENT, "Int"},
+		{QUESTION, "?"},
+		{ASSIGN, "="},
+		{NULL, "null"},
+		{SEMICOLON, ";"},
+		{IDENT, "a"},
+		{SafeDot, "?."},
+		{IDENT, "b"},
+		{NullCoalesce, "??"},
+		{IDENT, "c"},
+		{SEMICOLON, ";"},
+		{EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
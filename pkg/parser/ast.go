@@ -2,6 +2,7 @@ package parser
 
 import (
 	"bytes"
+	"math/big"
 	"strings"
 )
 
@@ -47,6 +48,40 @@ func (p *Program) String() string {
 	return out.String()
 }
 
+// Strict reports whether the program starts with pragma strict, which
+// turns off the implicit conversions of values to strings and to booleans
+func (p *Program) Strict() bool {
+	for _, stmt := range p.Statements {
+		pragma, ok := stmt.(*PragmaStatement)
+		if !ok || pragma == nil {
+			return false
+		}
+		if pragma.Name.Value == "strict" {
+			return true
+		}
+	}
+	return false
+}
+
+// PragmaStatement represents a pragma selecting a language mode for the
+// file it starts (pragma strict;)
+type PragmaStatement struct {
+	Token Token // the 'pragma' token
+	Name  *Identifier
+}
+
+func (ps *PragmaStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (ps *PragmaStatement) TokenLiteral() string {
+	return ps.Token.Literal
+}
+
+// String returns a string representation of the pragma statement
+func (ps *PragmaStatement) String() string {
+	return "pragma " + ps.Name.String() + ";"
+}
+
 // ContractStatement represents a contract declaration
 type ContractStatement struct {
 	Token      Token // the 'contract' token
@@ -69,6 +104,10 @@ func (cs *ContractStatement) String() string {
 	out.WriteString("contract ")
 	out.WriteString(cs.Name.String())
 	out.WriteString(" ")
+	if cs.StateBlock != nil {
+		out.WriteString(cs.StateBlock.String())
+		out.WriteString(" ")
+	}
 	out.WriteString(cs.Body.String())
 
 	return out.String()
@@ -76,8 +115,8 @@ func (cs *ContractStatement) String() string {
 
 // StateBlockStatement represents a state block in a contract
 type StateBlockStatement struct {
-	Token Token // the 'state' token
-	Body  *BlockStatement
+	Token  Token // the 'state' token
+	Fields []*StateFieldStatement
 }
 
 func (sb *StateBlockStatement) statementNode() {}
@@ -91,19 +130,65 @@ func (sb *StateBlockStatement) TokenLiteral() string {
 func (sb *StateBlockStatement) String() string {
 	var out bytes.Buffer
 
-	out.WriteString("state ")
-	out.WriteString(sb.Body.String())
+	fields := []string{}
+	for _, f := range sb.Fields {
+		fields = append(fields, f.String())
+	}
+
+	out.WriteString("state { ")
+	out.WriteString(strings.Join(fields, "; "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// StateFieldStatement represents a typed field declared in a state block
+type StateFieldStatement struct {
+	Token     Token // the field name token
+	Name      *Identifier
+	Type      *TypeExpression
+	Value     Expression // optional initializer, required for constants
+	Constant  bool       // declared with 'const': never reassigned
+	Immutable bool       // declared with 'immutable': assigned only in the constructor
+}
+
+func (sf *StateFieldStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (sf *StateFieldStatement) TokenLiteral() string {
+	return sf.Token.Literal
+}
+
+// String returns a string representation of the state field
+func (sf *StateFieldStatement) String() string {
+	var out bytes.Buffer
+
+	if sf.Constant {
+		out.WriteString("const ")
+	}
+	if sf.Immutable {
+		out.WriteString("immutable ")
+	}
+	out.WriteString(sf.Name.String())
+	out.WriteString(": ")
+	out.WriteString(sf.Type.String())
+
+	if sf.Value != nil {
+		out.WriteString(" = ")
+		out.WriteString(sf.Value.String())
+	}
 
 	return out.String()
 }
 
 // FunctionStatement represents a function declaration
 type FunctionStatement struct {
-	Token      Token // the 'function' token
-	Name       *Identifier
-	Parameters []*ParameterStatement
-	ReturnType *TypeExpression
-	Body       *BlockStatement
+	Token          Token // the 'function' token
+	Name           *Identifier
+	TypeParameters []*Identifier // the type parameters of a generic function
+	Parameters     []*ParameterStatement
+	ReturnType     *TypeExpression
+	Body           *BlockStatement
 }
 
 func (fs *FunctionStatement) statementNode() {}
@@ -125,6 +210,15 @@ func (fs *FunctionStatement) String() string {
 	out.WriteString(fs.TokenLiteral())
 	out.WriteString(" ")
 	out.WriteString(fs.Name.String())
+	if len(fs.TypeParameters) != 0 {
+		typeParams := []string{}
+		for _, tp := range fs.TypeParameters {
+			typeParams = append(typeParams, tp.String())
+		}
+		out.WriteString("<")
+		out.WriteString(strings.Join(typeParams, ", "))
+		out.WriteString(">")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") ")
@@ -205,11 +299,117 @@ func (es *EventStatement) String() string {
 	return out.String()
 }
 
+// StructStatement represents a struct type declaration
+type StructStatement struct {
+	Token  Token // the 'struct' token
+	Name   *Identifier
+	Fields []*ParameterStatement
+}
+
+func (ss *StructStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (ss *StructStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+
+// String returns a string representation of the struct statement
+func (ss *StructStatement) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, f := range ss.Fields {
+		fields = append(fields, f.String())
+	}
+
+	out.WriteString("struct ")
+	out.WriteString(ss.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// EnumStatement represents an enum type declaration
+type EnumStatement struct {
+	Token    Token // the 'enum' token
+	Name     *Identifier
+	Variants []*Identifier
+}
+
+func (es *EnumStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (es *EnumStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+
+// String returns a string representation of the enum statement
+func (es *EnumStatement) String() string {
+	var out bytes.Buffer
+
+	variants := []string{}
+	for _, v := range es.Variants {
+		variants = append(variants, v.String())
+	}
+
+	out.WriteString("enum ")
+	out.WriteString(es.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(variants, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// ImportStatement represents an import of another source file. It either
+// binds the whole module to a name (import "lib/math.sx" as math) or binds
+// selected exports directly (import { add, sub } from "lib/math.sx").
+type ImportStatement struct {
+	Token Token // the 'import' token
+	Path  *StringLiteral
+	Alias *Identifier   // the namespace name given with 'as', if any
+	Names []*Identifier // the selected exports, empty when importing a namespace
+}
+
+func (is *ImportStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (is *ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+// String returns a string representation of the import statement
+func (is *ImportStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("import ")
+	if len(is.Names) > 0 {
+		names := []string{}
+		for _, n := range is.Names {
+			names = append(names, n.String())
+		}
+		out.WriteString("{ ")
+		out.WriteString(strings.Join(names, ", "))
+		out.WriteString(" } from ")
+	}
+	out.WriteString(is.Path.String())
+	if is.Alias != nil {
+		out.WriteString(" as ")
+		out.WriteString(is.Alias.String())
+	}
+
+	return out.String()
+}
+
 // ParameterStatement represents a parameter in a function or constructor
 type ParameterStatement struct {
-	Token Token // the parameter name token
-	Name  *Identifier
-	Type  *TypeExpression
+	Token    Token // the parameter name token
+	Name     *Identifier
+	Type     *TypeExpression
+	Default  Expression // the value used when the argument is omitted, if any
+	Variadic bool       // true for ...rest, which collects the remaining arguments into an array
 }
 
 func (ps *ParameterStatement) statementNode() {}
@@ -223,9 +423,18 @@ func (ps *ParameterStatement) TokenLiteral() string {
 func (ps *ParameterStatement) String() string {
 	var out bytes.Buffer
 
+	if ps.Variadic {
+		out.WriteString("...")
+	}
 	out.WriteString(ps.Name.String())
-	out.WriteString(": ")
-	out.WriteString(ps.Type.String())
+	if ps.Type != nil {
+		out.WriteString(": ")
+		out.WriteString(ps.Type.String())
+	}
+	if ps.Default != nil {
+		out.WriteString(" = ")
+		out.WriteString(ps.Default.String())
+	}
 
 	return out.String()
 }
@@ -277,12 +486,15 @@ func (es *ExpressionStatement) String() string {
 	return ""
 }
 
-// LetStatement represents a variable declaration
+// LetStatement represents a variable declaration. Constants are declared
+// the same way with 'const' and cannot be reassigned.
 type LetStatement struct {
-	Token Token // the 'let' token
-	Name  *Identifier
-	Type  *TypeExpression
-	Value Expression
+	Token    Token // the 'let' or 'const' token
+	Name     *Identifier
+	Pattern  *DestructuringPattern // set instead of Name by a destructuring let
+	Type     *TypeExpression
+	Value    Expression
+	Constant bool
 }
 
 func (ls *LetStatement) statementNode() {}
@@ -298,7 +510,11 @@ func (ls *LetStatement) String() string {
 
 	out.WriteString(ls.TokenLiteral())
 	out.WriteString(" ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 
 	if ls.Type != nil {
 		out.WriteString(": ")
@@ -315,6 +531,43 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+// DestructuringPattern represents the names a destructuring let binds:
+// the elements of a tuple `(a, b)` or an array `[a, b]`, or the fields of a
+// struct `{ x, y: py }`. The name _ discards a value.
+type DestructuringPattern struct {
+	Token  Token         // the '(', '[' or '{' token
+	Names  []*Identifier // the bound names
+	Fields []*Identifier // for struct patterns, the field each name is read from
+}
+
+func (dp *DestructuringPattern) expressionNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (dp *DestructuringPattern) TokenLiteral() string {
+	return dp.Token.Literal
+}
+
+// String returns a string representation of the pattern
+func (dp *DestructuringPattern) String() string {
+	names := []string{}
+	for idx, name := range dp.Names {
+		if dp.Fields != nil && dp.Fields[idx].Value != name.Value {
+			names = append(names, dp.Fields[idx].Value+": "+name.Value)
+		} else {
+			names = append(names, name.Value)
+		}
+	}
+
+	switch dp.Token.Literal {
+	case "[":
+		return "[" + strings.Join(names, ", ") + "]"
+	case "{":
+		return "{ " + strings.Join(names, ", ") + " }"
+	default:
+		return "(" + strings.Join(names, ", ") + ")"
+	}
+}
+
 // ReturnStatement represents a return statement
 type ReturnStatement struct {
 	Token       Token // the 'return' token
@@ -409,6 +662,298 @@ func (es *EmitStatement) String() string {
 	return out.String()
 }
 
+// ErrorStatement represents a custom error declaration such as
+// `error InsufficientBalance(needed: Int, available: Int)`
+type ErrorStatement struct {
+	Token  Token // the 'error' token
+	Name   *Identifier
+	Fields []*ParameterStatement
+}
+
+func (es *ErrorStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (es *ErrorStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+
+// String returns a string representation of the error statement
+func (es *ErrorStatement) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, f := range es.Fields {
+		fields = append(fields, f.String())
+	}
+
+	out.WriteString("error ")
+	out.WriteString(es.Name.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// RevertStatement represents a revert statement, which fails the running
+// call with an error value such as `InsufficientBalance(10, 5)` or with a
+// message
+type RevertStatement struct {
+	Token Token      // the 'revert' token
+	Error Expression // the error value or message
+}
+
+func (rs *RevertStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (rs *RevertStatement) TokenLiteral() string {
+	return rs.Token.Literal
+}
+
+// String returns a string representation of the revert statement
+func (rs *RevertStatement) String() string {
+	return rs.TokenLiteral() + " " + rs.Error.String()
+}
+
+// AssertStatement represents an assert statement, which checks an
+// invariant of the program rather than its input
+type AssertStatement struct {
+	Token     Token      // the 'assert' token
+	Condition Expression // the condition to check
+	Message   Expression // the error message (optional)
+}
+
+func (as *AssertStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (as *AssertStatement) TokenLiteral() string {
+	return as.Token.Literal
+}
+
+// String returns a string representation of the assert statement
+func (as *AssertStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(as.TokenLiteral())
+	out.WriteString("(")
+	out.WriteString(as.Condition.String())
+
+	if as.Message != nil {
+		out.WriteString(", ")
+		out.WriteString(as.Message.String())
+	}
+
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// TryStatement represents a call of a contract function whose failure is
+// handled, such as
+// `try vault.withdraw(5) as paid { ... } catch InsufficientBalance(needed, available) { ... }`
+type TryStatement struct {
+	Token   Token           // the 'try' token
+	Call    *CallExpression // the guarded call
+	Result  *Identifier     // bound to the result of the call, if named with 'as'
+	Body    *BlockStatement // run when the call succeeds
+	Catches []*CatchClause
+}
+
+func (ts *TryStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (ts *TryStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+// String returns a string representation of the try statement
+func (ts *TryStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(ts.Call.String())
+	if ts.Result != nil {
+		out.WriteString(" as ")
+		out.WriteString(ts.Result.String())
+	}
+	out.WriteString(" ")
+	out.WriteString(ts.Body.String())
+
+	for _, c := range ts.Catches {
+		out.WriteString(" ")
+		out.WriteString(c.String())
+	}
+
+	return out.String()
+}
+
+// CatchClause represents one catch of a try statement. With an error name
+// it handles failures with that error, binding the error's fields to its
+// parameters in order; without one it handles any failure, binding the
+// error value to its parameter if it has one.
+type CatchClause struct {
+	Token      Token       // the 'catch' token
+	ErrorName  *Identifier // nil for a clause that catches every failure
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+// String returns a string representation of the catch clause
+func (cc *CatchClause) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range cc.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString("catch ")
+	if cc.ErrorName != nil {
+		out.WriteString(cc.ErrorName.String())
+	}
+	if cc.ErrorName != nil || len(params) > 0 {
+		out.WriteString("(")
+		out.WriteString(strings.Join(params, ", "))
+		out.WriteString(") ")
+	}
+	out.WriteString(cc.Body.String())
+
+	return out.String()
+}
+
+// WhileStatement represents a while loop
+type WhileStatement struct {
+	Token     Token // the 'while' token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (ws *WhileStatement) TokenLiteral() string {
+	return ws.Token.Literal
+}
+
+// String returns a string representation of the while statement
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while (")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(") ")
+	out.WriteString(ws.Body.String())
+
+	return out.String()
+}
+
+// ForStatement represents a C-style for loop
+type ForStatement struct {
+	Token     Token      // the 'for' token
+	Init      Statement  // runs once before the loop (optional)
+	Condition Expression // checked before each iteration (optional)
+	Update    Expression // runs after each iteration (optional)
+	Body      *BlockStatement
+}
+
+func (fs *ForStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (fs *ForStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+
+// String returns a string representation of the for statement
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	if fs.Init != nil {
+		out.WriteString(strings.TrimSuffix(fs.Init.String(), ";"))
+	}
+	out.WriteString("; ")
+	if fs.Condition != nil {
+		out.WriteString(fs.Condition.String())
+	}
+	out.WriteString("; ")
+	if fs.Update != nil {
+		out.WriteString(fs.Update.String())
+	}
+	out.WriteString(") ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
+// ForInStatement represents iteration over an array or map
+// (e.g., for (item in items), for (i, item in items), for (key, value in balances))
+type ForInStatement struct {
+	Token     Token         // the 'for' token
+	Variables []*Identifier // one or two loop variables
+	Iterable  Expression
+	Body      *BlockStatement
+}
+
+func (fs *ForInStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (fs *ForInStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+
+// String returns a string representation of the for-in statement
+func (fs *ForInStatement) String() string {
+	var out bytes.Buffer
+
+	vars := []string{}
+	for _, v := range fs.Variables {
+		vars = append(vars, v.String())
+	}
+
+	out.WriteString("for (")
+	out.WriteString(strings.Join(vars, ", "))
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
+// BreakStatement represents a break statement
+type BreakStatement struct {
+	Token Token // the 'break' token
+}
+
+func (bs *BreakStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (bs *BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+// String returns a string representation of the break statement
+func (bs *BreakStatement) String() string {
+	return bs.Token.Literal + ";"
+}
+
+// ContinueStatement represents a continue statement
+type ContinueStatement struct {
+	Token Token // the 'continue' token
+}
+
+func (cs *ContinueStatement) statementNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (cs *ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+// String returns a string representation of the continue statement
+func (cs *ContinueStatement) String() string {
+	return cs.Token.Literal + ";"
+}
+
 // Identifier represents an identifier
 type Identifier struct {
 	Token Token // the identifier token
@@ -429,10 +974,15 @@ func (i *Identifier) String() string {
 
 // TypeExpression represents a type expression
 type TypeExpression struct {
-	Token     Token           // the type token
-	Type      string          // the type name (e.g., Int, String, Address, Map)
-	KeyType   *TypeExpression // for Map types, the key type
-	ValueType *TypeExpression // for Map types, the value type
+	Token        Token             // the type token
+	Type         string            // the type name (e.g., Int, String, Address, Map, Array, fn, Tuple)
+	KeyType      *TypeExpression   // for Map types, the key type
+	ValueType    *TypeExpression   // for Map types, the value type
+	ElementType  *TypeExpression   // for Array types, the element type
+	ParamTypes   []*TypeExpression // for function types, the parameter types
+	ReturnType   *TypeExpression   // for function types, the return type if any
+	ElementTypes []*TypeExpression // for tuple types, the types of the elements
+	Optional     bool              // true for T?, which may also hold null
 }
 
 func (te *TypeExpression) expressionNode() {}
@@ -446,15 +996,46 @@ func (te *TypeExpression) TokenLiteral() string {
 func (te *TypeExpression) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(te.Type)
+	if te.Type == "Tuple" {
+		elements := []string{}
+		for _, el := range te.ElementTypes {
+			elements = append(elements, el.String())
+		}
+		out.WriteString("(")
+		out.WriteString(strings.Join(elements, ", "))
+		out.WriteString(")")
+	} else {
+		out.WriteString(te.Type)
+	}
 
-	// If it's a Map type, include the key and value types
-	if te.Type == "Map" && te.KeyType != nil && te.ValueType != nil {
+	switch {
+	case te.Type == "Map" && te.KeyType != nil && te.ValueType != nil:
+		// If it's a Map type, include the key and value types
 		out.WriteString("<")
 		out.WriteString(te.KeyType.String())
 		out.WriteString(", ")
 		out.WriteString(te.ValueType.String())
 		out.WriteString(">")
+	case te.Type == "Array" && te.ElementType != nil:
+		out.WriteString("<")
+		out.WriteString(te.ElementType.String())
+		out.WriteString(">")
+	case te.Type == "fn":
+		params := []string{}
+		for _, p := range te.ParamTypes {
+			params = append(params, p.String())
+		}
+		out.WriteString("(")
+		out.WriteString(strings.Join(params, ", "))
+		out.WriteString(")")
+		if te.ReturnType != nil {
+			out.WriteString(" -> ")
+			out.WriteString(te.ReturnType.String())
+		}
+	}
+
+	if te.Optional {
+		out.WriteString("?")
 	}
 
 	return out.String()
@@ -463,7 +1044,7 @@ func (te *TypeExpression) String() string {
 // IntegerLiteral represents an integer literal
 type IntegerLiteral struct {
 	Token Token // the integer token
-	Value int64
+	Value *big.Int
 }
 
 func (il *IntegerLiteral) expressionNode() {}
@@ -478,6 +1059,87 @@ func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
 
+// StructLiteral represents a struct literal such as `Proposal { votes: 0 }`
+type StructLiteral struct {
+	Token  Token // the struct name token
+	Name   *Identifier
+	Fields []*Identifier // field names in source order
+	Values []Expression  // field values, parallel to Fields
+}
+
+func (sl *StructLiteral) expressionNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (sl *StructLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+
+// String returns a string representation of the struct literal
+func (sl *StructLiteral) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for i, name := range sl.Fields {
+		fields = append(fields, name.String()+": "+sl.Values[i].String())
+	}
+
+	out.WriteString(sl.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// MatchExpression represents a match over a subject value, such as
+// `match (phase) { Phase.Open => 1, Phase.Ended => 2 }`
+type MatchExpression struct {
+	Token   Token // the 'match' token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (me *MatchExpression) expressionNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (me *MatchExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+// String returns a string representation of the match expression
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match (")
+	out.WriteString(me.Subject.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// MatchArm represents one arm of a match expression
+type MatchArm struct {
+	Token   Token      // the first token of the pattern
+	Pattern Expression // nil for the wildcard arm `_`
+	Body    *BlockStatement
+}
+
+// String returns a string representation of the match arm
+func (ma *MatchArm) String() string {
+	pattern := "_"
+	if ma.Pattern != nil {
+		pattern = ma.Pattern.String()
+	}
+	return pattern + " => " + ma.Body.String()
+}
+
 // StringLiteral represents a string literal
 type StringLiteral struct {
 	Token Token // the string token
@@ -496,6 +1158,87 @@ func (sl *StringLiteral) String() string {
 	return "\"" + sl.Value + "\""
 }
 
+// InterpolatedString represents a string literal with embedded
+// expressions, such as "Transfer of ${amount} to ${to}"
+type InterpolatedString struct {
+	Token          Token    // the token holding the text before the first expression
+	Parts          []string // the text around the expressions, one more than there are expressions
+	Interpolations []*Interpolation
+}
+
+func (is *InterpolatedString) expressionNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (is *InterpolatedString) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+// String returns a string representation of the interpolated string
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("\"")
+	for idx, part := range is.Parts {
+		out.WriteString(part)
+		if idx < len(is.Interpolations) {
+			out.WriteString(is.Interpolations[idx].String())
+		}
+	}
+	out.WriteString("\"")
+
+	return out.String()
+}
+
+// Interpolation is an expression embedded in a string, with an optional
+// format such as ${balance:08x}
+type Interpolation struct {
+	Expression Expression
+	Format     *Format // nil if the value is written as it is inspected
+}
+
+// String returns a string representation of the interpolation
+func (in *Interpolation) String() string {
+	if in.Format == nil {
+		return "${" + in.Expression.String() + "}"
+	}
+	return "${" + in.Expression.String() + ":" + in.Format.String() + "}"
+}
+
+// Format describes how an interpolated value is written: an optional
+// alignment, padding with zeros and width, then an optional verb. The
+// verbs x, X, o and b write integers in hexadecimal, upper case
+// hexadecimal, octal and binary.
+type Format struct {
+	Token Token  // the FormatSpec token
+	Align string // "<" to align left or ">" to align right; "" for the default, right
+	Zero  bool   // pad integers with zeros after their sign instead of spaces
+	Width int    // the minimum width in characters; 0 for none
+	Verb  string // x, X, o or b; "" to write the value as it is inspected
+}
+
+// String returns a string representation of the format
+func (f *Format) String() string {
+	return f.Token.Literal
+}
+
+// AddressLiteral represents an address literal: 0x followed by 40 hex digits
+type AddressLiteral struct {
+	Token Token  // the token.INT token
+	Value string // the address as written
+}
+
+func (al *AddressLiteral) expressionNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (al *AddressLiteral) TokenLiteral() string {
+	return al.Token.Literal
+}
+
+// String returns a string representation of the address literal
+func (al *AddressLiteral) String() string {
+	return al.Value
+}
+
 // BooleanLiteral represents a boolean literal
 type BooleanLiteral struct {
 	Token Token // the boolean token
@@ -514,6 +1257,23 @@ func (bl *BooleanLiteral) String() string {
 	return bl.Token.Literal
 }
 
+// NullLiteral represents the null literal
+type NullLiteral struct {
+	Token Token // the 'null' token
+}
+
+func (nl *NullLiteral) expressionNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (nl *NullLiteral) TokenLiteral() string {
+	return nl.Token.Literal
+}
+
+// String returns a string representation of the null literal
+func (nl *NullLiteral) String() string {
+	return nl.Token.Literal
+}
+
 // PrefixExpression represents a prefix expression
 type PrefixExpression struct {
 	Token    Token // the prefix token, e.g. !
@@ -570,7 +1330,7 @@ func (ie *InfixExpression) String() string {
 
 // AssignExpression represents an assignment expression
 type AssignExpression struct {
-	Token    Token // the = token
+	Token    Token // the =, +=, -=, *= or /= token
 	Left     Expression
 	Operator string
 	Right    Expression
@@ -594,11 +1354,38 @@ func (ae *AssignExpression) String() string {
 	return out.String()
 }
 
+// PostfixExpression represents a postfix increment or decrement (e.g., i++)
+type PostfixExpression struct {
+	Token    Token // the ++ or -- token
+	Left     Expression
+	Operator string
+}
+
+func (pe *PostfixExpression) expressionNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (pe *PostfixExpression) TokenLiteral() string {
+	return pe.Token.Literal
+}
+
+// String returns a string representation of the postfix expression
+func (pe *PostfixExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(pe.Left.String())
+	out.WriteString(pe.Operator)
+	out.WriteString(")")
+
+	return out.String()
+}
+
 // CallExpression represents a function call expression
 type CallExpression struct {
 	Token     Token // the ( token
 	Function  Expression
 	Arguments []Expression
+	Names     []*Identifier // the parameter each argument is passed to, parallel to Arguments; nil for positional arguments
 }
 
 func (ce *CallExpression) expressionNode() {}
@@ -613,8 +1400,12 @@ func (ce *CallExpression) String() string {
 	var out bytes.Buffer
 
 	args := []string{}
-	for _, a := range ce.Arguments {
-		args = append(args, a.String())
+	for idx, a := range ce.Arguments {
+		if name := ce.ArgumentName(idx); name != nil {
+			args = append(args, name.Value+": "+a.String())
+		} else {
+			args = append(args, a.String())
+		}
 	}
 
 	out.WriteString(ce.Function.String())
@@ -625,11 +1416,21 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+// ArgumentName returns the parameter name an argument is passed to, or nil
+// for a positional argument
+func (ce *CallExpression) ArgumentName(idx int) *Identifier {
+	if ce.Names == nil {
+		return nil
+	}
+	return ce.Names[idx]
+}
+
 // DotExpression represents a dot expression (e.g., obj.property)
 type DotExpression struct {
-	Token Token      // the '.' token
-	Left  Expression // the expression on the left of the dot
-	Right Expression // the identifier on the right of the dot
+	Token    Token      // the '.' or '?.' token
+	Left     Expression // the expression on the left of the dot
+	Right    Expression // the identifier on the right of the dot
+	Optional bool       // true for a safe access (?.), which is null if the left side is
 }
 
 func (de *DotExpression) expressionNode() {}
@@ -645,7 +1446,7 @@ func (de *DotExpression) String() string {
 
 	out.WriteString("(")
 	out.WriteString(de.Left.String())
-	out.WriteString(".")
+	out.WriteString(de.Token.Literal)
 	out.WriteString(de.Right.String())
 	out.WriteString(")")
 
@@ -703,14 +1504,8 @@ func (fl *FunctionLiteral) String() string {
 
 	params := []string{}
 	for _, p := range fl.Parameters {
-		if p != nil {
-			if p.Name != nil {
-				if p.Type != nil {
-					params = append(params, p.String())
-				} else {
-					params = append(params, p.Name.Value)
-				}
-			}
+		if p != nil && p.Name != nil {
+			params = append(params, p.String())
 		}
 	}
 
@@ -762,6 +1557,28 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+// TupleLiteral represents a tuple literal such as `(quotient, remainder)`
+type TupleLiteral struct {
+	Token    Token // the '(' token
+	Elements []Expression
+}
+
+func (tl *TupleLiteral) expressionNode() {}
+
+// TokenLiteral returns the literal of the token associated with the node
+func (tl *TupleLiteral) TokenLiteral() string {
+	return tl.Token.Literal
+}
+
+// String returns a string representation of the tuple literal
+func (tl *TupleLiteral) String() string {
+	elements := []string{}
+	for _, el := range tl.Elements {
+		elements = append(elements, el.String())
+	}
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
 // IndexExpression represents an index expression (e.g., array[index])
 type IndexExpression struct {
 	Token Token      // The [ token
@@ -791,6 +1608,7 @@ func (ie *IndexExpression) String() string {
 type HashLiteral struct {
 	Token Token // the '{' token
 	Pairs map[Expression]Expression
+	Keys  []Expression // the keys of Pairs in source order
 }
 
 func (hl *HashLiteral) expressionNode() {}
@@ -803,8 +1621,8 @@ func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+": "+value.String())
+	for _, key := range hl.Keys {
+		pairs = append(pairs, key.String()+": "+hl.Pairs[key].String())
 	}
 
 	out.WriteString("{")
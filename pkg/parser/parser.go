@@ -2,42 +2,72 @@ package parser
 
 import (
 	"fmt"
+	"github.com/Stremax-Team/stremax-lang/pkg/blockchain"
+	"github.com/Stremax-Team/stremax-lang/pkg/errors"
 	"github.com/Stremax-Team/stremax-lang/pkg/lexer"
+	"math/big"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
 // Precedence levels for operators
 const (
 	_ int = iota
 	LOWEST
+	ASSIGNMENT  // = or +=
+	COALESCE    // ??
 	LOGICAL     // && or ||
 	EQUALS      // ==
 	LESSGREATER // > or <
+	BITOR       // |
+	BITXOR      // ^
+	BITAND      // &
+	SHIFT       // << or >>
 	SUM         // +
-	PRODUCT     // *
-	PREFIX      // -X or !X
-	CALL        // myFunction(X)
+	PRODUCT     // * / %
+	PREFIX      // -X or !X or ~X
+	EXPONENT    // ** (binds tighter than prefix operators, so -2 ** 2 is -(2 ** 2))
+	CALL        // myFunction(X) or X++
 	INDEX       // array[index]
 	DOT         // obj.property
 )
 
 // Operator precedence map
 var precedences = map[lexer.TokenType]int{
-	lexer.EQ:         EQUALS,
-	lexer.NotEq:      EQUALS,
-	lexer.LT:         LESSGREATER,
-	lexer.GT:         LESSGREATER,
-	lexer.LessEq:     LESSGREATER,
-	lexer.GreaterEq:  LESSGREATER,
-	lexer.PLUS:       SUM,
-	lexer.MINUS:      SUM,
-	lexer.SLASH:      PRODUCT,
-	lexer.ASTERISK:   PRODUCT,
-	lexer.AND:        LOGICAL,
-	lexer.OR:         LOGICAL,
-	lexer.LPAREN:     CALL,
-	lexer.LBRACKET:   INDEX,
-	lexer.DOT:        DOT,
+	lexer.ASSIGN:         ASSIGNMENT,
+	lexer.PlusAssign:     ASSIGNMENT,
+	lexer.MinusAssign:    ASSIGNMENT,
+	lexer.AsteriskAssign: ASSIGNMENT,
+	lexer.SlashAssign:    ASSIGNMENT,
+	lexer.PercentAssign:  ASSIGNMENT,
+	lexer.EQ:             EQUALS,
+	lexer.NotEq:          EQUALS,
+	lexer.LT:             LESSGREATER,
+	lexer.GT:             LESSGREATER,
+	lexer.LessEq:         LESSGREATER,
+	lexer.GreaterEq:      LESSGREATER,
+	lexer.IN:             LESSGREATER,
+	lexer.PLUS:           SUM,
+	lexer.MINUS:          SUM,
+	lexer.SLASH:          PRODUCT,
+	lexer.ASTERISK:       PRODUCT,
+	lexer.PERCENT:        PRODUCT,
+	lexer.Power:          EXPONENT,
+	lexer.PIPE:           BITOR,
+	lexer.CARET:          BITXOR,
+	lexer.AMPERSAND:      BITAND,
+	lexer.ShiftLeft:      SHIFT,
+	lexer.ShiftRight:     SHIFT,
+	lexer.NullCoalesce:   COALESCE,
+	lexer.AND:            LOGICAL,
+	lexer.OR:             LOGICAL,
+	lexer.LPAREN:         CALL,
+	lexer.Increment:      CALL,
+	lexer.Decrement:      CALL,
+	lexer.LBRACKET:       INDEX,
+	lexer.DOT:            DOT,
+	lexer.SafeDot:        DOT,
 }
 
 // Parser represents a parser for Stremax-Lang.
@@ -48,9 +78,18 @@ var precedences = map[lexer.TokenType]int{
 type Parser struct {
 	l         *lexer.Lexer
 	errors    []string
+
+	// syntaxErrors holds the errors that carry a source position; each is
+	// also listed in errors
+	syntaxErrors []*errors.Error
+	file         string // the file syntax errors are reported against, if known
 	curToken  lexer.Token
 	peekToken lexer.Token
 
+	// loopDepth counts the loops enclosing the current token within the
+	// current function, so break and continue can be rejected outside loops
+	loopDepth int
+
 	prefixParseFns map[lexer.TokenType]prefixParseFn
 	infixParseFns  map[lexer.TokenType]infixParseFn
 }
@@ -82,14 +121,19 @@ func New(l *lexer.Lexer) *Parser {
 	// Register prefix parse functions
 	p.prefixParseFns = make(map[lexer.TokenType]prefixParseFn)
 	p.registerPrefix(lexer.IDENT, p.parseIdentifier)
+	p.registerPrefix(lexer.ADDRESS, p.parseTypeName)
 	p.registerPrefix(lexer.INT, p.parseIntegerLiteral)
 	p.registerPrefix(lexer.STRING, p.parseStringLiteral)
+	p.registerPrefix(lexer.StringStart, p.parseInterpolatedString)
 	p.registerPrefix(lexer.TRUE, p.parseBooleanLiteral)
 	p.registerPrefix(lexer.FALSE, p.parseBooleanLiteral)
+	p.registerPrefix(lexer.NULL, p.parseNullLiteral)
 	p.registerPrefix(lexer.BANG, p.parsePrefixExpression)
 	p.registerPrefix(lexer.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(lexer.TILDE, p.parsePrefixExpression)
 	p.registerPrefix(lexer.LPAREN, p.parseGroupedExpression)
 	p.registerPrefix(lexer.IF, p.parseIfExpression)
+	p.registerPrefix(lexer.MATCH, p.parseMatchExpression)
 	p.registerPrefix(lexer.FUNCTION, p.parseFunctionLiteral)
 	p.registerPrefix(lexer.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(lexer.LBRACE, p.parseHashLiteral)
@@ -100,17 +144,35 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(lexer.MINUS, p.parseInfixExpression)
 	p.registerInfix(lexer.SLASH, p.parseInfixExpression)
 	p.registerInfix(lexer.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(lexer.PERCENT, p.parseInfixExpression)
+	p.registerInfix(lexer.Power, p.parseInfixExpression)
+	p.registerInfix(lexer.PIPE, p.parseInfixExpression)
+	p.registerInfix(lexer.CARET, p.parseInfixExpression)
+	p.registerInfix(lexer.AMPERSAND, p.parseInfixExpression)
+	p.registerInfix(lexer.ShiftLeft, p.parseInfixExpression)
+	p.registerInfix(lexer.ShiftRight, p.parseInfixExpression)
 	p.registerInfix(lexer.EQ, p.parseInfixExpression)
 	p.registerInfix(lexer.NotEq, p.parseInfixExpression)
 	p.registerInfix(lexer.LT, p.parseInfixExpression)
 	p.registerInfix(lexer.GT, p.parseInfixExpression)
 	p.registerInfix(lexer.LessEq, p.parseInfixExpression)
 	p.registerInfix(lexer.GreaterEq, p.parseInfixExpression)
+	p.registerInfix(lexer.IN, p.parseInfixExpression)
+	p.registerInfix(lexer.NullCoalesce, p.parseInfixExpression)
 	p.registerInfix(lexer.AND, p.parseInfixExpression)
 	p.registerInfix(lexer.OR, p.parseInfixExpression)
 	p.registerInfix(lexer.LPAREN, p.parseCallExpression)
 	p.registerInfix(lexer.LBRACKET, p.parseIndexExpression)
 	p.registerInfix(lexer.DOT, p.parseDotExpression)
+	p.registerInfix(lexer.SafeDot, p.parseDotExpression)
+	p.registerInfix(lexer.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(lexer.PlusAssign, p.parseAssignExpression)
+	p.registerInfix(lexer.MinusAssign, p.parseAssignExpression)
+	p.registerInfix(lexer.AsteriskAssign, p.parseAssignExpression)
+	p.registerInfix(lexer.SlashAssign, p.parseAssignExpression)
+	p.registerInfix(lexer.PercentAssign, p.parseAssignExpression)
+	p.registerInfix(lexer.Increment, p.parsePostfixExpression)
+	p.registerInfix(lexer.Decrement, p.parsePostfixExpression)
 
 	return p
 }
@@ -125,6 +187,24 @@ func (p *Parser) Errors() []string {
 	return p.errors
 }
 
+// SyntaxErrors returns the errors encountered during parsing that carry a
+// source position, including malformed tokens found by the lexer
+func (p *Parser) SyntaxErrors() []*errors.Error {
+	return p.syntaxErrors
+}
+
+// SetFile sets the file that syntax errors are reported against
+func (p *Parser) SetFile(file string) {
+	p.file = file
+}
+
+// syntaxError adds an error positioned at the given token
+func (p *Parser) syntaxError(msg string, tok lexer.Token) {
+	err := errors.NewSyntaxError(msg, tok.Line, tok.Column, p.file)
+	p.syntaxErrors = append(p.syntaxErrors, err)
+	p.errors = append(p.errors, err.Error())
+}
+
 // nextToken advances both curToken and peekToken
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
@@ -145,21 +225,38 @@ func (p *Parser) ParseProgram() *Program {
 		Statements: []Statement{},
 	}
 
+	// Pragmas set the mode of the whole file, so they come first
+	pragmas := true
+
 	for !p.curTokenIs(lexer.EOF) {
 		stmt := p.parseStatement()
+		if pragma, ok := stmt.(*PragmaStatement); !ok {
+			pragmas = false
+		} else if !pragmas && pragma != nil {
+			p.syntaxError(fmt.Sprintf("pragma %s must come before the other statements of the file", pragma.Name.Value), pragma.Token)
+		}
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
 		p.nextToken()
 	}
 
+	// Malformed tokens are reported ahead of the parse errors they cause
+	lexErrors := []string{}
+	for _, err := range p.l.Errors() {
+		err.File = p.file
+		lexErrors = append(lexErrors, err.Error())
+	}
+	p.errors = append(lexErrors, p.errors...)
+	p.syntaxErrors = append(p.l.Errors(), p.syntaxErrors...)
+
 	return program
 }
 
 // parseStatement parses a statement
 func (p *Parser) parseStatement() Statement {
 	switch p.curToken.Type {
-	case lexer.LET:
+	case lexer.LET, lexer.CONST:
 		return p.parseLetStatement()
 	case lexer.RETURN:
 		return p.parseReturnStatement()
@@ -171,25 +268,78 @@ func (p *Parser) parseStatement() Statement {
 		return p.parseConstructorStatement()
 	case lexer.EVENT:
 		return p.parseEventStatement()
+	case lexer.STRUCT:
+		return p.parseStructStatement()
+	case lexer.ENUM:
+		return p.parseEnumStatement()
+	case lexer.IMPORT:
+		return p.parseImportStatement()
 	case lexer.REQUIRE:
 		return p.parseRequireStatement()
 	case lexer.EMIT:
 		return p.parseEmitStatement()
+	case lexer.ERROR:
+		return p.parseErrorStatement()
+	case lexer.REVERT:
+		return p.parseRevertStatement()
+	case lexer.ASSERT:
+		return p.parseAssertStatement()
+	case lexer.TRY:
+		return p.parseTryStatement()
+	case lexer.WHILE:
+		return p.parseWhileStatement()
+	case lexer.FOR:
+		return p.parseForStatement()
+	case lexer.BREAK:
+		return p.parseBreakStatement()
+	case lexer.CONTINUE:
+		return p.parseContinueStatement()
+	case lexer.PRAGMA:
+		return p.parsePragmaStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
 }
 
-// parseLetStatement parses a let statement
-func (p *Parser) parseLetStatement() *LetStatement {
-	stmt := &LetStatement{Token: p.curToken}
+// parsePragmaStatement parses a pragma statement, such as pragma strict;
+func (p *Parser) parsePragmaStatement() *PragmaStatement {
+	stmt := &PragmaStatement{Token: p.curToken}
 
 	if !p.expectPeek(lexer.IDENT) {
 		return nil
 	}
-
 	stmt.Name = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
+	if p.peekTokenIs(lexer.SEMICOLON) {
+		p.nextToken()
+	}
+
+	if stmt.Name.Value != "strict" {
+		p.syntaxError(fmt.Sprintf("unknown pragma %s", stmt.Name.Value), stmt.Name.Token)
+		return nil
+	}
+
+	return stmt
+}
+
+// parseLetStatement parses a let or const statement
+func (p *Parser) parseLetStatement() *LetStatement {
+	stmt := &LetStatement{Token: p.curToken, Constant: p.curTokenIs(lexer.CONST)}
+
+	if p.peekTokenIs(lexer.LPAREN) || p.peekTokenIs(lexer.LBRACKET) || p.peekTokenIs(lexer.LBRACE) {
+		p.nextToken()
+		stmt.Pattern = p.parseDestructuringPattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+
+		stmt.Name = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
 	// Check for type annotation
 	if p.peekTokenIs(lexer.COLON) {
 		p.nextToken() // Skip the colon
@@ -204,6 +354,7 @@ func (p *Parser) parseLetStatement() *LetStatement {
 	p.nextToken()
 
 	stmt.Value = p.parseExpression(LOWEST)
+	p.checkLiteralFits(stmt.Value, stmt.Type)
 
 	if p.peekTokenIs(lexer.SEMICOLON) {
 		p.nextToken()
@@ -212,6 +363,53 @@ func (p *Parser) parseLetStatement() *LetStatement {
 	return stmt
 }
 
+// parseDestructuringPattern parses the names bound by a destructuring let:
+// (a, b) for a tuple, [a, b] for an array or { x, y: py } for a struct
+func (p *Parser) parseDestructuringPattern() *DestructuringPattern {
+	pattern := &DestructuringPattern{Token: p.curToken}
+
+	var end lexer.TokenType = lexer.RPAREN
+	switch {
+	case p.curTokenIs(lexer.LBRACKET):
+		end = lexer.RBRACKET
+	case p.curTokenIs(lexer.LBRACE):
+		end = lexer.RBRACE
+		pattern.Fields = []*Identifier{}
+	}
+
+	for !p.peekTokenIs(end) {
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+		name := &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if pattern.Fields != nil {
+			// A struct field is bound to its own name unless another is given
+			pattern.Fields = append(pattern.Fields, name)
+			if p.peekTokenIs(lexer.COLON) {
+				p.nextToken()
+				if !p.expectPeek(lexer.IDENT) {
+					return nil
+				}
+				name = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			}
+		}
+		pattern.Names = append(pattern.Names, name)
+
+		if !p.peekTokenIs(end) && !p.expectPeek(lexer.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken()
+
+	if len(pattern.Names) == 0 {
+		p.syntaxError("a destructuring pattern must bind at least one name", pattern.Token)
+		return nil
+	}
+
+	return pattern
+}
+
 // parseReturnStatement parses a return statement
 func (p *Parser) parseReturnStatement() *ReturnStatement {
 	stmt := &ReturnStatement{Token: p.curToken}
@@ -274,11 +472,123 @@ func (p *Parser) parseStateBlockStatement() *StateBlockStatement {
 		return nil
 	}
 
-	stmt.Body = p.parseBlockStatement()
+	p.nextToken()
+
+	for !p.curTokenIs(lexer.RBRACE) && !p.curTokenIs(lexer.EOF) {
+		field := p.parseStateFieldStatement()
+		if field == nil {
+			return nil
+		}
+		stmt.Fields = append(stmt.Fields, field)
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseImportStatement parses an import statement. The words 'from' and
+// 'as' are only special here, so they remain usable as identifiers.
+func (p *Parser) parseImportStatement() *ImportStatement {
+	stmt := &ImportStatement{Token: p.curToken}
+
+	if p.peekTokenIs(lexer.LBRACE) {
+		p.nextToken()
+
+		for !p.peekTokenIs(lexer.RBRACE) {
+			if !p.expectPeek(lexer.IDENT) {
+				return nil
+			}
+			stmt.Names = append(stmt.Names, &Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+			if !p.peekTokenIs(lexer.RBRACE) && !p.expectPeek(lexer.COMMA) {
+				return nil
+			}
+		}
+		p.nextToken()
+
+		if len(stmt.Names) == 0 {
+			p.syntaxError("import list must name at least one export", stmt.Token)
+			return nil
+		}
+
+		if !p.peekTokenIs(lexer.IDENT) || p.peekToken.Literal != "from" {
+			p.syntaxError(fmt.Sprintf("expected 'from' after import list, got %s", p.peekToken.Literal), p.peekToken)
+			return nil
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(lexer.STRING) {
+		return nil
+	}
+	stmt.Path = &StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(lexer.IDENT) && p.peekToken.Literal == "as" {
+		if len(stmt.Names) > 0 {
+			p.syntaxError("cannot rename a module when importing selected names", p.peekToken)
+			return nil
+		}
+		p.nextToken()
+
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+		stmt.Alias = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if p.peekTokenIs(lexer.SEMICOLON) {
+		p.nextToken()
+	}
 
 	return stmt
 }
 
+// parseStateFieldStatement parses a state field declaration such as
+// `decimals: Uint8 = 18`, optionally marked const or immutable. Fields may
+// be separated by newlines, semicolons or commas.
+func (p *Parser) parseStateFieldStatement() *StateFieldStatement {
+	field := &StateFieldStatement{}
+	if p.curTokenIs(lexer.CONST) || p.curTokenIs(lexer.IMMUTABLE) {
+		field.Constant = p.curTokenIs(lexer.CONST)
+		field.Immutable = p.curTokenIs(lexer.IMMUTABLE)
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(lexer.IDENT) {
+		msg := fmt.Sprintf("expected state field name, got %s instead", p.curToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	field.Token = p.curToken
+	field.Name = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(lexer.COLON) {
+		return nil
+	}
+
+	p.nextToken()
+	field.Type = p.parseTypeExpression()
+	if field.Type == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(lexer.ASSIGN) {
+		p.nextToken()
+		p.nextToken()
+		field.Value = p.parseExpression(LOWEST)
+		p.checkLiteralFits(field.Value, field.Type)
+	} else if field.Constant {
+		p.syntaxError(fmt.Sprintf("constant %s must have an initializer", field.Name.Value), field.Token)
+	}
+
+	if p.peekTokenIs(lexer.SEMICOLON) || p.peekTokenIs(lexer.COMMA) {
+		p.nextToken()
+	}
+
+	return field
+}
+
 // parseFunctionStatement parses a function statement
 func (p *Parser) parseFunctionStatement() *FunctionStatement {
 	stmt := &FunctionStatement{Token: p.curToken}
@@ -289,6 +599,15 @@ func (p *Parser) parseFunctionStatement() *FunctionStatement {
 
 	stmt.Name = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
+	// Type parameters of a generic function (e.g., function max<T>(...))
+	if p.peekTokenIs(lexer.LT) {
+		p.nextToken()
+		stmt.TypeParameters = p.parseTypeParameters()
+		if stmt.TypeParameters == nil {
+			return nil
+		}
+	}
+
 	if !p.expectPeek(lexer.LPAREN) {
 		return nil
 	}
@@ -306,7 +625,7 @@ func (p *Parser) parseFunctionStatement() *FunctionStatement {
 		return nil
 	}
 
-	stmt.Body = p.parseBlockStatement()
+	stmt.Body = p.parseFunctionBody()
 
 	return stmt
 }
@@ -325,7 +644,106 @@ func (p *Parser) parseConstructorStatement() *ConstructorStatement {
 		return nil
 	}
 
-	stmt.Body = p.parseBlockStatement()
+	stmt.Body = p.parseFunctionBody()
+
+	return stmt
+}
+
+// parseStructStatement parses a struct declaration such as
+// `struct Proposal { description: String, votes: Int }`. Fields may be
+// separated by commas, semicolons or newlines.
+func (p *Parser) parseStructStatement() *StructStatement {
+	stmt := &StructStatement{Token: p.curToken}
+
+	if !p.expectPeek(lexer.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+
+	p.nextToken()
+
+	for !p.curTokenIs(lexer.RBRACE) && !p.curTokenIs(lexer.EOF) {
+		if !p.curTokenIs(lexer.IDENT) {
+			msg := fmt.Sprintf("expected struct field name, got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+
+		field := &ParameterStatement{
+			Token: p.curToken,
+			Name:  &Identifier{Token: p.curToken, Value: p.curToken.Literal},
+		}
+
+		if !p.expectPeek(lexer.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+		field.Type = p.parseTypeExpression()
+		if field.Type == nil {
+			return nil
+		}
+		stmt.Fields = append(stmt.Fields, field)
+
+		if p.peekTokenIs(lexer.SEMICOLON) || p.peekTokenIs(lexer.COMMA) {
+			p.nextToken()
+		}
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(lexer.RBRACE) {
+		p.errors = append(p.errors, fmt.Sprintf("unterminated struct %s", stmt.Name.Value))
+		return nil
+	}
+
+	return stmt
+}
+
+// parseEnumStatement parses an enum declaration such as `enum Phase { Open, Ended }`
+func (p *Parser) parseEnumStatement() *EnumStatement {
+	stmt := &EnumStatement{Token: p.curToken}
+
+	if !p.expectPeek(lexer.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for !p.peekTokenIs(lexer.RBRACE) {
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+
+		variant := &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if seen[variant.Value] {
+			p.syntaxError(fmt.Sprintf("duplicate variant %s in enum %s", variant.Value, stmt.Name.Value), variant.Token)
+		}
+		seen[variant.Value] = true
+
+		stmt.Variants = append(stmt.Variants, variant)
+
+		if !p.peekTokenIs(lexer.RBRACE) && !p.expectPeek(lexer.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACE) {
+		return nil
+	}
+
+	if len(stmt.Variants) == 0 {
+		p.syntaxError(fmt.Sprintf("enum %s must have at least one variant", stmt.Name.Value), stmt.Name.Token)
+	}
 
 	return stmt
 }
@@ -424,11 +842,30 @@ func (p *Parser) parseEmitStatement() *EmitStatement {
 	return stmt
 }
 
-// parseExpressionStatement parses an expression statement
-func (p *Parser) parseExpressionStatement() *ExpressionStatement {
-	stmt := &ExpressionStatement{Token: p.curToken}
+// parseErrorStatement parses a custom error declaration such as
+// `error InsufficientBalance(needed: Int, available: Int)`
+func (p *Parser) parseErrorStatement() *ErrorStatement {
+	stmt := &ErrorStatement{Token: p.curToken}
 
-	stmt.Expression = p.parseExpression(LOWEST)
+	if !p.expectPeek(lexer.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(lexer.LPAREN) {
+		return nil
+	}
+
+	stmt.Fields = p.parseParameters()
+	if stmt.Fields == nil {
+		return nil
+	}
+	for _, field := range stmt.Fields {
+		if field.Variadic {
+			p.syntaxError(fmt.Sprintf("error field %s cannot be variadic", field.Name.Value), field.Token)
+		}
+	}
 
 	if p.peekTokenIs(lexer.SEMICOLON) {
 		p.nextToken()
@@ -437,66 +874,653 @@ func (p *Parser) parseExpressionStatement() *ExpressionStatement {
 	return stmt
 }
 
-// parseBlockStatement parses a block statement
-func (p *Parser) parseBlockStatement() *BlockStatement {
-	block := &BlockStatement{Token: p.curToken}
-	block.Statements = []Statement{}
+// parseRevertStatement parses a revert statement such as
+// `revert InsufficientBalance(amount, balance)` or `revert "Paused"`
+func (p *Parser) parseRevertStatement() *RevertStatement {
+	stmt := &RevertStatement{Token: p.curToken}
 
 	p.nextToken()
+	stmt.Error = p.parseExpression(LOWEST)
+	if stmt.Error == nil {
+		return nil
+	}
 
-	for !p.curTokenIs(lexer.RBRACE) && !p.curTokenIs(lexer.EOF) {
-		stmt := p.parseStatement()
-		if stmt != nil {
-			block.Statements = append(block.Statements, stmt)
-		}
+	if p.peekTokenIs(lexer.SEMICOLON) {
 		p.nextToken()
 	}
 
-	return block
+	return stmt
 }
 
-// parseExpression parses an expression
-func (p *Parser) parseExpression(precedence int) Expression {
-	prefix := p.prefixParseFns[p.curToken.Type]
-	if prefix == nil {
-		p.noPrefixParseFnError(p.curToken.Type)
+// parseAssertStatement parses an assert statement, whose message is optional
+func (p *Parser) parseAssertStatement() *AssertStatement {
+	stmt := &AssertStatement{Token: p.curToken}
+
+	if !p.expectPeek(lexer.LPAREN) {
 		return nil
 	}
-	leftExp := prefix()
 
-	for !p.peekTokenIs(lexer.SEMICOLON) && precedence < p.peekPrecedence() {
-		infix := p.infixParseFns[p.peekToken.Type]
-		if infix == nil {
-			return leftExp
-		}
+	p.nextToken()
+	stmt.Condition = p.parseExpression(LOWEST)
 
+	if p.peekTokenIs(lexer.COMMA) {
 		p.nextToken()
+		p.nextToken()
+		stmt.Message = p.parseExpression(LOWEST)
+	}
 
-		leftExp = infix(leftExp)
+	if !p.expectPeek(lexer.RPAREN) {
+		return nil
 	}
 
-	return leftExp
-}
+	if p.peekTokenIs(lexer.SEMICOLON) {
+		p.nextToken()
+	}
 
-// parseIdentifier parses an identifier
+	return stmt
+}
+
+// parseTryStatement parses a try statement: a call, optionally naming its
+// result with 'as', the block run when it succeeds and one or more catch
+// clauses. As in imports, 'as' is only special here.
+func (p *Parser) parseTryStatement() *TryStatement {
+	stmt := &TryStatement{Token: p.curToken}
+
+	p.nextToken()
+	call, ok := p.parseExpression(LOWEST).(*CallExpression)
+	if !ok {
+		p.syntaxError("try must be followed by a contract call, such as try token.transfer(to, 5) { ... }", stmt.Token)
+		return nil
+	}
+	stmt.Call = call
+
+	if p.peekTokenIs(lexer.IDENT) && p.peekToken.Literal == "as" {
+		p.nextToken()
+
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+		stmt.Result = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseBlockStatement()
+
+	for p.peekTokenIs(lexer.CATCH) {
+		p.nextToken()
+
+		clause := p.parseCatchClause()
+		if clause == nil {
+			return nil
+		}
+		if n := len(stmt.Catches); n > 0 && stmt.Catches[n-1].ErrorName == nil {
+			p.syntaxError("catch clause after a clause that catches every failure is never reached", clause.Token)
+			return nil
+		}
+		stmt.Catches = append(stmt.Catches, clause)
+	}
+
+	if len(stmt.Catches) == 0 {
+		p.syntaxError("try must have at least one catch clause", stmt.Token)
+		return nil
+	}
+
+	return stmt
+}
+
+// parseCatchClause parses a catch clause: `catch Name(a, b) { ... }`,
+// `catch (e) { ... }` or `catch { ... }`
+func (p *Parser) parseCatchClause() *CatchClause {
+	clause := &CatchClause{Token: p.curToken}
+
+	if p.peekTokenIs(lexer.IDENT) {
+		p.nextToken()
+		clause.ErrorName = &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if p.peekTokenIs(lexer.LPAREN) {
+		p.nextToken()
+
+		for !p.peekTokenIs(lexer.RPAREN) {
+			if !p.expectPeek(lexer.IDENT) {
+				return nil
+			}
+			clause.Parameters = append(clause.Parameters, &Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+			if !p.peekTokenIs(lexer.RPAREN) && !p.expectPeek(lexer.COMMA) {
+				return nil
+			}
+		}
+		p.nextToken()
+
+		if clause.ErrorName == nil && len(clause.Parameters) != 1 {
+			p.syntaxError("a catch clause without an error name takes one parameter", clause.Token)
+			return nil
+		}
+	} else if clause.ErrorName != nil {
+		p.peekError(lexer.LPAREN)
+		return nil
+	}
+
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+	clause.Body = p.parseBlockStatement()
+
+	return clause
+}
+
+// parseWhileStatement parses a while loop (e.g., while (x < 10) { x += 1; })
+func (p *Parser) parseWhileStatement() *WhileStatement {
+	stmt := &WhileStatement{Token: p.curToken}
+
+	if !p.expectPeek(lexer.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(lexer.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
+	return stmt
+}
+
+// parseForStatement parses a C-style for loop (e.g., for (let i = 0; i < n; i += 1) { ... })
+// or a for-in loop (e.g., for (key, value in balances) { ... })
+func (p *Parser) parseForStatement() Statement {
+	stmt := &ForStatement{Token: p.curToken}
+
+	if !p.expectPeek(lexer.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+
+	if p.curTokenIs(lexer.IDENT) && (p.peekTokenIs(lexer.IN) || p.peekTokenIs(lexer.COMMA)) {
+		return p.parseForInStatement(stmt.Token)
+	}
+
+	// Init clause
+	if !p.curTokenIs(lexer.SEMICOLON) {
+		if p.curTokenIs(lexer.LET) || p.curTokenIs(lexer.CONST) {
+			init := p.parseLetStatement()
+			if init == nil {
+				return nil
+			}
+			stmt.Init = init
+		} else {
+			stmt.Init = &ExpressionStatement{Token: p.curToken, Expression: p.parseExpression(LOWEST)}
+			p.nextToken()
+		}
+
+		if !p.curTokenIs(lexer.SEMICOLON) {
+			msg := fmt.Sprintf("expected ; after for loop initializer, got %s instead", p.curToken.Type)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+	}
+
+	p.nextToken()
+
+	// Condition clause
+	if !p.curTokenIs(lexer.SEMICOLON) {
+		stmt.Condition = p.parseExpression(LOWEST)
+
+		if !p.expectPeek(lexer.SEMICOLON) {
+			return nil
+		}
+	}
+
+	p.nextToken()
+
+	// Update clause
+	if !p.curTokenIs(lexer.RPAREN) {
+		stmt.Update = p.parseExpression(LOWEST)
+
+		if !p.expectPeek(lexer.RPAREN) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
+	return stmt
+}
+
+// parseForInStatement parses the remainder of a for-in loop, starting at its first variable
+func (p *Parser) parseForInStatement(token lexer.Token) Statement {
+	stmt := &ForInStatement{Token: token}
+
+	stmt.Variables = append(stmt.Variables, &Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+	if p.peekTokenIs(lexer.COMMA) {
+		p.nextToken()
+
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+
+		stmt.Variables = append(stmt.Variables, &Identifier{Token: p.curToken, Value: p.curToken.Literal})
+	}
+
+	if !p.expectPeek(lexer.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(lexer.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
+	return stmt
+}
+
+// parseLoopBody parses the block statement forming the body of a loop
+func (p *Parser) parseLoopBody() *BlockStatement {
+	p.loopDepth++
+	body := p.parseBlockStatement()
+	p.loopDepth--
+
+	return body
+}
+
+// parseBreakStatement parses a break statement
+func (p *Parser) parseBreakStatement() *BreakStatement {
+	stmt := &BreakStatement{Token: p.curToken}
+
+	if p.loopDepth == 0 {
+		p.errors = append(p.errors, "break statement outside of loop")
+	}
+
+	if p.peekTokenIs(lexer.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseContinueStatement parses a continue statement
+func (p *Parser) parseContinueStatement() *ContinueStatement {
+	stmt := &ContinueStatement{Token: p.curToken}
+
+	if p.loopDepth == 0 {
+		p.errors = append(p.errors, "continue statement outside of loop")
+	}
+
+	if p.peekTokenIs(lexer.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseFunctionBody parses the body of a function. Loops enclosing the
+// function do not extend into it, so break and continue must appear in a
+// loop of the function itself.
+func (p *Parser) parseFunctionBody() *BlockStatement {
+	loopDepth := p.loopDepth
+	p.loopDepth = 0
+	body := p.parseBlockStatement()
+	p.loopDepth = loopDepth
+
+	return body
+}
+
+// parseExpressionStatement parses an expression statement
+func (p *Parser) parseExpressionStatement() *ExpressionStatement {
+	stmt := &ExpressionStatement{Token: p.curToken}
+
+	stmt.Expression = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(lexer.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseBlockStatement parses a block statement
+func (p *Parser) parseBlockStatement() *BlockStatement {
+	block := &BlockStatement{Token: p.curToken}
+	block.Statements = []Statement{}
+
+	p.nextToken()
+
+	for !p.curTokenIs(lexer.RBRACE) && !p.curTokenIs(lexer.EOF) {
+		stmt := p.parseStatement()
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		p.nextToken()
+	}
+
+	return block
+}
+
+// parseExpression parses an expression
+func (p *Parser) parseExpression(precedence int) Expression {
+	prefix := p.prefixParseFns[p.curToken.Type]
+	if prefix == nil {
+		p.noPrefixParseFnError(p.curToken.Type)
+		return nil
+	}
+	leftExp := prefix()
+
+	for !p.peekTokenIs(lexer.SEMICOLON) && precedence < p.peekPrecedence() {
+		infix := p.infixParseFns[p.peekToken.Type]
+		if infix == nil {
+			return leftExp
+		}
+
+		p.nextToken()
+
+		leftExp = infix(leftExp)
+	}
+
+	return leftExp
+}
+
+// parseMatchExpression parses a match expression:
+//
+//	match (subject) { Pattern => expression, Pattern => { block }, _ => ... }
+//
+// Arms are separated by optional commas; `_` matches anything.
+func (p *Parser) parseMatchExpression() Expression {
+	expr := &MatchExpression{Token: p.curToken}
+
+	if !p.expectPeek(lexer.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	expr.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(lexer.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(lexer.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(lexer.RBRACE) && !p.peekTokenIs(lexer.EOF) {
+		p.nextToken()
+
+		arm := &MatchArm{Token: p.curToken}
+		if !(p.curTokenIs(lexer.IDENT) && p.curToken.Literal == "_") {
+			arm.Pattern = p.parseExpression(LOWEST)
+		}
+
+		if !p.expectPeek(lexer.FatArrow) {
+			return nil
+		}
+
+		if p.peekTokenIs(lexer.LBRACE) {
+			p.nextToken()
+			arm.Body = p.parseBlockStatement()
+		} else {
+			p.nextToken()
+			value := &ExpressionStatement{Token: p.curToken, Expression: p.parseExpression(LOWEST)}
+			arm.Body = &BlockStatement{Token: arm.Token, Statements: []Statement{value}}
+		}
+
+		expr.Arms = append(expr.Arms, arm)
+
+		if p.peekTokenIs(lexer.COMMA) {
+			p.nextToken()
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACE) {
+		return nil
+	}
+
+	return expr
+}
+
+// parseIdentifier parses an identifier. A capitalized name directly
+// followed by a brace starts a struct literal.
 func (p *Parser) parseIdentifier() Expression {
+	ident := &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(lexer.LBRACE) && isTypeName(ident.Value) {
+		return p.parseStructLiteral(ident)
+	}
+
+	return ident
+}
+
+// isTypeName reports whether a name follows the convention for type names:
+// it starts with an upper-case letter
+func isTypeName(name string) bool {
+	return name != "" && unicode.IsUpper([]rune(name)[0])
+}
+
+// parseStructLiteral parses a struct literal such as
+// `Proposal { description: "Fund it", votes: 0 }`
+func (p *Parser) parseStructLiteral(name *Identifier) Expression {
+	lit := &StructLiteral{Token: p.curToken, Name: name}
+
+	p.nextToken() // move to the {
+
+	for !p.peekTokenIs(lexer.RBRACE) {
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+		field := &Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if !p.expectPeek(lexer.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+
+		lit.Fields = append(lit.Fields, field)
+		lit.Values = append(lit.Values, value)
+
+		if !p.peekTokenIs(lexer.RBRACE) && !p.expectPeek(lexer.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(lexer.RBRACE) {
+		return nil
+	}
+
+	return lit
+}
+
+// maxIntegerLiteral is the largest integer literal, the maximum of Uint256
+var maxIntegerLiteral = integerRanges["Uint256"].max
+
+// parseIntegerLiteral parses an integer literal: decimal, 0x-prefixed hex,
+// with underscore separators, or with an exponent such as 1e18. A hex
+// literal of exactly 40 digits is an address literal; one of 39 or 41
+// digits is most likely a mistyped address, so it must use separators to be
+// read as an integer.
+func (p *Parser) parseIntegerLiteral() Expression {
+	if isAddressLiteral(p.curToken.Literal) {
+		if _, err := blockchain.ParseAddress(p.curToken.Literal); err != nil {
+			p.syntaxError(err.Error(), p.curToken)
+			return nil
+		}
+		return &AddressLiteral{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if n := hexDigits(p.curToken.Literal); n == 39 || n == 41 {
+		p.syntaxError(fmt.Sprintf("hex literal %s has %d digits, but an address has 40; use _ separators to write an integer", p.curToken.Literal, n), p.curToken)
+		return nil
+	}
+
+	lit := &IntegerLiteral{Token: p.curToken}
+
+	value, ok := parseIntegerValue(p.curToken.Literal)
+	if !ok {
+		p.syntaxError(fmt.Sprintf("could not parse %q as integer", p.curToken.Literal), p.curToken)
+		return nil
+	}
+
+	if value.Cmp(maxIntegerLiteral) > 0 {
+		p.syntaxError(fmt.Sprintf("Integer literal out of range: %s", p.curToken.Literal), p.curToken)
+		return nil
+	}
+
+	lit.Value = value
+
+	return lit
+}
+
+// isAddressLiteral reports whether a number literal is 0x followed by 40
+// hex digits
+func isAddressLiteral(literal string) bool {
+	return hexDigits(literal) == 40
+}
+
+// hexDigits returns the number of digits of a hex literal written without
+// separators, or 0 if the literal is not one
+func hexDigits(literal string) int {
+	if !(strings.HasPrefix(literal, "0x") || strings.HasPrefix(literal, "0X")) || strings.Contains(literal, "_") {
+		return 0
+	}
+	if _, ok := new(big.Int).SetString(literal[2:], 16); !ok {
+		return 0
+	}
+	return len(literal) - 2
+}
+
+// parseTypeName parses a type keyword used as an expression, such as the
+// Address in Address(0)
+func (p *Parser) parseTypeName() Expression {
 	return &Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
 
-// parseIntegerLiteral parses an integer literal
-func (p *Parser) parseIntegerLiteral() Expression {
-	lit := &IntegerLiteral{Token: p.curToken}
+// parseIntegerValue converts the text of an integer literal to its value
+func parseIntegerValue(literal string) (*big.Int, bool) {
+	literal = strings.ReplaceAll(literal, "_", "")
+
+	if strings.HasPrefix(literal, "0x") || strings.HasPrefix(literal, "0X") {
+		return new(big.Int).SetString(literal[2:], 16)
+	}
+
+	mantissa, exponent := literal, ""
+	if idx := strings.IndexAny(literal, "eE"); idx >= 0 {
+		mantissa, exponent = literal[:idx], literal[idx+1:]
+	}
+
+	value, ok := new(big.Int).SetString(mantissa, 10)
+	if !ok {
+		return nil, false
+	}
+	if exponent == "" || value.Sign() == 0 {
+		return value, true
+	}
+
+	exp, ok := new(big.Int).SetString(exponent, 10)
+	if !ok {
+		return nil, false
+	}
+	// Any larger power of ten is out of range; stop before computing it
+	if exp.Cmp(big.NewInt(78)) > 0 {
+		exp.SetInt64(78)
+	}
+
+	return value.Mul(value, new(big.Int).Exp(big.NewInt(10), exp, nil)), true
+}
+
+// checkLiteralFits reports an integer literal, possibly negated, that does
+// not fit the sized integer type it is declared with
+func (p *Parser) checkLiteralFits(value Expression, typ *TypeExpression) {
+	if typ == nil {
+		return
+	}
 
-	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
-	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
-		return nil
+	min, max, ok := IntegerRange(typ.Type)
+	if !ok {
+		return
 	}
 
-	lit.Value = value
+	var n *big.Int
+	var tok lexer.Token
+	switch v := value.(type) {
+	case *IntegerLiteral:
+		n, tok = v.Value, v.Token
+	case *PrefixExpression:
+		if lit, ok := v.Right.(*IntegerLiteral); ok && v.Operator == "-" && lit.Value != nil {
+			n, tok = new(big.Int).Neg(lit.Value), v.Token
+		}
+	}
+	if n == nil {
+		return
+	}
 
-	return lit
+	if n.Cmp(min) < 0 || n.Cmp(max) > 0 {
+		p.syntaxError(fmt.Sprintf("Integer literal %s out of range for %s", n, typ.Type), tok)
+	}
+}
+
+// integerRanges holds the bounds of the integer types by name: Int, and
+// Uint8 through Uint256 and Int8 through Int256 in steps of 8 bits. The
+// checker and the interpreter read them through IntegerRange.
+var integerRanges = newIntegerRanges()
+
+// integerRange is the smallest and largest value of an integer type
+type integerRange struct {
+	min, max *big.Int
+}
+
+// newIntegerRanges builds the table of integer type bounds
+func newIntegerRanges() map[string]integerRange {
+	ranges := make(map[string]integerRange)
+
+	for bits := uint(8); bits <= 256; bits += 8 {
+		ranges[fmt.Sprintf("Uint%d", bits)] = integerRange{
+			min: new(big.Int),
+			max: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits), big.NewInt(1)),
+		}
+		ranges[fmt.Sprintf("Int%d", bits)] = integerRange{
+			min: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), bits-1)),
+			max: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits-1), big.NewInt(1)),
+		}
+	}
+
+	// Int is a signed 256-bit integer
+	ranges["Int"] = ranges["Int256"]
+
+	return ranges
+}
+
+// IntegerRange returns the bounds of an integer type name: Int, or a sized
+// type such as Uint8 or Int64
+func IntegerRange(name string) (min, max *big.Int, ok bool) {
+	r, ok := integerRanges[name]
+	if !ok {
+		return nil, nil, false
+	}
+	return new(big.Int).Set(r.min), new(big.Int).Set(r.max), true
 }
 
 // parseStringLiteral parses a string literal
@@ -504,11 +1528,124 @@ func (p *Parser) parseStringLiteral() Expression {
 	return &StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+// parseInterpolatedString parses a string literal with embedded
+// expressions, which the lexer splits into the text around each expression
+// and the tokens of the expressions themselves
+func (p *Parser) parseInterpolatedString() Expression {
+	str := &InterpolatedString{Token: p.curToken, Parts: []string{p.curToken.Literal}}
+
+	for !p.curTokenIs(lexer.StringEnd) {
+		if p.peekTokenIs(lexer.StringMiddle) || p.peekTokenIs(lexer.StringEnd) {
+			p.syntaxError("expected an expression in ${}", p.peekToken)
+			p.nextToken()
+			p.skipInterpolatedString()
+			return nil
+		}
+		p.nextToken()
+
+		interpolation := &Interpolation{Expression: p.parseExpression(LOWEST)}
+		if interpolation.Expression == nil {
+			p.skipInterpolatedString()
+			return nil
+		}
+
+		if p.peekTokenIs(lexer.FormatSpec) {
+			p.nextToken()
+			if interpolation.Format = p.parseFormat(); interpolation.Format == nil {
+				p.skipInterpolatedString()
+				return nil
+			}
+		}
+
+		if !p.peekTokenIs(lexer.StringMiddle) && !p.peekTokenIs(lexer.StringEnd) {
+			p.syntaxError(fmt.Sprintf("expected } to end the interpolation, got %s", p.peekToken.Type), p.peekToken)
+			p.skipInterpolatedString()
+			return nil
+		}
+		p.nextToken()
+
+		str.Interpolations = append(str.Interpolations, interpolation)
+		str.Parts = append(str.Parts, p.curToken.Literal)
+	}
+
+	return str
+}
+
+// skipInterpolatedString skips the rest of an interpolated string with a
+// syntax error, so parsing goes on after its end
+func (p *Parser) skipInterpolatedString() {
+	nested := 0
+	for !p.curTokenIs(lexer.EOF) {
+		switch {
+		case p.curTokenIs(lexer.StringStart):
+			nested++
+		case p.curTokenIs(lexer.StringEnd) && nested == 0:
+			return
+		case p.curTokenIs(lexer.StringEnd):
+			nested--
+		}
+		p.nextToken()
+	}
+}
+
+// parseFormat parses the format of an interpolation, such as 08x: an
+// optional alignment, < or >, an optional 0 to pad with zeros, an optional
+// width and an optional verb, x, X, o or b
+func (p *Parser) parseFormat() *Format {
+	tok := p.curToken
+	format := &Format{Token: tok}
+	spec := tok.Literal
+
+	if strings.HasPrefix(spec, "<") || strings.HasPrefix(spec, ">") {
+		format.Align, spec = spec[:1], spec[1:]
+	}
+	if strings.HasPrefix(spec, "0") {
+		format.Zero, spec = true, spec[1:]
+	}
+
+	digits := 0
+	for digits < len(spec) && '0' <= spec[digits] && spec[digits] <= '9' {
+		digits++
+	}
+	if digits != 0 {
+		width, err := strconv.Atoi(spec[:digits])
+		if err != nil || width > 1000 {
+			p.syntaxError(fmt.Sprintf("format width %s is too large", spec[:digits]), tok)
+			return nil
+		}
+		format.Width, spec = width, spec[digits:]
+	}
+
+	switch spec {
+	case "", "x", "X", "o", "b":
+		format.Verb = spec
+	default:
+		p.syntaxError(fmt.Sprintf("invalid format %q: expected an alignment, width and verb such as >08x", tok.Literal), tok)
+		return nil
+	}
+
+	if format.Zero && format.Align == "<" {
+		p.syntaxError(fmt.Sprintf("invalid format %q: zero padding cannot be aligned left", tok.Literal), tok)
+		return nil
+	}
+	if format.Zero && format.Width == 0 {
+		p.syntaxError(fmt.Sprintf("invalid format %q: zero padding needs a width", tok.Literal), tok)
+		return nil
+	}
+
+	return format
+}
+
 // parseBooleanLiteral parses a boolean literal
 func (p *Parser) parseBooleanLiteral() Expression {
 	return &BooleanLiteral{Token: p.curToken, Value: p.curTokenIs(lexer.TRUE)}
 }
 
+// parseNullLiteral parses the null literal
+func (p *Parser) parseNullLiteral() Expression {
+	return &NullLiteral{Token: p.curToken}
+}
+
 // parsePrefixExpression parses a prefix expression
 func (p *Parser) parsePrefixExpression() Expression {
 	expression := &PrefixExpression{
@@ -532,18 +1669,88 @@ func (p *Parser) parseInfixExpression(left Expression) Expression {
 	}
 
 	precedence := p.curPrecedence()
+	// Exponentiation is right-associative, so 2 ** 3 ** 2 parses as 2 ** (3 ** 2)
+	if p.curTokenIs(lexer.Power) {
+		precedence--
+	}
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
 
 	return expression
 }
 
+// parseAssignExpression parses an assignment expression (e.g., x = 5, balances[to] += amount)
+func (p *Parser) parseAssignExpression(left Expression) Expression {
+	expression := &AssignExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		Left:     left,
+	}
+
+	if !p.checkAssignable(left) {
+		return nil
+	}
+
+	// Assignment is right-associative, so a = b = c parses as a = (b = c)
+	p.nextToken()
+	expression.Right = p.parseExpression(ASSIGNMENT - 1)
+
+	return expression
+}
+
+// parsePostfixExpression parses a postfix increment or decrement (e.g., i++)
+func (p *Parser) parsePostfixExpression(left Expression) Expression {
+	expression := &PostfixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		Left:     left,
+	}
+
+	if !p.checkAssignable(left) {
+		return nil
+	}
+
+	return expression
+}
+
+// checkAssignable reports an error if the expression cannot be assigned to
+func (p *Parser) checkAssignable(target Expression) bool {
+	switch target := target.(type) {
+	case *Identifier, *IndexExpression:
+		return true
+	case *DotExpression:
+		// A safe access may have no object to assign to
+		if !target.Optional {
+			return true
+		}
+	}
+
+	msg := fmt.Sprintf("invalid assignment target for %s", p.curToken.Literal)
+	if target != nil {
+		msg = fmt.Sprintf("invalid assignment target %s for %s", target.String(), p.curToken.Literal)
+	}
+	p.errors = append(p.errors, msg)
+	return false
+}
+
 // parseGroupedExpression parses a grouped expression
 func (p *Parser) parseGroupedExpression() Expression {
+	token := p.curToken
 	p.nextToken()
 
 	exp := p.parseExpression(LOWEST)
 
+	if p.peekTokenIs(lexer.COMMA) {
+		// A comma makes the parenthesized expressions a tuple
+		tuple := &TupleLiteral{Token: token, Elements: []Expression{exp}}
+		for p.peekTokenIs(lexer.COMMA) {
+			p.nextToken()
+			p.nextToken()
+			tuple.Elements = append(tuple.Elements, p.parseExpression(LOWEST))
+		}
+		exp = tuple
+	}
+
 	if !p.expectPeek(lexer.RPAREN) {
 		return nil
 	}
@@ -587,8 +1794,33 @@ func (p *Parser) parseIfExpression() Expression {
 
 // parseCallExpression parses a call expression
 func (p *Parser) parseCallExpression(function Expression) Expression {
-	exp := &CallExpression{Token: p.curToken, Function: function}
-	exp.Arguments = p.parseExpressionList(lexer.RPAREN)
+	exp := &CallExpression{Token: p.curToken, Function: function, Arguments: []Expression{}}
+
+	for !p.peekTokenIs(lexer.RPAREN) {
+		p.nextToken()
+
+		// A named argument such as amount: 5 is passed to the parameter
+		// of that name
+		if p.curTokenIs(lexer.IDENT) && p.peekTokenIs(lexer.COLON) {
+			if exp.Names == nil {
+				exp.Names = make([]*Identifier, len(exp.Arguments))
+			}
+			exp.Names = append(exp.Names, &Identifier{Token: p.curToken, Value: p.curToken.Literal})
+			p.nextToken()
+			p.nextToken()
+		} else if exp.Names != nil {
+			p.syntaxError("positional argument after a named argument", p.curToken)
+			return nil
+		}
+
+		exp.Arguments = append(exp.Arguments, p.parseExpression(LOWEST))
+
+		if !p.peekTokenIs(lexer.RPAREN) && !p.expectPeek(lexer.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken()
+
 	return exp
 }
 
@@ -644,28 +1876,18 @@ func (p *Parser) parseParameters() []*ParameterStatement {
 		return parameters
 	}
 
-	p.nextToken()
-
-	param := &ParameterStatement{
-		Token: p.curToken,
-		Name:  &Identifier{Token: p.curToken, Value: p.curToken.Literal},
-	}
-
-	if !p.expectPeek(lexer.COLON) {
-		return nil
-	}
-
-	p.nextToken()
-	param.Type = p.parseTypeExpression()
-	parameters = append(parameters, param)
-
-	for p.peekTokenIs(lexer.COMMA) {
-		p.nextToken()
+	for {
 		p.nextToken()
 
+		variadic := p.curTokenIs(lexer.Ellipsis)
+		if variadic && !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+
 		param := &ParameterStatement{
-			Token: p.curToken,
-			Name:  &Identifier{Token: p.curToken, Value: p.curToken.Literal},
+			Token:    p.curToken,
+			Name:     &Identifier{Token: p.curToken, Value: p.curToken.Literal},
+			Variadic: variadic,
 		}
 
 		if !p.expectPeek(lexer.COLON) {
@@ -674,26 +1896,158 @@ func (p *Parser) parseParameters() []*ParameterStatement {
 
 		p.nextToken()
 		param.Type = p.parseTypeExpression()
+		param.Default = p.parseParameterDefault()
 		parameters = append(parameters, param)
+
+		if !p.peekTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
 	}
 
 	if !p.expectPeek(lexer.RPAREN) {
 		return nil
 	}
 
+	p.checkParameters(parameters)
 	return parameters
 }
 
-// parseTypeExpression parses a type expression
+// parseParameterDefault parses the default value of a parameter, if it has one
+func (p *Parser) parseParameterDefault() Expression {
+	if !p.peekTokenIs(lexer.ASSIGN) {
+		return nil
+	}
+	p.nextToken()
+	p.nextToken()
+	return p.parseExpression(LOWEST)
+}
+
+// checkParameters reports parameter lists that calls cannot be matched
+// against: parameters with default values must come after the others, and
+// a variadic parameter must come last and cannot have a default value
+func (p *Parser) checkParameters(parameters []*ParameterStatement) {
+	var defaulted *ParameterStatement
+	for idx, param := range parameters {
+		switch {
+		case param.Variadic && idx != len(parameters)-1:
+			p.syntaxError(fmt.Sprintf("variadic parameter %s must be the last parameter", param.Name.Value), param.Token)
+		case param.Variadic && param.Default != nil:
+			p.syntaxError(fmt.Sprintf("variadic parameter %s cannot have a default value", param.Name.Value), param.Token)
+		case param.Default != nil:
+			defaulted = param
+		case defaulted != nil && !param.Variadic:
+			p.syntaxError(fmt.Sprintf("parameter %s without a default value follows %s, which has one",
+				param.Name.Value, defaulted.Name.Value), param.Token)
+		}
+	}
+}
+
+// parseTypeParameters parses the type parameter list of a generic
+// function, starting at its opening <
+func (p *Parser) parseTypeParameters() []*Identifier {
+	params := []*Identifier{}
+
+	for {
+		if !p.expectPeek(lexer.IDENT) {
+			return nil
+		}
+		params = append(params, &Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+		if !p.peekTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(lexer.GT) {
+		return nil
+	}
+
+	return params
+}
+
+// parseTypeExpression parses a type expression, followed by ? if the type
+// is optional
 func (p *Parser) parseTypeExpression() *TypeExpression {
 	expr := &TypeExpression{Token: p.curToken}
 
-	// Basic type (e.g., Int, String, Address)
-	if p.curTokenIs(lexer.IDENT) {
+	switch {
+	case p.curTokenIs(lexer.IDENT) && p.curToken.Literal == "Array" && p.peekTokenIs(lexer.LT):
+		// Array type (e.g., Array<Int>)
+		expr.Type = "Array"
+		p.nextToken()
+
+		p.nextToken()
+		expr.ElementType = p.parseTypeExpression()
+		if expr.ElementType == nil {
+			return nil
+		}
+
+		if !p.expectTypeClose() {
+			return nil
+		}
+	case p.curTokenIs(lexer.IDENT) && p.curToken.Literal == "fn" && p.peekTokenIs(lexer.LPAREN):
+		// Function type (e.g., fn(Int, Int) -> Bool)
+		expr.Type = "fn"
+		p.nextToken()
+
+		expr.ParamTypes = []*TypeExpression{}
+		for !p.peekTokenIs(lexer.RPAREN) {
+			p.nextToken()
+			param := p.parseTypeExpression()
+			if param == nil {
+				return nil
+			}
+			expr.ParamTypes = append(expr.ParamTypes, param)
+
+			if !p.peekTokenIs(lexer.COMMA) {
+				break
+			}
+			p.nextToken()
+		}
+
+		if !p.expectPeek(lexer.RPAREN) {
+			return nil
+		}
+
+		if p.peekTokenIs(lexer.Arrow) {
+			p.nextToken()
+			p.nextToken()
+			expr.ReturnType = p.parseTypeExpression()
+			if expr.ReturnType == nil {
+				return nil
+			}
+		}
+	case p.curTokenIs(lexer.LPAREN):
+		// Tuple type (e.g., (Int, String))
+		expr.Type = "Tuple"
+
+		for !p.peekTokenIs(lexer.RPAREN) {
+			p.nextToken()
+			element := p.parseTypeExpression()
+			if element == nil {
+				return nil
+			}
+			expr.ElementTypes = append(expr.ElementTypes, element)
+
+			if !p.peekTokenIs(lexer.RPAREN) && !p.expectPeek(lexer.COMMA) {
+				return nil
+			}
+		}
+		p.nextToken()
+
+		if len(expr.ElementTypes) < 2 {
+			p.syntaxError("a tuple type has at least two elements, such as (Int, String)", expr.Token)
+			return nil
+		}
+	case p.curTokenIs(lexer.IDENT):
+		// Basic type (e.g., Int, String) or the name of a struct, enum,
+		// contract or type parameter
 		expr.Type = p.curToken.Literal
-	} else if p.curTokenIs(lexer.ADDRESS) {
+	case p.curTokenIs(lexer.ADDRESS):
 		expr.Type = "Address"
-	} else if p.curTokenIs(lexer.MAP) {
+	case p.curTokenIs(lexer.MAP):
 		// Map type (e.g., Map<Address, Int>)
 		expr.Type = "Map"
 
@@ -711,18 +2065,37 @@ func (p *Parser) parseTypeExpression() *TypeExpression {
 		p.nextToken()
 		expr.ValueType = p.parseTypeExpression()
 
-		if !p.expectPeek(lexer.GT) {
+		if !p.expectTypeClose() {
 			return nil
 		}
-	} else {
+	default:
 		msg := fmt.Sprintf("expected type expression, got %s instead", p.curToken.Type)
 		p.errors = append(p.errors, msg)
 		return nil
 	}
 
+	if p.peekTokenIs(lexer.QUESTION) {
+		p.nextToken()
+		expr.Optional = true
+	}
+
 	return expr
 }
 
+// expectTypeClose advances past the > closing a generic type argument list.
+// The lexer reads the >> ending a nested type like Map<Address, Map<Address, Int>>
+// as a shift operator, so that token is split and its second half left as
+// the next token.
+func (p *Parser) expectTypeClose() bool {
+	if p.peekTokenIs(lexer.ShiftRight) {
+		tok := p.peekToken
+		p.curToken = lexer.Token{Type: lexer.GT, Literal: ">", Line: tok.Line, Column: tok.Column}
+		p.peekToken = lexer.Token{Type: lexer.GT, Literal: ">", Line: tok.Line, Column: tok.Column + 1}
+		return true
+	}
+	return p.expectPeek(lexer.GT)
+}
+
 // curTokenIs checks if the current token is of the given type
 func (p *Parser) curTokenIs(t lexer.TokenType) bool {
 	return p.curToken.Type == t
@@ -768,6 +2141,11 @@ func (p *Parser) peekError(t lexer.TokenType) {
 
 // noPrefixParseFnError adds an error for a token that doesn't have a prefix parse function
 func (p *Parser) noPrefixParseFnError(t lexer.TokenType) {
+	if t == lexer.ILLEGAL {
+		// The lexer has already reported the malformed token
+		return
+	}
+
 	msg := fmt.Sprintf("no prefix parse function for %s found", t)
 	p.errors = append(p.errors, msg)
 }
@@ -785,8 +2163,9 @@ func (p *Parser) registerInfix(tokenType lexer.TokenType, fn infixParseFn) {
 // parseDotExpression parses a dot expression (e.g., obj.property)
 func (p *Parser) parseDotExpression(left Expression) Expression {
 	expression := &DotExpression{
-		Token: p.curToken,
-		Left:  left,
+		Token:    p.curToken,
+		Left:     left,
+		Optional: p.curTokenIs(lexer.SafeDot),
 	}
 
 	p.nextToken()
@@ -802,6 +2181,37 @@ func (p *Parser) parseDotExpression(left Expression) Expression {
 	return expression
 }
 
+// parseLiteralParameter parses a parameter of a function literal, whose
+// type may be left out
+func (p *Parser) parseLiteralParameter() *ParameterStatement {
+	variadic := p.curTokenIs(lexer.Ellipsis)
+	if variadic && !p.expectPeek(lexer.IDENT) {
+		return nil
+	}
+
+	param := &ParameterStatement{
+		Token: p.curToken,
+		Name: &Identifier{
+			Token: p.curToken,
+			Value: p.curToken.Literal,
+		},
+		Variadic: variadic,
+	}
+
+	if p.peekTokenIs(lexer.COLON) {
+		p.nextToken()
+		p.nextToken()
+		param.Type = p.parseTypeExpression()
+		if param.Type == nil {
+			return nil
+		}
+	}
+
+	param.Default = p.parseParameterDefault()
+
+	return param
+}
+
 // parseFunctionLiteral parses a function literal expression (e.g., function(a, b) { a + b; })
 func (p *Parser) parseFunctionLiteral() Expression {
 	lit := &FunctionLiteral{Token: p.curToken}
@@ -810,7 +2220,7 @@ func (p *Parser) parseFunctionLiteral() Expression {
 		return nil
 	}
 
-	// Parse parameters as identifiers
+	// Parse parameters as identifiers, each with an optional type
 	parameters := []*ParameterStatement{}
 	
 	// Handle empty parameter list
@@ -818,12 +2228,9 @@ func (p *Parser) parseFunctionLiteral() Expression {
 		p.nextToken() // Skip the left paren
 		
 		// First parameter
-		param := &ParameterStatement{
-			Token: p.curToken,
-			Name: &Identifier{
-				Token: p.curToken,
-				Value: p.curToken.Literal,
-			},
+		param := p.parseLiteralParameter()
+		if param == nil {
+			return nil
 		}
 		parameters = append(parameters, param)
 		
@@ -832,12 +2239,9 @@ func (p *Parser) parseFunctionLiteral() Expression {
 			p.nextToken() // Skip the comma
 			p.nextToken() // Move to the parameter name
 			
-			param := &ParameterStatement{
-				Token: p.curToken,
-				Name: &Identifier{
-					Token: p.curToken,
-					Value: p.curToken.Literal,
-				},
+			param := p.parseLiteralParameter()
+			if param == nil {
+				return nil
 			}
 			parameters = append(parameters, param)
 		}
@@ -847,6 +2251,7 @@ func (p *Parser) parseFunctionLiteral() Expression {
 		return nil
 	}
 	
+	p.checkParameters(parameters)
 	lit.Parameters = parameters
 
 	// Skip return type for now, we'll add it later if needed
@@ -861,7 +2266,7 @@ func (p *Parser) parseFunctionLiteral() Expression {
 		return nil
 	}
 
-	lit.Body = p.parseBlockStatement()
+	lit.Body = p.parseFunctionBody()
 
 	return lit
 }
@@ -895,6 +2300,7 @@ func (p *Parser) parseHashLiteral() Expression {
 	p.nextToken()
 	value := p.parseExpression(LOWEST)
 	hash.Pairs[key] = value
+	hash.Keys = append(hash.Keys, key)
 
 	for p.peekTokenIs(lexer.COMMA) {
 		p.nextToken()
@@ -909,6 +2315,7 @@ func (p *Parser) parseHashLiteral() Expression {
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
 		hash.Pairs[key] = value
+		hash.Keys = append(hash.Keys, key)
 	}
 
 	if !p.expectPeek(lexer.RBRACE) {
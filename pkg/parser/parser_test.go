@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"github.com/Stremax-Team/stremax-lang/pkg/lexer"
+	"math/big"
 	"testing"
 )
 
@@ -153,7 +154,7 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	if !ok {
 		t.Fatalf("exp not *IntegerLiteral. got=%T", stmt.Expression)
 	}
-	if literal.Value != 5 {
+	if literal.Value.Cmp(big.NewInt(5)) != 0 {
 		t.Errorf("literal.Value not %d. got=%d", 5, literal.Value)
 	}
 	if literal.TokenLiteral() != "5" {
@@ -212,7 +213,7 @@ func testIntegerLiteral(t *testing.T, il Expression, value int64) bool {
 		return false
 	}
 
-	if integ.Value != value {
+	if integ.Value.Cmp(big.NewInt(value)) != 0 {
 		t.Errorf("integ.Value not %d. got=%d", value, integ.Value)
 		return false
 	}
@@ -306,3 +307,881 @@ func checkParserErrors(t *testing.T, p *Parser) {
 	}
 	t.FailNow()
 }
+
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 5;", "x = 5"},
+		{"x += 5;", "x += 5"},
+		{"x -= y * 2;", "x -= (y * 2)"},
+		{"x = y = z;", "x = y = z"},
+		{"balances[to] += amount;", "(balances[to]) += amount"},
+		{"msg.value *= 2;", "(msg.value) *= 2"},
+		{"x /= 2;", "x /= 2"},
+		{"i++;", "(i++)"},
+		{"a[0]--;", "((a[0])--)"},
+		{"x = a + b++;", "x = (a + (b++))"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+				len(program.Statements))
+		}
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestInvalidAssignmentTarget(t *testing.T) {
+	tests := []string{
+		"5 = x;",
+		"f() = 1;",
+		"(a + b) += 1;",
+		"3++;",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parser errors for input: %s", input)
+		}
+	}
+}
+
+func TestLoopStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"while (x < 10) { x += 1; }", "while ((x < 10)) { x += 1 }"},
+		{"for (let i = 0; i < n; i += 1) { sum += i; }", "for (let i = 0; (i < n); i += 1) { sum += i }"},
+		{"for (i = 0; i < n; i++) { }", "for (i = 0; (i < n); (i++)) {  }"},
+		{"for (;;) { break; }", "for (; ; ) { break; }"},
+		{"while (true) { continue; }", "while (true) { continue; }"},
+		{"for (item in items) { }", "for (item in items) {  }"},
+		{"for (i, item in items) { }", "for (i, item in items) {  }"},
+		{"for (key, value in {\"a\": 1, \"b\": 2}) { }", "for (key, value in {\"a\": 1, \"b\": 2}) {  }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+				len(program.Statements))
+		}
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestLoopControlOutsideLoop(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"break;", "break statement outside of loop"},
+		{"continue;", "continue statement outside of loop"},
+		{"if (true) { break; }", "break statement outside of loop"},
+		{"while (true) { let f = function() { break; }; }", "break statement outside of loop"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		found := false
+		for _, msg := range p.Errors() {
+			if msg == tt.expectedError {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected error %q for input %q, got %v", tt.expectedError, tt.input, p.Errors())
+		}
+	}
+}
+
+func TestOperatorPrecedence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a + b % c;", "(a + (b % c))"},
+		{"2 ** 3 ** 2;", "(2 ** (3 ** 2))"},
+		{"-2 ** 2;", "(-(2 ** 2))"},
+		{"a * b ** c;", "(a * (b ** c))"},
+		{"a | b ^ c & d;", "(a | (b ^ (c & d)))"},
+		{"a & b == 0;", "((a & b) == 0)"},
+		{"1 << 2 + 3;", "(1 << (2 + 3))"},
+		{"a >> 1 < b;", "((a >> 1) < b)"},
+		{"~a & b;", "((~a) & b)"},
+		{"a | b && c;", "((a | b) && c)"},
+		{"k + 1 in m && ok;", "(((k + 1) in m) && ok)"},
+		{"a[k] in m[j] == true;", "(((a[k]) in (m[j])) == true)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestNestedMapTypeClosing(t *testing.T) {
+	input := `
+	function allowance(allowed: Map<Address, Map<Address, Int>>, owner: Address): Int {
+		return 0;
+	}
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	p.ParseProgram()
+	checkParserErrors(t, p)
+}
+
+func TestStateBlockFields(t *testing.T) {
+	input := `
+	contract Token {
+		state {
+			decimals: Uint8 = 18
+			totalSupply: Uint256;
+			balances: Map<Address, Uint256>,
+			allowed: Map<Address, Map<Address, Uint256>>
+		}
+	}
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	contract, ok := program.Statements[0].(*ContractStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ContractStatement. got=%T", program.Statements[0])
+	}
+
+	expected := []string{
+		"decimals: Uint8 = 18",
+		"totalSupply: Uint256",
+		"balances: Map<Address, Uint256>",
+		"allowed: Map<Address, Map<Address, Uint256>>",
+	}
+
+	fields := contract.StateBlock.Fields
+	if len(fields) != len(expected) {
+		t.Fatalf("wrong number of state fields. got=%d, want=%d", len(fields), len(expected))
+	}
+	for i, want := range expected {
+		if fields[i].String() != want {
+			t.Errorf("fields[%d] wrong. got=%q, want=%q", i, fields[i].String(), want)
+		}
+	}
+}
+
+func TestStructStatementsAndLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"struct Proposal { description: String, votes: Int }", "struct Proposal { description: String, votes: Int }"},
+		{"struct Point {\n x: Int\n y: Int\n}", "struct Point { x: Int, y: Int }"},
+		{"struct Empty {}", "struct Empty {  }"},
+		{"Proposal { description: \"x\", votes: 1 + 2 };", "Proposal { description: \"x\", votes: (1 + 2) }"},
+		{"Point { x: 1, y: 2, };", "Point { x: 1, y: 2 }"},
+		{"Point {};", "Point {  }"},
+		{"p.votes = p.votes + 1;", "(p.votes) = ((p.votes) + 1)"},
+		{"if (x) { y }", "if x { y }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestEnumAndMatchParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"enum Phase { Open, Ended }", "enum Phase { Open, Ended }"},
+		{"enum Phase {\n Open,\n Ended,\n}", "enum Phase { Open, Ended }"},
+		{
+			"enum Phase { Open, Ended }\nmatch (p) { Phase.Open => 1, Phase.Ended => { 2 } }",
+			"enum Phase { Open, Ended }match (p) { (Phase.Open) => { 1 }, (Phase.Ended) => { 2 } }",
+		},
+		{
+			// A wildcard arm covers the remaining variants
+			"enum Phase { Open, Ended }\nmatch (p) { Phase.Open => 1, _ => 2 }",
+			"enum Phase { Open, Ended }match (p) { (Phase.Open) => { 1 }, _ => { 2 } }",
+		},
+		{
+			// Enums may be declared after the match that uses them
+			"match (p) { Phase.Open => 1, Phase.Ended => 2 }\nenum Phase { Open, Ended }",
+			"match (p) { (Phase.Open) => { 1 }, (Phase.Ended) => { 2 } }enum Phase { Open, Ended }",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestEnumDeclarationErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"enum Phase { Open, Open }", "SyntaxError: duplicate variant Open in enum Phase at :1:20"},
+		{"enum Phase {}", "SyntaxError: enum Phase must have at least one variant at :1:6"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		found := false
+		for _, msg := range p.Errors() {
+			if msg == tt.expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected error %q for %q, got=%v", tt.expected, tt.input, p.Errors())
+		}
+	}
+}
+
+func TestLexerErrorsReported(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let s = "abc`, "SyntaxError: Unterminated string literal at :1:9"},
+		{"let s = 1;\nlet t = \"\\q\";", `SyntaxError: Invalid escape sequence \q at :2:10`},
+		{"let s = @;", "SyntaxError: Unexpected character '@' at :1:9"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected first error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+}
+
+func TestIntegerLiteralForms(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0xff", "255"},
+		{"0x00FF_FF", "65535"},
+		{"1_000_000", "1000000"},
+		{"1e18", "1000000000000000000"},
+		{"25e2", "2500"},
+		{"0e500", "0"},
+		{"010", "10"},
+		{"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			"115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ExpressionStatement)
+		literal, ok := stmt.Expression.(*IntegerLiteral)
+		if !ok {
+			t.Fatalf("exp not *IntegerLiteral. got=%T", stmt.Expression)
+		}
+		if literal.Value.String() != tt.expected {
+			t.Errorf("wrong value for %q. expected=%s, got=%s", tt.input, tt.expected, literal.Value)
+		}
+		if literal.String() != tt.input {
+			t.Errorf("literal not kept as written. expected=%s, got=%s", tt.input, literal.String())
+		}
+	}
+}
+
+func TestIntegerLiteralRangeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x: Uint8 = 256;", "SyntaxError: Integer literal 256 out of range for Uint8 at :1:16"},
+		{"let x: Uint8 = -1;", "SyntaxError: Integer literal -1 out of range for Uint8 at :1:16"},
+		{"let x: Int8 = -129;", "SyntaxError: Integer literal -129 out of range for Int8 at :1:15"},
+		{"let x: Uint16 = 0x1_0000;", "SyntaxError: Integer literal 65536 out of range for Uint16 at :1:17"},
+		{"let x: Int = 1e77;", "SyntaxError: Integer literal 100000000000000000000000000000000000000000000000000000000000000000000000000000 out of range for Int at :1:14"},
+		{"contract C {\n state {\n  d: Uint8 = 1e3\n }\n}", "SyntaxError: Integer literal 1000 out of range for Uint8 at :3:14"},
+		{"1e78;", "SyntaxError: Integer literal out of range: 1e78 at :1:1"},
+		{"1e999999999999;", "SyntaxError: Integer literal out of range: 1e999999999999 at :1:1"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected first error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+
+	// Literals that fit their type are accepted
+	for _, input := range []string{"let x: Uint8 = 255;", "let x: Int8 = -128;", "let x: Uint256 = 0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff;"} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		checkParserErrors(t, p)
+	}
+}
+
+func TestAddressExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0x00000000000000000000000000000000000000ab", "0x00000000000000000000000000000000000000ab"},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{"Address(0)", "Address(0)"},
+		{"to != Address(0)", "(to != Address(0))"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	p := New(lexer.New("0x00000000000000000000000000000000000000ab"))
+	program := p.ParseProgram()
+	if _, ok := program.Statements[0].(*ExpressionStatement).Expression.(*AddressLiteral); !ok {
+		t.Errorf("40-digit hex literal is not an *AddressLiteral")
+	}
+}
+
+func TestAddressLiteralErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"let a = 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD;",
+			`SyntaxError: invalid address "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD": wrong checksum, expected 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed at :1:9`,
+		},
+		{
+			"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe;",
+			"SyntaxError: hex literal 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe has 39 digits, but an address has 40; use _ separators to write an integer at :1:1",
+		},
+		{
+			"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed0;",
+			"SyntaxError: hex literal 0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed0 has 41 digits, but an address has 40; use _ separators to write an integer at :1:1",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected first error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+
+	// With separators, literals of those lengths are integers
+	for _, input := range []string{"0x1_00000000_00000000_00000000_00000000_00000000;", "0x0000000_00000000_00000000_00000000_00000001;"} {
+		p := New(lexer.New(input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		if _, ok := program.Statements[0].(*ExpressionStatement).Expression.(*IntegerLiteral); !ok {
+			t.Errorf("%s is not an *IntegerLiteral", input)
+		}
+	}
+}
+
+func TestImportStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "lib/safemath.sx"`, `import "lib/safemath.sx"`},
+		{`import "lib/safemath.sx" as math;`, `import "lib/safemath.sx" as math`},
+		{`import { transfer } from "./token.sx"`, `import { transfer } from "./token.sx"`},
+		{`import { a, b, } from "x.sx";`, `import { a, b } from "x.sx"`},
+		{
+			// from and as remain ordinary identifiers elsewhere
+			`let from = 1; let as = from;`,
+			`let from = 1;let as = from;`,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`import { a } "x.sx"`, "SyntaxError: expected 'from' after import list, got x.sx at :1:14"},
+		{`import {} from "x.sx"`, "SyntaxError: import list must name at least one export at :1:1"},
+		{`import { a } from "x.sx" as y`, "SyntaxError: cannot rename a module when importing selected names at :1:26"},
+		{`import x`, "expected next token to be STRING, got IDENT instead"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected first error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+}
+
+func TestConstAndImmutableDeclarations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const MAX: Int = 10;", "const MAX: Int = 10;"},
+		{"const name = \"Stremax\"", "const name = \"Stremax\";"},
+		{
+			"contract T {\n state {\n  const decimals: Uint8 = 18\n  immutable owner: Address\n }\n}",
+			"contract T state { const decimals: Uint8 = 18; immutable owner: Address } {  }",
+		},
+		{
+			// Immutable fields may be assigned in the constructor
+			"contract T {\n state { immutable owner: Address }\n constructor() { owner = msg.sender; }\n}",
+			"contract T state { immutable owner: Address } { constructor() { owner = (msg.sender) } }",
+		},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"const x;", "expected next token to be =, got ; instead"},
+		{"contract T {\n state { const max: Int }\n}", "SyntaxError: constant max must have an initializer at :2:16"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected first error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+}
+
+func TestGenericAndFunctionTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let xs: Array<Int> = [];", "let xs: Array<Int> = [];"},
+		{"let grid: Array<Array<Uint8>> = [];", "let grid: Array<Array<Uint8>> = [];"},
+		{"let m: Map<String, Array<Int>> = {};", "let m: Map<String, Array<Int>> = {};"},
+		{"let owner: Address? = x;", "let owner: Address? = x;"},
+		{"let m: Map<Address, Int?>? = x;", "let m: Map<Address, Int?>? = x;"},
+		{"let p: fn(Int) -> Bool = x;", "let p: fn(Int) -> Bool = x;"},
+		{"let cb: fn() = x;", "let cb: fn() = x;"},
+		{"let c: fn(Int, fn(Int) -> Int) -> Array<Int> = x;", "let c: fn(Int, fn(Int) -> Int) -> Array<Int> = x;"},
+		{
+			"function max<T>(a: T, b: T): T { return a; }",
+			"function max<T>(a: T, b: T) : T { return a; }",
+		},
+		{
+			"function pair<K, V>(k: K, v: V): Map<K, V> { return {}; }",
+			"function pair<K, V>(k: K, v: V) : Map<K, V> { return {}; }",
+		},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errorTests := []string{
+		"let xs: Array<> = [];",
+		"let f: fn(Int -> Bool = x;",
+		"function f<>(a: Int) { }",
+		"function f<T(a: T) { }",
+	}
+
+	for _, input := range errorTests {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		if len(p.Errors()) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestNullHandlingExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"null;", "null"},
+		{"a ?? b;", "(a ?? b)"},
+		{"a ?? b || c;", "(a ?? (b || c))"},
+		{"a ?? b ?? c;", "((a ?? b) ?? c)"},
+		{"a ?? 1 + 2;", "(a ?? (1 + 2))"},
+		{"a?.b;", "(a?.b)"},
+		{"a?.b?.c;", "((a?.b)?.c)"},
+		{"a?.b(1) ?? 0;", "((a?.b)(1) ?? 0)"},
+		{"x = a?.b ?? 0;", "x = ((a?.b) ?? 0)"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	p := New(lexer.New("a?.b = 1;"))
+	p.ParseProgram()
+	if len(p.Errors()) == 0 || p.Errors()[0] != "invalid assignment target (a?.b) for =" {
+		t.Errorf("expected invalid assignment target error, got=%v", p.Errors())
+	}
+}
+
+func TestErrorHandlingStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"error InsufficientBalance(needed: Int, available: Int)", "error InsufficientBalance(needed: Int, available: Int)"},
+		{"error Paused();", "error Paused()"},
+		{"revert InsufficientBalance(10, balance);", "revert InsufficientBalance(10, balance)"},
+		{"revert \"Paused\"", "revert \"Paused\""},
+		{"assert(total >= 0);", "assert((total >= 0))"},
+		{"assert(total >= 0, \"negative total\")", "assert((total >= 0), \"negative total\")"},
+		{
+			"try vault.withdraw(5) as left { x = left; } catch InsufficientBalance(needed, available) { x = needed; } catch (e) { x = e; }",
+			"try (vault.withdraw)(5) as left { x = left } catch InsufficientBalance(needed, available) { x = needed } catch (e) { x = e }",
+		},
+		{"try Token(100) { } catch { }", "try Token(100) {  } catch {  }"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"try vault.withdraw(5) { }", "SyntaxError: try must have at least one catch clause at :1:1"},
+		{"try vault { } catch { }", "SyntaxError: try must be followed by a contract call, such as try token.transfer(to, 5) { ... } at :1:1"},
+		{"try f() { } catch { } catch E() { }", "SyntaxError: catch clause after a clause that catches every failure is never reached at :1:23"},
+		{"try f() { } catch (a, b) { }", "SyntaxError: a catch clause without an error name takes one parameter at :1:13"},
+		{"try f() { } catch E { }", "expected next token to be (, got { instead"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected first error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+}
+
+func TestTuplesAndDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let pair = (1, \"a\");", "let pair = (1, \"a\");"},
+		{"let x = (1 + 2);", "let x = (1 + 2);"},
+		{"let (q, r) = divmod(7, 2);", "let (q, r) = divmod(7, 2);"},
+		{"const [first, _, third] = items;", "const [first, _, third] = items;"},
+		{"let { x, y: py } = point;", "let { x, y: py } = point;"},
+		{"let (a, b): (Int, String) = pair;", "let (a, b): (Int, String) = pair;"},
+		{"let p: (Int, Array<Int>)? = null;", "let p: (Int, Array<Int>)? = null;"},
+		{"function split(): (Int, Int) { return (1, 2); }", "function split() : (Int, Int) { return (1, 2); }"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x: (Int) = 1;", "SyntaxError: a tuple type has at least two elements, such as (Int, String) at :1:8"},
+		{"let [] = items;", "SyntaxError: a destructuring pattern must bind at least one name at :1:5"},
+		{"let (a, 1) = pair;", "expected next token to be IDENT, got INT instead"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected first error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+}
+
+func TestParameterDeclarations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"function mint(to: Address, amount: Int = 1) { }", "function mint(to: Address, amount: Int = 1) {  }"},
+		{"function sum(...values: Int) { }", "function sum(...values: Int) {  }"},
+		{"function log(level: Int = 0, ...messages: String) { }", "function log(level: Int = 0, ...messages: String) {  }"},
+		{"mint(to: a, amount: 2);", "mint(to: a, amount: 2)"},
+		{"mint(a, amount: 2 * 3);", "mint(a, amount: (2 * 3))"},
+		{"let f = function(x, y = 2) { x + y; };", "let f = function(x, y = 2) { (x + y) };"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"function f(...a: Int, b: Int) { }", "SyntaxError: variadic parameter a must be the last parameter at :1:15"},
+		{"function f(...a: Int = 1) { }", "SyntaxError: variadic parameter a cannot have a default value at :1:15"},
+		{"function f(a: Int = 1, b: Int) { }", "SyntaxError: parameter b without a default value follows a, which has one at :1:24"},
+		{"f(a: 1, 2);", "SyntaxError: positional argument after a named argument at :1:9"},
+		{"error E(...a: Int)", "SyntaxError: error field a cannot be variadic at :1:12"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("expected first error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+}
+
+func TestInterpolatedStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"Transfer of ${amount} to ${to}";`, `"Transfer of ${amount} to ${to}"`},
+		{`"${a + b * 2}";`, `"${(a + (b * 2))}"`},
+		{`"${balance:08x} ${name:<10}";`, `"${balance:08x} ${name:<10}"`},
+		{`"outer ${"inner ${n}"}";`, `"outer ${"inner ${n}"}"`},
+		{`"${f(a, b)}" + "!";`, `("${f(a, b)}" + "!")`},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	// Formats are parsed into their parts
+	p := New(lexer.New(`"${n:>08X}";`))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	str := program.Statements[0].(*ExpressionStatement).Expression.(*InterpolatedString)
+	format := str.Interpolations[0].Format
+	if format.Align != ">" || !format.Zero || format.Width != 8 || format.Verb != "X" {
+		t.Errorf("wrong format. got=%+v", format)
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a ${}";`, "SyntaxError: expected an expression in ${} at :1:6"},
+		{`"a ${1 2}";`, "SyntaxError: expected } to end the interpolation, got INT at :1:8"},
+		{`"a ${n:q}";`, `SyntaxError: invalid format "q": expected an alignment, width and verb such as >08x at :1:7`},
+		{`"a ${n:<08}";`, `SyntaxError: invalid format "<08": zero padding cannot be aligned left at :1:7`},
+		{`"a ${n:0x}";`, `SyntaxError: invalid format "0x": zero padding needs a width at :1:7`},
+		{`"a ${n:99999}";`, "SyntaxError: format width 99999 is too large at :1:7"},
+		{`"a ${n`, "SyntaxError: expected } to end the interpolation, got EOF at :1:7"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 || errors[0] != tt.expected {
+			t.Errorf("expected the error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+}
+
+func TestPragmaStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		strict   bool
+	}{
+		{"pragma strict;\nlet x = 1;", "pragma strict;let x = 1;", true},
+		{"pragma strict\nlet x = 1;", "pragma strict;let x = 1;", true},
+		{"let x = 1;", "let x = 1;", false},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+		if program.Strict() != tt.strict {
+			t.Errorf("wrong strictness for %q. expected=%t", tt.input, tt.strict)
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"pragma loose;", "SyntaxError: unknown pragma loose at :1:8"},
+		{"let x = 1;\npragma strict;", "SyntaxError: pragma strict must come before the other statements of the file at :2:1"},
+		{"pragma 5;", "expected next token to be IDENT, got INT instead"},
+	}
+
+	for _, tt := range errorTests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 || errors[0] != tt.expected {
+			t.Errorf("expected the error %q for %q, got=%v", tt.expected, tt.input, errors)
+		}
+	}
+}